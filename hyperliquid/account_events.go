@@ -0,0 +1,156 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+)
+
+type AccountEventType string
+
+const (
+	AccountEventOrder   AccountEventType = "order"   // Order status change
+	AccountEventFill    AccountEventType = "fill"    // Order fill
+	AccountEventFunding AccountEventType = "funding" // Funding payment
+	AccountEventLedger  AccountEventType = "ledger"  // Deposit, withdrawal, transfer, ...
+)
+
+// AccountEvent is a single event of the account event stream.
+// Exactly one of Order, Fill, Funding or Ledger is set depending on Type.
+type AccountEvent struct {
+	Seq        uint64           // Sequence number, starts at 1 and has no gaps
+	Type       AccountEventType // Type of the event
+	Time       int64            // Time of the event in milliseconds
+	IsSnapshot bool             // True for historical events sent when subscribing
+	Order      *HistoricalOrder
+	Fill       *OrderFill
+	Funding    *WsFunding
+	Ledger     *NonFundingUpdate
+}
+
+// AccountEvents merges the order updates, fills, fundings and ledger updates
+// of a user into a single ordered stream of events.
+//
+//	events, err := ws.SubscribeAccountEvents(address)
+//	for event := range events.C() {
+//		switch event.Type { ... }
+//	}
+type AccountEvents struct {
+	ws     *WebSocketAPI
+	user   string
+	sub    *WsSubscriber
+	ch     chan AccountEvent
+	seq    uint64
+	done   chan struct{}
+	closed chan struct{}
+	once   sync.Once
+}
+
+// SubscribeAccountEvents subscribes to every account stream of the user
+// and returns the merged event stream.
+func (ws *WebSocketAPI) SubscribeAccountEvents(user string) (*AccountEvents, error) {
+	events := &AccountEvents{
+		ws:     ws,
+		user:   user,
+		ch:     make(chan AccountEvent, WS_SUBSCRIBER_BUFFER),
+		done:   make(chan struct{}),
+		closed: make(chan struct{}),
+	}
+	// A single subscriber keeps the events in the order they were received.
+	sub, err := ws.SubscribeMany([]Subscription{
+		{Type: "orderUpdates", User: user},
+		{Type: "userFills", User: user},
+		{Type: "userFundings", User: user},
+		{Type: "userNonFundingLedgerUpdates", User: user},
+	}, SubscriberOptions{})
+	if err != nil {
+		return nil, err
+	}
+	events.sub = sub
+	go events.run()
+	return events, nil
+}
+
+// C returns the channel the events are delivered on.
+// The channel is closed after Close.
+func (events *AccountEvents) C() <-chan AccountEvent {
+	return events.ch
+}
+
+// User returns the address of the user the events belong to.
+func (events *AccountEvents) User() string {
+	return events.user
+}
+
+// Close unsubscribes from the account streams and closes the event channel.
+func (events *AccountEvents) Close() error {
+	var err error
+	events.once.Do(func() {
+		close(events.done)
+		err = events.sub.Unsubscribe()
+		<-events.closed
+	})
+	return err
+}
+
+// run converts the subscription messages to events until the subscription is closed.
+func (events *AccountEvents) run() {
+	defer close(events.closed)
+	defer close(events.ch)
+	for msg := range events.sub.C() {
+		parsed, err := parseAccountEvents(&msg)
+		if err != nil {
+			events.ws.debug("Error parsing account event: %s", err)
+			continue
+		}
+		for _, event := range parsed {
+			events.seq++
+			event.Seq = events.seq
+			select {
+			case events.ch <- event:
+			case <-events.done:
+			}
+		}
+	}
+}
+
+// parseAccountEvents converts a websocket message to account events sorted by time.
+func parseAccountEvents(msg *WsMessage) ([]AccountEvent, error) {
+	var events []AccountEvent
+	switch msg.Channel {
+	case "orderUpdates":
+		var data []HistoricalOrder
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			return nil, err
+		}
+		for i := range data {
+			events = append(events, AccountEvent{Type: AccountEventOrder, Time: data[i].StatusTimestamp, Order: &data[i]})
+		}
+	case "userFills":
+		var data WsUserFills
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			return nil, err
+		}
+		for i := range data.Fills {
+			events = append(events, AccountEvent{Type: AccountEventFill, Time: data.Fills[i].Time, IsSnapshot: data.IsSnapshot, Fill: &data.Fills[i]})
+		}
+	case "userFundings":
+		var data WsUserFundings
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			return nil, err
+		}
+		for i := range data.Fundings {
+			events = append(events, AccountEvent{Type: AccountEventFunding, Time: data.Fundings[i].Time, IsSnapshot: data.IsSnapshot, Funding: &data.Fundings[i]})
+		}
+	case "userNonFundingLedgerUpdates":
+		var data WsUserNonFundingLedgerUpdates
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			return nil, err
+		}
+		for i := range data.NonFundingLedgerUpdates {
+			events = append(events, AccountEvent{Type: AccountEventLedger, Time: data.NonFundingLedgerUpdates[i].Time, IsSnapshot: data.IsSnapshot, Ledger: &data.NonFundingLedgerUpdates[i]})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time < events[j].Time })
+	return events, nil
+}
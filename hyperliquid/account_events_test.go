@@ -0,0 +1,74 @@
+package hyperliquid
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestAccountEvents_MergedStream(t *testing.T) {
+	user := "0x0000000000000000000000000000000000000001"
+	subscribed := make(chan *websocket.Conn, 4)
+	server := newTestWsServer(t, func(conn *websocket.Conn, req WsRequest) any {
+		if req.Method == "subscribe" {
+			subscribed <- conn
+		}
+		return nil
+	})
+	ws := GetTestWebSocketAPI(t, server)
+	events, err := ws.SubscribeAccountEvents(user)
+	if err != nil {
+		t.Fatalf("SubscribeAccountEvents() error = %v", err)
+	}
+	defer events.Close()
+	var conn *websocket.Conn
+	for i := 0; i < 4; i++ {
+		conn = <-subscribed
+	}
+
+	messages := []map[string]any{
+		{"channel": "userFills", "data": map[string]any{"user": user, "fills": []any{
+			map[string]any{"coin": "ETH", "px": "2000", "sz": "1", "tid": 2, "time": 20},
+			map[string]any{"coin": "ETH", "px": "2000", "sz": "1", "tid": 1, "time": 10},
+		}}},
+		{"channel": "orderUpdates", "data": []any{
+			map[string]any{"order": map[string]any{"coin": "ETH", "oid": 7, "side": "B", "timestamp": 5}, "status": "filled", "statusTimestamp": 30},
+		}},
+		{"channel": "userFundings", "data": map[string]any{"user": user, "fundings": []any{
+			map[string]any{"time": 40, "coin": "ETH", "usdc": "-0.1", "szi": "1", "fundingRate": "0.0001"},
+		}}},
+		{"channel": "userNonFundingLedgerUpdates", "data": map[string]any{"user": user, "nonFundingLedgerUpdates": []any{
+			map[string]any{"time": 50, "hash": "0x1", "delta": map[string]any{"type": "deposit", "usdc": "100"}},
+		}}},
+	}
+	for _, msg := range messages {
+		if err := conn.WriteJSON(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []struct {
+		eventType AccountEventType
+		time      int64
+	}{
+		{AccountEventFill, 10},
+		{AccountEventFill, 20},
+		{AccountEventOrder, 30},
+		{AccountEventFunding, 40},
+		{AccountEventLedger, 50},
+	}
+	for i, want := range expected {
+		select {
+		case event := <-events.C():
+			if event.Seq != uint64(i+1) {
+				t.Errorf("event.Seq = %v, want %v", event.Seq, i+1)
+			}
+			if event.Type != want.eventType || event.Time != want.time {
+				t.Errorf("event = %+v, want %s at %d", event, want.eventType, want.time)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for event %d", i+1)
+		}
+	}
+}
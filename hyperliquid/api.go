@@ -3,10 +3,14 @@ package hyperliquid
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 )
 
 // API implementation general error
+// Code is the HTTP status code of the response if the request failed with one, 0 otherwise.
 type APIError struct {
+	Code    int
 	Message string
 }
 
@@ -14,6 +18,128 @@ func (e APIError) Error() string {
 	return e.Message
 }
 
+// ErrExchangeMaintenance is returned when the API reports that the exchange is down for maintenance.
+// Requests should be paused rather than retried until GetExchangeStatus reports a normal status.
+//
+//	if errors.Is(err, hyperliquid.ErrExchangeMaintenance) { ... }
+var ErrExchangeMaintenance = APIError{Message: "Exchange is under maintenance"}
+
+// InsufficientLiquidityError is returned when the order book is too thin to fill
+// the requested size within the allowed slippage.
+//
+//	var liquidityErr hyperliquid.InsufficientLiquidityError
+//	if errors.As(err, &liquidityErr) { ... }
+type InsufficientLiquidityError struct {
+	Coin      string
+	Size      float64 // requested size
+	Available float64 // size available within the slippage bound
+	BoundPx   float64 // worst price allowed by the slippage
+}
+
+func (e InsufficientLiquidityError) Error() string {
+	return fmt.Sprintf("Insufficient liquidity for %s: %g available within %g, requested %g", e.Coin, e.Available, e.BoundPx, e.Size)
+}
+
+// ErrOrderTooSmall is matched by the OrderTooSmallError returned when an order is below
+// the minimum order value or the minimum size of its asset.
+//
+//	if errors.Is(err, hyperliquid.ErrOrderTooSmall) { ... }
+var ErrOrderTooSmall = APIError{Message: "Order is too small"}
+
+// OrderTooSmallError is returned before signing an order that the exchange would reject
+// because its value is below MinNotional or its size rounds below the lot size MinSz.
+type OrderTooSmallError struct {
+	Coin        string
+	Sz          float64
+	Notional    float64 // value of the order at its limit price
+	MinSz       float64
+	MinNotional float64
+}
+
+func (e OrderTooSmallError) Error() string {
+	if e.Sz < e.MinSz {
+		return fmt.Sprintf("Order is too small: %s size %v is below the minimum size %v", e.Coin, e.Sz, e.MinSz)
+	}
+	return fmt.Sprintf("Order is too small: %s order value %v is below the minimum %v", e.Coin, e.Notional, e.MinNotional)
+}
+
+func (e OrderTooSmallError) Is(target error) bool {
+	return target == ErrOrderTooSmall
+}
+
+// The typed errors below wrap an APIError, so errors.As(err, &APIError{}) matches every error of the API.
+
+// RateLimitError is returned when the request was rejected by the rate limits of the API,
+// by its IP limits (HTTP 429) or by the limits of the address.
+// RetryAfter is the delay requested by the server, 0 if unknown.
+type RateLimitError struct {
+	APIError
+	RetryAfter time.Duration
+}
+
+func (e RateLimitError) Unwrap() error {
+	return e.APIError
+}
+
+// SignatureError is returned when the exchange rejects the signature of an action,
+// e.g. when the signer is not the account or one of its approved API wallets.
+type SignatureError struct {
+	APIError
+}
+
+func (e SignatureError) Unwrap() error {
+	return e.APIError
+}
+
+// InsufficientMarginError is returned when an order requires more margin, or spot balance,
+// than the account has available.
+type InsufficientMarginError struct {
+	APIError
+}
+
+func (e InsufficientMarginError) Unwrap() error {
+	return e.APIError
+}
+
+// OrderRejectedError is returned when the exchange rejects an order it received, Reason is the message of the exchange.
+// It wraps the typed error of the reason, e.g. an InsufficientMarginError or a RateLimitError, an APIError otherwise.
+//
+//	var rejected hyperliquid.OrderRejectedError
+//	if errors.As(err, &rejected) { ... }
+//	if errors.As(err, &hyperliquid.InsufficientMarginError{}) { ... }
+type OrderRejectedError struct {
+	Reason string
+	err    error
+}
+
+func (e OrderRejectedError) Error() string {
+	return e.Reason
+}
+
+func (e OrderRejectedError) Unwrap() error {
+	return e.err
+}
+
+// newOrderRejectedError creates an OrderRejectedError for the error status of an order.
+func newOrderRejectedError(reason string) OrderRejectedError {
+	return OrderRejectedError{Reason: reason, err: responseError(reason)}
+}
+
+// responseError converts an error message of the API to a typed error, APIError if the message is not recognized.
+func responseError(message string) error {
+	err := APIError{Message: message}
+	lower := strings.ToLower(message)
+	switch {
+	case strings.Contains(lower, "too many") || strings.Contains(lower, "rate limit"):
+		return RateLimitError{APIError: err}
+	case strings.Contains(lower, "signature") || (strings.Contains(lower, "wallet") && strings.Contains(lower, "does not exist")):
+		return SignatureError{APIError: err}
+	case strings.Contains(lower, "insufficient margin") || strings.Contains(lower, "insufficient spot balance"):
+		return InsufficientMarginError{APIError: err}
+	}
+	return err
+}
+
 // IAPIService is an interface for making requests to the API Service.
 //
 // It has a Request method that takes a path and a payload and returns a byte array and an error.
@@ -59,7 +185,8 @@ func MakeUniversalRequest[T any](api IAPIService, request any) (*T, error) {
 	}
 
 	if errResult["status"] == "err" {
-		return nil, APIError{Message: errResult["response"].(string)}
+		message, _ := errResult["response"].(string)
+		return nil, responseError(message)
 	}
 
 	return nil, APIError{Message: fmt.Sprintf("Unexpected response: %v", errResult)}
@@ -7,7 +7,9 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -46,6 +48,32 @@ type Client struct {
 	Logger         *log.Logger  // Logger for debug messages
 	role           Role         // Role of the client,
 	vaultAddress   string       // Vault address
+	retryPolicy    *RetryPolicy // Retries of the failed requests, nil to never retry
+	rateLimiter    *RateLimiter // Weight limit of the requests, nil for no limit
+}
+
+// RetryPolicy retries the requests failing with a network error, HTTP 429 or a server error.
+// Maintenance errors are not retried. Exchange requests are signed with a nonce,
+// so a retried action that already reached the exchange is rejected instead of executed twice.
+type RetryPolicy struct {
+	MaxAttempts int           // Total number of attempts, including the first one
+	MinBackoff  time.Duration // Delay before the first retry, doubled after every attempt (RETRY_MIN_BACKOFF if 0)
+	MaxBackoff  time.Duration // Maximum delay between two attempts (RETRY_MAX_BACKOFF if 0)
+}
+
+// backoff returns the delay after the given failed attempt.
+func (policy *RetryPolicy) backoff(attempt int) time.Duration {
+	backoff, maxBackoff := policy.MinBackoff, policy.MaxBackoff
+	if backoff <= 0 {
+		backoff = RETRY_MIN_BACKOFF
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = RETRY_MAX_BACKOFF
+	}
+	for i := 1; i < attempt && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxBackoff)
 }
 
 // Returns the private key manager connected to the API.
@@ -138,6 +166,33 @@ func (client *Client) IsMainnet() bool {
 	return client.isMainnet
 }
 
+// SetHTTPClient sets the HTTP client sending the requests, e.g. with a proxy or mTLS transport.
+// Pass nil to use http.DefaultClient.
+func (client *Client) SetHTTPClient(httpClient *http.Client) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	client.httpClient = httpClient
+}
+
+// HTTPClient returns the HTTP client sending the requests.
+func (client *Client) HTTPClient() *http.Client {
+	return client.httpClient
+}
+
+// SetTransport sets the RoundTripper of the HTTP client, e.g. for a proxy or custom DNS resolution.
+// The HTTP client is copied, the client given to SetHTTPClient or http.DefaultClient is not modified.
+func (client *Client) SetTransport(transport http.RoundTripper) {
+	httpClient := *client.httpClient
+	httpClient.Transport = transport
+	client.httpClient = &httpClient
+}
+
+// SetRetryPolicy sets the retries of the failed requests, pass nil to never retry.
+func (client *Client) SetRetryPolicy(policy *RetryPolicy) {
+	client.retryPolicy = policy
+}
+
 // SetDebugActive enables debug mode.
 func (client *Client) SetDebugActive() {
 	client.Debug = true
@@ -159,35 +214,77 @@ func (client *Client) Request(endpoint string, payload any) ([]byte, error) {
 		client.debug("Error json.Marshal: %s", err)
 		return nil, err
 	}
+	policy := client.retryPolicy
+	for attempt := 1; ; attempt++ {
+		if err := client.waitRateLimit(endpoint, payloadBytes); err != nil {
+			return nil, err
+		}
+		data, retryable, err := client.send(url, payloadBytes)
+		if err == nil || !retryable || policy == nil || attempt >= policy.MaxAttempts {
+			return data, err
+		}
+		backoff := policy.backoff(attempt)
+		if rateLimitErr, ok := err.(RateLimitError); ok {
+			backoff = max(backoff, rateLimitErr.RetryAfter)
+		}
+		client.debug("Retrying request to %s in %s after attempt %d: %s", url, backoff, attempt, err)
+		time.Sleep(backoff)
+	}
+}
+
+// send posts the payload once. Network errors, rate limits and server errors are retryable.
+func (client *Client) send(url string, payloadBytes []byte) ([]byte, bool, error) {
 	request, err := http.NewRequest("POST", url, bytes.NewBuffer(payloadBytes))
 	if err != nil {
 		client.debug("Error http.NewRequest: %s", err)
-		return nil, err
+		return nil, false, err
 	}
 	request.Header.Set("Content-Type", "application/json")
 	response, err := client.httpClient.Do(request)
 	if err != nil {
 		client.debug("Error client.httpClient.Do: %s", err)
-		return nil, err
+		return nil, true, err
 	}
+	defer response.Body.Close()
 	data, err := io.ReadAll(response.Body)
 	if err != nil {
-		return nil, err
+		return nil, true, err
 	}
-	defer func() {
-		cerr := response.Body.Close()
-		// Only overwrite the retured error if the original error was nil and an
-		// error occurred while closing the body.
-		if err == nil && cerr != nil {
-			err = cerr
-		}
-	}()
 	client.debug("response: %#v", response)
 	client.debug("response body: %s", string(data))
 	client.debug("response status code: %d", response.StatusCode)
 	if response.StatusCode >= http.StatusBadRequest {
+		if isMaintenanceResponse(response.StatusCode, data) {
+			return nil, false, fmt.Errorf("%w: HTTP %d: %s", ErrExchangeMaintenance, response.StatusCode, data)
+		}
+		message := fmt.Sprintf("HTTP %d: %s", response.StatusCode, data)
+		if response.StatusCode == http.StatusTooManyRequests {
+			return nil, true, RateLimitError{
+				APIError:   APIError{Code: response.StatusCode, Message: message},
+				RetryAfter: parseRetryAfter(response.Header.Get("Retry-After")),
+			}
+		}
 		// If the status code is 400 or greater, return an error
-		return nil, APIError{Message: fmt.Sprintf("HTTP %d: %s", response.StatusCode, data)}
+		return nil, response.StatusCode >= http.StatusInternalServerError, APIError{Code: response.StatusCode, Message: message}
+	}
+	return data, false, nil
+}
+
+// parseRetryAfter parses the Retry-After header, in seconds or as an HTTP date. It returns 0 if it is not set.
+func parseRetryAfter(value string) time.Duration {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(0, time.Until(date))
+	}
+	return 0
+}
+
+// isMaintenanceResponse reports whether an error response means the exchange is down for maintenance.
+func isMaintenanceResponse(statusCode int, data []byte) bool {
+	if statusCode == http.StatusServiceUnavailable {
+		return true
 	}
-	return data, nil
+	return strings.Contains(strings.ToLower(string(data)), "maintenance")
 }
@@ -0,0 +1,153 @@
+package hyperliquid
+
+import (
+	"strings"
+	"sync"
+)
+
+// CloidRegistry maps client order ids to the order ids assigned by the exchange.
+// The mappings are recorded from the order responses of an ExchangeAPI (see SetCloidRegistry)
+// and from the orderUpdates stream (see Watch), so orders placed by cloid can be canceled or modified by oid.
+// It is safe for concurrent use. Mappings are kept until Forget, including the ones of final orders.
+//
+//	registry := hyperliquid.NewCloidRegistry()
+//	api.SetCloidRegistry(registry)
+//	registry.Watch(ws, address)
+//	defer registry.Close()
+//	oid, ok := registry.Oid(cloid)
+type CloidRegistry struct {
+	oids   map[string]int // Lowercase cloid to oid
+	cloids map[int]string
+	subs   []*WsSubscriber
+	wg     sync.WaitGroup
+	mu     sync.RWMutex
+}
+
+// NewCloidRegistry creates an empty CloidRegistry.
+func NewCloidRegistry() *CloidRegistry {
+	return &CloidRegistry{
+		oids:   make(map[string]int),
+		cloids: make(map[int]string),
+	}
+}
+
+// SetCloidRegistry records the cloid of the orders placed by api in registry.
+// Pass nil to stop recording.
+func (api *ExchangeAPI) SetCloidRegistry(registry *CloidRegistry) {
+	api.cloidRegistry = registry
+}
+
+// CloidRegistry returns the registry the orders are recorded in, nil if there is none.
+func (api *ExchangeAPI) CloidRegistry() *CloidRegistry {
+	return api.cloidRegistry
+}
+
+// Record maps cloid to oid, empty cloids and zero oids are ignored.
+func (registry *CloidRegistry) Record(cloid string, oid int) {
+	if cloid == "" || oid == 0 {
+		return
+	}
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.oids[strings.ToLower(cloid)] = oid
+	registry.cloids[oid] = cloid
+}
+
+// RecordResponse records the resting and filled orders of the response to the placement of requests.
+// The statuses of the response are in the same order as requests.
+func (registry *CloidRegistry) RecordResponse(requests []OrderRequest, res *OrderResponse) {
+	if res == nil {
+		return
+	}
+	for i, status := range res.Response.Data.Statuses {
+		cloid := status.Resting.Cloid
+		if cloid == "" {
+			cloid = status.Filled.Cloid
+		}
+		if cloid == "" && i < len(requests) {
+			cloid = requests[i].Cloid
+		}
+		registry.Record(cloid, status.OrderID())
+	}
+}
+
+// RecordOrder records the order of an order update.
+func (registry *CloidRegistry) RecordOrder(order *HistoricalOrder) {
+	registry.Record(order.Order.Cloid, int(order.Order.Oid))
+}
+
+// Oid returns the order id of cloid.
+func (registry *CloidRegistry) Oid(cloid string) (int, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	oid, ok := registry.oids[strings.ToLower(cloid)]
+	return oid, ok
+}
+
+// Cloid returns the client order id of oid.
+func (registry *CloidRegistry) Cloid(oid int) (string, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	cloid, ok := registry.cloids[oid]
+	return cloid, ok
+}
+
+// Forget removes the mapping of cloid.
+func (registry *CloidRegistry) Forget(cloid string) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	key := strings.ToLower(cloid)
+	if oid, ok := registry.oids[key]; ok {
+		delete(registry.cloids, oid)
+		delete(registry.oids, key)
+	}
+}
+
+// Len returns the number of mappings.
+func (registry *CloidRegistry) Len() int {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	return len(registry.oids)
+}
+
+// Watch records the orders of the orderUpdates stream of user on ws, which must be connected,
+// until Close. This includes the orders placed outside of the ExchangeAPI, e.g. from the frontend.
+func (registry *CloidRegistry) Watch(ws *WebSocketAPI, user string) error {
+	sub, err := ws.Subscribe(Subscription{Type: "orderUpdates", User: user})
+	if err != nil {
+		return err
+	}
+	registry.mu.Lock()
+	registry.subs = append(registry.subs, sub)
+	registry.mu.Unlock()
+	registry.wg.Add(1)
+	go func() {
+		defer registry.wg.Done()
+		for msg := range sub.C() {
+			events, err := parseAccountEvents(&msg)
+			if err != nil {
+				ws.debug("Error parsing order updates: %s", err)
+				continue
+			}
+			for _, event := range events {
+				if event.Order != nil {
+					registry.RecordOrder(event.Order)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Close stops watching the order updates, the mappings are kept.
+func (registry *CloidRegistry) Close() error {
+	registry.mu.Lock()
+	subs := registry.subs
+	registry.subs = nil
+	registry.mu.Unlock()
+	for _, sub := range subs {
+		sub.Unsubscribe()
+	}
+	registry.wg.Wait()
+	return nil
+}
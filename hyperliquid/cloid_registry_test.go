@@ -0,0 +1,79 @@
+package hyperliquid
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestCloidRegistry(t *testing.T) {
+	cloids := []string{"0x00000000000000000000000000000001", "0x00000000000000000000000000000002", "0x00000000000000000000000000000003"}
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		statuses := []any{
+			map[string]any{"resting": map[string]any{"oid": 10}},
+			map[string]any{"filled": map[string]any{"oid": 11, "avgPx": "2000", "totalSz": "0.01", "cloid": cloids[1]}},
+			map[string]any{"error": "Order must have minimum value of $10."},
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "order", "data": map[string]any{"statuses": statuses}}}
+	})
+	registry := NewCloidRegistry()
+	exchangeAPI.SetCloidRegistry(registry)
+	limit := OrderType{Limit: &LimitOrderType{Tif: TifGtc}}
+	requests := []OrderRequest{
+		{Coin: "ETH", IsBuy: true, Sz: 0.01, LimitPx: 1900, OrderType: limit, Cloid: cloids[0]},
+		{Coin: "ETH", IsBuy: true, Sz: 0.01, LimitPx: 2100, OrderType: limit, Cloid: cloids[1]},
+		{Coin: "ETH", IsBuy: true, Sz: 0.01, LimitPx: 2000, OrderType: limit, Cloid: cloids[2]},
+	}
+	if _, err := exchangeAPI.BulkOrders(requests, GroupingNa); err != nil {
+		t.Fatalf("BulkOrders() error = %v", err)
+	}
+	if oid, ok := registry.Oid(cloids[0]); !ok || oid != 10 {
+		t.Errorf("Oid(%s) = %d, %v, want 10", cloids[0], oid, ok)
+	}
+	if oid, ok := registry.Oid("0X00000000000000000000000000000002"); !ok || oid != 11 {
+		t.Errorf("Oid() = %d, %v, want 11 whatever the case of the cloid", oid, ok)
+	}
+	if _, ok := registry.Oid(cloids[2]); ok {
+		t.Errorf("Oid(%s) expected no oid for a rejected order", cloids[2])
+	}
+	if cloid, ok := registry.Cloid(10); !ok || cloid != cloids[0] {
+		t.Errorf("Cloid(10) = %s, %v, want %s", cloid, ok, cloids[0])
+	}
+	registry.Forget(cloids[0])
+	if _, ok := registry.Cloid(10); ok || registry.Len() != 1 {
+		t.Errorf("Forget() expected the mapping of oid 10 to be removed")
+	}
+
+	subscribed := make(chan *websocket.Conn, 1)
+	server := newTestWsServer(t, func(conn *websocket.Conn, req WsRequest) any {
+		if req.Method == "subscribe" {
+			subscribed <- conn
+		}
+		return nil
+	})
+	ws := GetTestWebSocketAPI(t, server)
+	if err := registry.Watch(ws, "0x1"); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	conn := <-subscribed
+	conn.WriteJSON(map[string]any{"channel": "orderUpdates", "data": []any{
+		map[string]any{"order": map[string]any{"coin": "ETH", "oid": 12, "cloid": cloids[2], "side": "B"}, "status": "open", "statusTimestamp": 1},
+	}})
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if oid, ok := registry.Oid(cloids[2]); ok && oid == 12 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Oid(%s) timed out waiting for the order update", cloids[2])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := registry.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if registry.Len() != 2 {
+		t.Errorf("Len() = %d, want the mappings kept after Close", registry.Len())
+	}
+}
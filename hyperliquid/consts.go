@@ -1,19 +1,56 @@
 package hyperliquid
 
+import "time"
+
 const GLOBAL_DEBUG = false // Default debug that is used in all tests
 
 // API constants
 const MAINNET_API_URL = "https://api.hyperliquid.xyz"
 const TESTNET_API_URL = "https://api.hyperliquid-testnet.xyz"
+const MAINNET_WS_URL = "wss://api.hyperliquid.xyz/ws"
+const TESTNET_WS_URL = "wss://api.hyperliquid-testnet.xyz/ws"
+const MAINNET_EXPLORER_URL = "https://rpc.hyperliquid.xyz"
+const TESTNET_EXPLORER_URL = "https://rpc.hyperliquid-testnet.xyz"
+
+// WebSocket constants
+const WS_PING_INTERVAL = 50 * time.Second // Server closes idle connections after 60s
+const WS_POST_TIMEOUT = 30 * time.Second  // Default timeout for post requests
+const WS_SUBSCRIBER_BUFFER = 100          // Default buffer size of a subscriber channel
+const WS_RECONNECT_MIN_DELAY = 1 * time.Second
+const WS_RECONNECT_MAX_DELAY = 30 * time.Second
+const WS_BACKFILL_DEDUP_SIZE = 10000 // Number of fill/order/ledger ids remembered for deduplication
+
+// Client constants
+const RETRY_MIN_BACKOFF = 500 * time.Millisecond // Default delay before the first retry of a failed request
+const RETRY_MAX_BACKOFF = 10 * time.Second       // Default maximum delay between two retries
+const RATE_LIMIT_WEIGHT_PER_MINUTE = 1200        // Weight of the requests allowed per minute and per IP address
+const RATE_LIMIT_BATCH_SIZE = 40                 // Exchange actions weigh 1 more for every 40 orders, cancels or modifies
+const INFO_REQUEST_WEIGHT = 20                   // Weight of most info requests
+const EXPLORER_REQUEST_WEIGHT = 40               // Weight of the explorer requests
+
+// Info constants
+const USER_FILLS_PAGE_SIZE = 2000    // Maximum number of fills returned by userFillsByTime
+const PERP_DEX_ASSET_OFFSET = 100000 // Asset ids of builder-deployed perps start at this offset
+const PERP_DEX_ASSET_STRIDE = 10000  // Range of asset ids reserved for each builder-deployed perp dex
 
 // Execution constants
-const DEFAULT_SLIPPAGE = 0.005 // 0.5% default slippage
-const SPOT_MAX_DECIMALS = 8    // Default decimals for spot
-const PERP_MAX_DECIMALS = 6    // Default decimals for perp
-var USDC_SZ_DECIMALS = 2       // Default decimals for usdc that is used for withdraw
+const DEFAULT_SLIPPAGE = 0.005                     // 0.5% default slippage
+const DEFAULT_LEVERAGE = 20                        // Leverage of an asset the user never set the leverage of, capped at its max leverage
+const MIN_ORDER_NOTIONAL = 10.0                    // Minimum value in USDC of an order that is not reduce only
+const KILL_SWITCH_CANCEL_DELAY = 10 * time.Second  // Delay of the scheduleCancel armed by KillSwitch, the 5s minimum of the exchange plus a margin for signing and the round trip
+const SCHEDULE_CANCEL_MIN_DELAY = 10 * time.Second // Earliest scheduleCancel of OrderExpiryScheduler from now, so that it is still 5s ahead when the request arrives
+const SPOT_MAX_DECIMALS = 8                        // Default decimals for spot
+const PERP_MAX_DECIMALS = 6                        // Default decimals for perp
+var USDC_SZ_DECIMALS = 2                           // Default decimals for usdc that is used for withdraw
+const ORDER_STATUS_POLL_INTERVAL = 1 * time.Second // Interval of the orderStatus polling of WaitForOrder
 
 // Signing constants
 const HYPERLIQUID_CHAIN_ID = 1337
 const VERIFYING_CONTRACT = "0x0000000000000000000000000000000000000000"
 const ARBITRUM_CHAIN_ID = 42161
 const ARBITRUM_TESTNET_CHAIN_ID = 421614
+const MAINNET_SIGNATURE_CHAIN_ID = "0xa4b1"  // Default signatureChainId of user signed actions on mainnet (Arbitrum)
+const TESTNET_SIGNATURE_CHAIN_ID = "0x66eee" // Default signatureChainId of user signed actions on testnet (Arbitrum Sepolia)
+
+// Withdraw constants
+const WITHDRAW_FEE = 1.0 // USDC fee deducted by the bridge from every withdrawal
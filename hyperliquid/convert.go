@@ -61,8 +61,31 @@ func OrderWiresToOrderAction(orders []OrderWire, grouping Grouping) PlaceOrderAc
 	}
 }
 
+// OrderRequestsBuilder returns the builder of the order requests of a PlaceOrderAction.
+// The builder is set for the whole action, so all requests must have the same builder (or none).
+func OrderRequestsBuilder(requests []OrderRequest) (*Builder, error) {
+	var builder *Builder
+	for i, req := range requests {
+		if i > 0 && !sameBuilder(builder, req.Builder) {
+			return nil, APIError{Message: "All orders must have the same builder"}
+		}
+		builder = req.Builder
+	}
+	if builder == nil {
+		return nil, nil
+	}
+	return &Builder{Address: strings.ToLower(builder.Address), Fee: builder.Fee}, nil
+}
+
+func sameBuilder(a, b *Builder) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return strings.EqualFold(a.Address, b.Address) && a.Fee == b.Fee
+}
+
 func (req *OrderRequest) isSpot() bool {
-	return strings.ContainsAny(req.Coin, "@-")
+	return req.spotAsset != nil || strings.ContainsAny(req.Coin, "@-")
 }
 
 // ToWire (OrderRequest) converts an OrderRequest to an OrderWire using the provided metadata.
@@ -201,6 +224,24 @@ func PriceToWire(x float64, maxDecimals, szDecimals int) string {
 		return strconv.FormatInt(int64(x), 10)
 	}
 
+	allowedDecimals := priceDecimals(x, maxDecimals, szDecimals)
+
+	// Round the price to allowedDecimals decimals.
+	factor := pow10(allowedDecimals)
+	rounded := math.Round(x*factor) / factor
+
+	// Format the number with fixed precision.
+	s := strconv.FormatFloat(rounded, 'f', allowedDecimals, 64)
+	// Only trim trailing zeros if the formatted string contains a decimal point.
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(s, "0")
+		s = strings.TrimRight(s, ".")
+	}
+	return s
+}
+
+// priceDecimals returns the number of decimals allowed for the price x.
+func priceDecimals(x float64, maxDecimals, szDecimals int) int {
 	// Rule 1: The tick rule – maximum decimals allowed is (maxDecimals - szDecimals).
 	allowedTick := maxDecimals - szDecimals
 
@@ -227,19 +268,15 @@ func PriceToWire(x float64, maxDecimals, szDecimals int) string {
 	if allowedDecimals < 0 {
 		allowedDecimals = 0
 	}
+	return allowedDecimals
+}
 
-	// Round the price to allowedDecimals decimals.
-	factor := pow10(allowedDecimals)
-	rounded := math.Round(x*factor) / factor
-
-	// Format the number with fixed precision.
-	s := strconv.FormatFloat(rounded, 'f', allowedDecimals, 64)
-	// Only trim trailing zeros if the formatted string contains a decimal point.
-	if strings.Contains(s, ".") {
-		s = strings.TrimRight(s, "0")
-		s = strings.TrimRight(s, ".")
-	}
-	return s
+// PriceTick returns the smallest price increment allowed at the price x,
+// following the same rules as PriceToWire.
+//
+//	PriceTick(2000, PERP_MAX_DECIMALS, 4) // 0.1
+func PriceTick(x float64, maxDecimals, szDecimals int) float64 {
+	return 1 / pow10(priceDecimals(x, maxDecimals, szDecimals))
 }
 
 // SizeToFloat calls SizeToWire for consistent rounding,
@@ -272,6 +309,12 @@ func SizeToWire(x float64, szDecimals int) string {
 	return strings.TrimRight(strings.TrimRight(s, "0"), ".")
 }
 
+// UsdToWireInt converts a USD amount to the integer amount of micro USD
+// used by the margin actions (e.g. 1.5 -> 1500000).
+func UsdToWireInt(x float64) int64 {
+	return int64(math.Round(x * 1e6))
+}
+
 // To sign raw messages via EIP-712
 func StructToMap(strct any) (res map[string]interface{}, err error) {
 	a, err := json.Marshal(strct)
@@ -1,6 +1,7 @@
 package hyperliquid
 
 import (
+	"math"
 	"testing"
 )
 
@@ -109,3 +110,26 @@ func TestConvert_PriceToWire(t *testing.T) {
 		})
 	}
 }
+
+func TestConvert_PriceTick(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    float64
+		maxDec   int
+		szDec    int
+		expected float64
+	}{
+		{name: "BTC Price", input: 105000, maxDec: 6, szDec: 5, expected: 1},
+		{name: "ETH Price", input: 2000, maxDec: 6, szDec: 4, expected: 0.1},
+		{name: "Small Perp Price", input: 0.012345, maxDec: 6, szDec: 0, expected: 0.000001},
+		{name: "Spot Price", input: 0.2345, maxDec: 8, szDec: 0, expected: 0.00001},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := PriceTick(tc.input, tc.maxDec, tc.szDec)
+			if math.Abs(res-tc.expected) > 1e-12 {
+				t.Errorf("PriceTick() = %v, want %v", res, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package hyperliquid
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// MultiSigSignature is the signature of a multi-sig action by one of the authorized users.
+// It can be serialized to json and sent to the outer signer, who merges the signatures with a MultiSigCollector.
+type MultiSigSignature struct {
+	Signer       string       `json:"signer"`
+	MultiSigUser string       `json:"multiSigUser"`
+	OuterSigner  string       `json:"outerSigner"`
+	Nonce        uint64       `json:"nonce"`
+	ActionHash   string       `json:"actionHash"` // identifies the signed action, see multiSigInnerActionHash
+	Signature    RsvSignature `json:"signature"`
+}
+
+// multiSigInnerActionHash returns the hash used to check that signatures were made for the same action.
+func multiSigInnerActionHash(action any, nonce uint64) (string, error) {
+	hash, err := buildActionHash(action, "", nonce)
+	if err != nil {
+		return "", err
+	}
+	return hash.Hex(), nil
+}
+
+// Sign an L1 action (e.g. an order) of a multi-sig user with the api key
+// The signature must be made with the nonce and the outer signer used to submit the action with MultiSig.
+// The vault address of the api is signed too, it must be the same as the one of the outer signer.
+func (api *ExchangeAPI) SignMultiSigInner(multiSigUser string, outerSigner string, action any, nonce uint64) (*MultiSigSignature, error) {
+	multiSigUser = strings.ToLower(multiSigUser)
+	outerSigner = strings.ToLower(outerSigner)
+	envelope := []any{multiSigUser, outerSigner, action}
+	srequest, err := api.BuildEIP712Message(envelope, nonce, api.VaultAddress())
+	if err != nil {
+		api.debug("Error building EIP712 message: %s", err)
+		return nil, err
+	}
+	v, r, s, err := api.Sign(srequest)
+	if err != nil {
+		api.debug("Error signing multi-sig L1 action: %s", err)
+		return nil, err
+	}
+	return api.newMultiSigSignature(multiSigUser, outerSigner, action, nonce, ToTypedSig(r, s, v))
+}
+
+// Sign a user signed action (e.g. an usdSend) of a multi-sig user with the api key
+// The payloadTypes and primaryType are the ones used to sign the action for a regular user,
+// the multi-sig user and the outer signer are added to the signed message.
+// The nonce must be the nonce (or time) of the action.
+func (api *ExchangeAPI) SignMultiSigInnerUserAction(multiSigUser string, outerSigner string, action any, nonce uint64, payloadTypes []apitypes.Type, primaryType string) (*MultiSigSignature, error) {
+	multiSigUser = strings.ToLower(multiSigUser)
+	outerSigner = strings.ToLower(outerSigner)
+	if len(payloadTypes) == 0 {
+		return nil, APIError{Message: "Missing payload types"}
+	}
+	message, err := StructToMap(action)
+	if err != nil {
+		return nil, err
+	}
+	message["payloadMultiSigUser"] = multiSigUser
+	message["outerSigner"] = outerSigner
+	// The multi-sig fields follow the hyperliquidChain field
+	types := []apitypes.Type{
+		payloadTypes[0],
+		{
+			Name: "payloadMultiSigUser",
+			Type: "address",
+		},
+		{
+			Name: "outerSigner",
+			Type: "address",
+		},
+	}
+	types = append(types, payloadTypes[1:]...)
+	v, r, s, err := api.SignUserSignableAction(message, types, primaryType)
+	if err != nil {
+		api.debug("Error signing multi-sig user signed action: %s", err)
+		return nil, err
+	}
+	return api.newMultiSigSignature(multiSigUser, outerSigner, action, nonce, ToTypedSig(r, s, v))
+}
+
+func (api *ExchangeAPI) newMultiSigSignature(multiSigUser string, outerSigner string, action any, nonce uint64, signature RsvSignature) (*MultiSigSignature, error) {
+	actionHash, err := multiSigInnerActionHash(action, nonce)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigSignature{
+		Signer:       strings.ToLower(api.KeyManager().PublicAddressHex()),
+		MultiSigUser: multiSigUser,
+		OuterSigner:  outerSigner,
+		Nonce:        nonce,
+		ActionHash:   actionHash,
+		Signature:    signature,
+	}, nil
+}
+
+// MultiSigCollector merges the signatures of a multi-sig action made by several signers.
+// It is used by the outer signer to submit the action once enough signatures are collected.
+type MultiSigCollector struct {
+	MultiSigUser string
+	OuterSigner  string
+	Action       any
+	Nonce        uint64
+	actionHash   string
+	signatures   map[string]RsvSignature // by signer
+}
+
+// NewMultiSigCollector creates a collector for the signatures of an action of a multi-sig user.
+func NewMultiSigCollector(multiSigUser string, outerSigner string, action any, nonce uint64) (*MultiSigCollector, error) {
+	actionHash, err := multiSigInnerActionHash(action, nonce)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigCollector{
+		MultiSigUser: strings.ToLower(multiSigUser),
+		OuterSigner:  strings.ToLower(outerSigner),
+		Action:       action,
+		Nonce:        nonce,
+		actionHash:   actionHash,
+		signatures:   make(map[string]RsvSignature),
+	}, nil
+}
+
+// Add adds the signature of a signer.
+// Returns an error if the signature was made for another action, nonce, user or outer signer.
+func (c *MultiSigCollector) Add(sig *MultiSigSignature) error {
+	if sig.MultiSigUser != c.MultiSigUser || sig.OuterSigner != c.OuterSigner {
+		return APIError{Message: fmt.Sprintf("Signature of %s is for user %s and outer signer %s", sig.Signer, sig.MultiSigUser, sig.OuterSigner)}
+	}
+	if sig.Nonce != c.Nonce || sig.ActionHash != c.actionHash {
+		return APIError{Message: fmt.Sprintf("Signature of %s is for another action", sig.Signer)}
+	}
+	c.signatures[sig.Signer] = sig.Signature
+	return nil
+}
+
+// Len returns the number of collected signatures.
+func (c *MultiSigCollector) Len() int {
+	return len(c.signatures)
+}
+
+// Signatures returns the collected signatures ordered by signer address.
+func (c *MultiSigCollector) Signatures() []RsvSignature {
+	signers := make([]string, 0, len(c.signatures))
+	for signer := range c.signatures {
+		signers = append(signers, signer)
+	}
+	sort.Strings(signers)
+	signatures := make([]RsvSignature, len(signers))
+	for i, signer := range signers {
+		signatures[i] = c.signatures[signer]
+	}
+	return signatures
+}
+
+// Submit submits the action with the collected signatures.
+// The api key must be the key of the outer signer.
+func (c *MultiSigCollector) Submit(api *ExchangeAPI) (*MultiSigResponse, error) {
+	if api.KeyManager() == nil {
+		return nil, APIError{Message: "API key not set"}
+	}
+	if signer := strings.ToLower(api.KeyManager().PublicAddressHex()); signer != c.OuterSigner {
+		return nil, APIError{Message: fmt.Sprintf("API key %s is not the outer signer %s", signer, c.OuterSigner)}
+	}
+	return api.MultiSig(c.MultiSigUser, c.Action, c.Signatures(), c.Nonce)
+}
@@ -1,11 +1,17 @@
 package hyperliquid
 
 import (
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math"
+	"net/http"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 )
 
@@ -43,15 +49,25 @@ type ExchangeAPI struct {
 	meta         map[string]AssetInfo
 	spotMeta     map[string]AssetInfo
 	role         string
+	ws           *WebSocketAPI
+	// signatureChainID overrides the default signatureChainId of user signed actions
+	signatureChainID string
+	riskGuard        *RiskGuard
+	cloidRegistry    *CloidRegistry
 }
 
 // NewExchangeAPI creates a new default ExchangeAPI.
 // Run SetPrivateKey() and SetAccountAddress() to set the private key and account address.
 func NewExchangeAPI(isMainnet bool) *ExchangeAPI {
+	return newExchangeAPI(*NewClient(isMainnet))
+}
+
+// newExchangeAPI creates an ExchangeAPI using client, for itself and its InfoAPI, and loads the meta with it.
+func newExchangeAPI(client Client) *ExchangeAPI {
 	api := ExchangeAPI{
-		Client:       *NewClient(isMainnet),
+		Client:       client,
 		baseEndpoint: "/exchange",
-		infoAPI:      NewInfoAPI(isMainnet),
+		infoAPI:      newInfoAPI(client),
 		address:      "",
 	}
 	// turn on debug mode if there is an error with /info service
@@ -71,6 +87,63 @@ func NewExchangeAPI(isMainnet bool) *ExchangeAPI {
 	return &api
 }
 
+// SetHTTPClient sets the HTTP client of the exchange requests and of the info requests made by the ExchangeAPI.
+// Pass nil to use http.DefaultClient.
+func (api *ExchangeAPI) SetHTTPClient(httpClient *http.Client) {
+	api.Client.SetHTTPClient(httpClient)
+	api.infoAPI.SetHTTPClient(httpClient)
+}
+
+// SetTransport sets the RoundTripper of the exchange requests and of the info requests made by the ExchangeAPI.
+func (api *ExchangeAPI) SetTransport(transport http.RoundTripper) {
+	api.Client.SetTransport(transport)
+	api.infoAPI.SetTransport(transport)
+}
+
+// SetRateLimiter limits the exchange requests and the info requests made by the ExchangeAPI with limiter.
+// Pass nil to disable the limit.
+func (api *ExchangeAPI) SetRateLimiter(limiter *RateLimiter) {
+	api.Client.SetRateLimiter(limiter)
+	api.infoAPI.SetRateLimiter(limiter)
+}
+
+// LoadPerpDexMeta adds the assets of a builder-deployed perp dex to the meta map
+// so they can be traded by name (e.g. "xyz:XYZ100") like the default perps.
+func (api *ExchangeAPI) LoadPerpDexMeta(dex string) error {
+	meta, err := api.infoAPI.BuildPerpDexMetaMap(dex)
+	if err != nil {
+		return err
+	}
+	if api.meta == nil {
+		api.meta = make(map[string]AssetInfo, len(meta))
+	}
+	for name, info := range meta {
+		api.meta[name] = info
+	}
+	return nil
+}
+
+// SetWebSocketAPI routes signed exchange requests through the websocket "post" channel
+// instead of the /exchange HTTP endpoint. The websocket must be connected by the caller.
+// Pass nil to switch back to HTTP.
+func (api *ExchangeAPI) SetWebSocketAPI(ws *WebSocketAPI) {
+	api.ws = ws
+}
+
+// WebSocketAPI returns the websocket used to post exchange requests, if any.
+func (api *ExchangeAPI) WebSocketAPI() *WebSocketAPI {
+	return api.ws
+}
+
+// Request sends a signed exchange request.
+// The request is posted over the websocket if one is connected, otherwise over HTTP.
+func (api *ExchangeAPI) Request(endpoint string, payload any) ([]byte, error) {
+	if api.ws != nil && api.ws.IsConnected() {
+		return api.ws.PostAction(payload)
+	}
+	return api.Client.Request(endpoint, payload)
+}
+
 // Helper function to calculate the slippage price based on the market price.
 func (api *ExchangeAPI) SlippagePrice(coin string, isBuy bool, slippage float64) float64 {
 	marketPx, err := api.infoAPI.GetMartketPx(coin)
@@ -92,12 +165,68 @@ func (api *ExchangeAPI) SlippagePriceSpot(coin string, isBuy bool, slippage floa
 	return slippagePrice
 }
 
+// DepthPrice walks the live L2 book of coin and returns the worst price needed to fill size,
+// the level at which the requested size is fully covered.
+// Levels beyond mid price * (1 +- slippage) are not used; if the book is too thin
+// an InsufficientLiquidityError is returned.
+func (api *ExchangeAPI) DepthPrice(coin string, isBuy bool, size float64, slippage float64) (float64, error) {
+	book, err := api.infoAPI.GetL2BookSnapshot(coin)
+	if err != nil {
+		return 0, err
+	}
+	if len(book.Levels) != 2 || len(book.Levels[0]) == 0 || len(book.Levels[1]) == 0 {
+		return 0, InsufficientLiquidityError{Coin: coin, Size: size}
+	}
+	bids, asks := book.Levels[0], book.Levels[1]
+	mid := (bids[0].Px + asks[0].Px) / 2
+	levels, boundPx := bids, mid*(1-slippage)
+	if isBuy {
+		levels, boundPx = asks, mid*(1+slippage)
+	}
+	filled := 0.0
+	for _, level := range levels {
+		if (isBuy && level.Px > boundPx) || (!isBuy && level.Px < boundPx) {
+			break
+		}
+		filled += level.Sz
+		if filled >= size {
+			return level.Px, nil
+		}
+	}
+	return 0, InsufficientLiquidityError{Coin: coin, Size: size, Available: filled, BoundPx: boundPx}
+}
+
+// SetSignatureChainID sets the chain id (hex, e.g. "0xa4b1") used to sign user signed actions
+// such as withdrawals and transfers. It must be the chain the signing wallet is connected to.
+// Pass an empty string to use the default chain of the network.
+// It returns an error, and keeps the previous chain id, if chainID is not a hex chain id.
+func (api *ExchangeAPI) SetSignatureChainID(chainID string) error {
+	if chainID != "" {
+		if _, err := parseSignatureChainID(chainID); err != nil {
+			return err
+		}
+	}
+	api.signatureChainID = chainID
+	return nil
+}
+
+// SignatureChainID returns the chain id used to sign user signed actions.
+func (api *ExchangeAPI) SignatureChainID() string {
+	signatureChainID, _ := api.getChainParams()
+	return signatureChainID
+}
+
 // Helper function to get the chain params based on the network type.
+// It returns the signatureChainId and the hyperliquidChain of user signed actions.
 func (api *ExchangeAPI) getChainParams() (string, string) {
+	signatureChainID, chainType := TESTNET_SIGNATURE_CHAIN_ID, "Testnet"
 	if api.IsMainnet() {
-		return "0xa4b1", "Mainnet"
+		signatureChainID, chainType = MAINNET_SIGNATURE_CHAIN_ID, "Mainnet"
+	}
+	if api.signatureChainID != "" {
+		signatureChainID = api.signatureChainID
 	}
-	return "0x66eee", "Testnet"
+	return signatureChainID, chainType
 }
 
 // Build bulk orders EIP712 message
@@ -107,8 +236,13 @@ func (api *ExchangeAPI) BuildBulkOrdersEIP712(requests []OrderRequest, grouping
 		meta := api.GetMeta(req)
 		wires = append(wires, req.ToWire(meta))
 	}
+	builder, err := OrderRequestsBuilder(requests)
+	if err != nil {
+		return apitypes.TypedData{}, err
+	}
 	timestamp := GetNonce()
 	action := OrderWiresToOrderAction(wires, grouping)
+	action.Builder = builder
 	srequest, err := api.BuildEIP712Message(action, timestamp, api.VaultAddress())
 	if err != nil {
 		api.debug("Error building EIP712 message: %s", err)
@@ -127,16 +261,203 @@ func (api *ExchangeAPI) BuildOrderEIP712(request OrderRequest, grouping Grouping
 //
 
 // Place orders in bulk
+// If the requests have a builder, it must be the same for all of them.
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#place-an-order
 func (api *ExchangeAPI) BulkOrders(requests []OrderRequest, grouping Grouping) (*OrderResponse, error) {
+	request, err := api.buildBulkOrdersRequest(requests, grouping)
+	if err != nil {
+		return nil, err
+	}
+	res, err := MakeUniversalRequest[OrderResponse](api, *request)
+	if err == nil && api.cloidRegistry != nil {
+		api.cloidRegistry.RecordResponse(requests, res)
+	}
+	return res, err
+}
+
+// ValidateOrder is ValidateOrders for a single order.
+func (api *ExchangeAPI) ValidateOrder(request OrderRequest) (*ExchangeRequest, error) {
+	return api.ValidateOrders([]OrderRequest{request}, GroupingNa)
+}
+
+// ValidateOrders checks the orders locally without sending them (dry run).
+// Each order must be for a known coin, have a size and prices matching the size decimals
+// and tick size of the coin, and be worth at least MIN_ORDER_NOTIONAL unless it is reduce only.
+// Perp orders that are not reduce only must also pass the margin check of CanPlaceOrder.
+// It returns the signed request BulkOrders would send to /exchange.
+func (api *ExchangeAPI) ValidateOrders(requests []OrderRequest, grouping Grouping) (*ExchangeRequest, error) {
+	for _, req := range requests {
+		if err := api.validateOrder(req); err != nil {
+			return nil, err
+		}
+		if req.isSpot() || req.ReduceOnly {
+			continue
+		}
+		check, err := api.CanPlaceOrder(req)
+		if err != nil {
+			return nil, err
+		}
+		if !check.Allowed {
+			return nil, InsufficientMarginError{APIError{Message: fmt.Sprintf("Insufficient margin for %s order: %v required, %v available", req.Coin, check.RequiredMargin, check.AvailableMargin)}}
+		}
+	}
+	return api.buildBulkOrdersRequest(requests, grouping)
+}
+
+// validateOrder runs the tick size, lot size and minimum notional checks of an order.
+func (api *ExchangeAPI) validateOrder(req OrderRequest) error {
+	meta, maxDecimals := api.meta, PERP_MAX_DECIMALS
+	if req.isSpot() {
+		meta, maxDecimals = api.spotMeta, SPOT_MAX_DECIMALS
+	}
+	info, ok := meta[req.Coin]
+	if !ok {
+		return APIError{Message: fmt.Sprintf("Unknown coin: %s", req.Coin)}
+	}
+	if req.Sz <= 0 || req.LimitPx <= 0 {
+		return APIError{Message: fmt.Sprintf("Invalid %s order size %v or price %v", req.Coin, req.Sz, req.LimitPx)}
+	}
+	if sz := SizeToWire(req.Sz, info.SzDecimals); sz != strconv.FormatFloat(req.Sz, 'f', -1, 64) {
+		return APIError{Message: fmt.Sprintf("Invalid %s order size %v: at most %d decimals allowed", req.Coin, req.Sz, info.SzDecimals)}
+	}
+	prices := []float64{req.LimitPx}
+	if req.OrderType.Trigger != nil {
+		triggerPx, err := strconv.ParseFloat(req.OrderType.Trigger.TriggerPx, 64)
+		if err != nil || triggerPx <= 0 {
+			return APIError{Message: fmt.Sprintf("Invalid %s trigger price %q", req.Coin, req.OrderType.Trigger.TriggerPx)}
+		}
+		prices = append(prices, triggerPx)
+	}
+	for _, px := range prices {
+		if PriceToWire(px, maxDecimals, info.SzDecimals) != strconv.FormatFloat(px, 'f', -1, 64) {
+			return APIError{Message: fmt.Sprintf("Invalid %s order price %v: tick size is %v", req.Coin, px, PriceTick(px, maxDecimals, info.SzDecimals))}
+		}
+	}
+	return checkOrderSize(req, info)
+}
+
+// checkOrderSize returns an OrderTooSmallError if the size of the order rounds below the lot size
+// of the asset or, unless it is reduce only, if its value is below MIN_ORDER_NOTIONAL.
+func checkOrderSize(req OrderRequest, info AssetInfo) error {
+	sz, _ := strconv.ParseFloat(SizeToWire(req.Sz, info.SzDecimals), 64)
+	err := OrderTooSmallError{
+		Coin:        req.Coin,
+		Sz:          sz,
+		Notional:    sz * req.LimitPx,
+		MinSz:       1 / pow10(info.SzDecimals),
+		MinNotional: MIN_ORDER_NOTIONAL,
+	}
+	if sz < err.MinSz || (!req.ReduceOnly && err.Notional < MIN_ORDER_NOTIONAL) {
+		return err
+	}
+	return nil
+}
+
+// CanPlaceOrder predicts whether the perp order would be rejected for insufficient margin.
+// The margin required by the order is its value at the limit price divided by the leverage of the position,
+// or DEFAULT_LEVERAGE capped at the max leverage of the asset if there is no position.
+// The part of the order that reduces an opposite position requires no margin.
+// This is an estimate, the exchange also takes resting orders and the mark price into account.
+func (api *ExchangeAPI) CanPlaceOrder(req OrderRequest) (*MarginCheck, error) {
+	if req.isSpot() {
+		return nil, APIError{Message: fmt.Sprintf("Margin check is not available for spot coin %s", req.Coin)}
+	}
+	user := api.VaultAddress()
+	if user == "" {
+		user = api.AccountAddress()
+	}
+	state, err := api.infoAPI.GetUserState(user)
+	if err != nil {
+		return nil, err
+	}
+	metaAndCtxs, err := api.infoAPI.GetMetaAndAssetCtxs()
+	if err != nil {
+		return nil, err
+	}
+	var asset *Asset
+	for i := range metaAndCtxs.Meta.Universe {
+		if metaAndCtxs.Meta.Universe[i].Name == req.Coin {
+			asset = &metaAndCtxs.Meta.Universe[i]
+		}
+	}
+	if asset == nil {
+		return nil, APIError{Message: fmt.Sprintf("Unknown coin: %s", req.Coin)}
+	}
+	leverage := min(DEFAULT_LEVERAGE, asset.MaxLeverage)
+	sz := req.Sz
+	for _, position := range state.AssetPositions {
+		if position.Position.Coin != req.Coin {
+			continue
+		}
+		if position.Position.Leverage.Value > 0 {
+			leverage = position.Position.Leverage.Value
+		}
+		if IsBuy(position.Position.Szi) != req.IsBuy {
+			sz = math.Max(0, sz-math.Abs(position.Position.Szi))
+		}
+	}
+	if leverage <= 0 {
+		return nil, APIError{Message: fmt.Sprintf("Invalid leverage for %s", req.Coin)}
+	}
+	check := &MarginCheck{
+		RequiredMargin:  sz * req.LimitPx / float64(leverage),
+		AvailableMargin: math.Max(0, state.MarginSummary.AccountValue-state.MarginSummary.TotalMarginUsed),
+		Leverage:        leverage,
+	}
+	check.Allowed = req.ReduceOnly || check.RequiredMargin <= check.AvailableMargin
+	return check, nil
+}
+
+// buildBulkOrdersRequest checks the orders against the RiskGuard, then converts and signs them
+// into the request sent to /exchange.
+func (api *ExchangeAPI) buildBulkOrdersRequest(requests []OrderRequest, grouping Grouping) (*ExchangeRequest, error) {
+	if err := api.checkRisk(requests); err != nil {
+		return nil, err
+	}
+	return api.signBulkOrders(requests, grouping)
+}
+
+// signBulkOrders converts and signs the orders into the request sent to /exchange, without the RiskGuard checks.
+func (api *ExchangeAPI) signBulkOrders(requests []OrderRequest, grouping Grouping) (*ExchangeRequest, error) {
 	var wires []OrderWire
-	var meta AssetInfo
 	for _, req := range requests {
-		meta = api.GetMeta(req)
+		meta, ok := api.lookupMeta(req)
+		if !ok {
+			return nil, APIError{Message: fmt.Sprintf("Unknown coin: %s", req.Coin)}
+		}
+		if err := checkOrderSize(req, meta); err != nil {
+			return nil, err
+		}
 		wires = append(wires, req.ToWire(meta))
 	}
+	builder, err := OrderRequestsBuilder(requests)
+	if err != nil {
+		return nil, err
+	}
 	timestamp := GetNonce()
 	action := OrderWiresToOrderAction(wires, grouping)
+	action.Builder = builder
+	v, r, s, err := api.SignL1Action(action, timestamp)
+	if err != nil {
+		api.debug("Error signing L1 action: %s", err)
+		return nil, err
+	}
+	return &ExchangeRequest{
+		Action:       action,
+		Nonce:        timestamp,
+		Signature:    ToTypedSig(r, s, v),
+		VaultAddress: api.VaultAddress(),
+	}, nil
+}
+
+// Cancel order(s)
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#cancel-order-s
+func (api *ExchangeAPI) BulkCancelOrders(cancels []CancelOidWire) (*OrderResponse, error) {
+	timestamp := GetNonce()
+	action := CancelOidOrderAction{
+		Type:    "cancel",
+		Cancels: cancels,
+	}
 	v, r, s, err := api.SignL1Action(action, timestamp)
 	if err != nil {
 		api.debug("Error signing L1 action: %s", err)
@@ -151,160 +472,1384 @@ func (api *ExchangeAPI) BulkOrders(requests []OrderRequest, grouping Grouping) (
 	return MakeUniversalRequest[OrderResponse](api, request)
 }
 
-// Cancel order(s)
-// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#cancel-order-s
-func (api *ExchangeAPI) BulkCancelOrders(cancels []CancelOidWire) (*OrderResponse, error) {
+// ModifyOrder modifies a single resting order.
+// The order is identified by its Oid or, if Oid is nil, by its Cloid, so an order
+// placed with a cloid can be modified without looking up its oid first.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#modify-an-order
+func (api *ExchangeAPI) ModifyOrder(request ModifyOrderRequest) (*OrderResponse, error) {
+	order, err := api.modifyOrderWire(request)
+	if err != nil {
+		return nil, err
+	}
+	var oid any = request.Cloid
+	if request.Oid != nil {
+		oid = *request.Oid
+	}
+	action := ModifyAction{
+		Type:  "modify",
+		Oid:   oid,
+		Order: order,
+	}
+
+	timestamp := GetNonce()
+	vVal, rVal, sVal, signErr := api.SignL1Action(action, timestamp)
+	if signErr != nil {
+		return nil, signErr
+	}
+	exchangeRequest := ExchangeRequest{
+		Action:       action,
+		Nonce:        timestamp,
+		Signature:    ToTypedSig(rVal, sVal, vVal),
+		VaultAddress: api.VaultAddress(),
+	}
+	return MakeUniversalRequest[OrderResponse](api, exchangeRequest)
+}
+
+// ModifyOrderByCloid modifies the resting order with the client order id cloid.
+//
+//	ModifyOrderByCloid("0x00000000000000000000000000000001", OrderRequest{Coin: "ETH", ...})
+func (api *ExchangeAPI) ModifyOrderByCloid(cloid string, order OrderRequest) (*OrderResponse, error) {
+	return api.ModifyOrder(ModifyOrderRequest{Cloid: cloid, Order: order})
+}
+
+// ReplaceOrder changes the price and size of the resting limit order oid.
+// The order is modified in place if possible; if the modify is rejected it is canceled
+// and placed again with the same side, time in force, reduce only flag and cloid.
+// The result tells which path was taken and the identity of the resulting order.
+func (api *ExchangeAPI) ReplaceOrder(oid int, newPx float64, newSz float64) (*ReplaceOrderResult, error) {
+	user := api.VaultAddress()
+	if user == "" {
+		user = api.AccountAddress()
+	}
+	status, err := api.infoAPI.GetOrderStatus(user, oid)
+	if err != nil {
+		return nil, err
+	}
+	if !status.IsFound() {
+		return nil, APIError{Message: fmt.Sprintf("Unknown order: %d", oid)}
+	}
+	order := status.Order.Order
+	if order.IsTrigger {
+		return nil, APIError{Message: fmt.Sprintf("Cannot replace trigger order %d", oid)}
+	}
+	tif := order.Tif
+	if tif == "" {
+		tif = TifGtc
+	}
+	request := OrderRequest{
+		Coin:       order.Coin,
+		IsBuy:      order.Side == "B",
+		Sz:         newSz,
+		LimitPx:    newPx,
+		OrderType:  OrderType{Limit: &LimitOrderType{Tif: tif}},
+		ReduceOnly: order.ReduceOnly,
+		Cloid:      order.Cloid,
+	}
+	res, err := api.BatchModifyOrders([]ModifyOrderRequest{{Oid: &oid, Order: request}})
+	if err != nil {
+		return nil, err
+	}
+	if statuses := res.Response.Data.Statuses; len(statuses) == 1 && statuses[0].Error == "" {
+		return &ReplaceOrderResult{Path: ReplacePathModify, Oid: statuses[0].OrderID(), Cloid: order.Cloid, Response: res}, nil
+	} else if len(statuses) == 1 {
+		api.debug("Modify of order %d rejected: %s, canceling it", oid, statuses[0].Error)
+	}
+	cancelRes, err := api.CancelOrderByOID(order.Coin, oid)
+	if err != nil {
+		return nil, err
+	}
+	if statuses := cancelRes.Response.Data.Statuses; len(statuses) == 1 && statuses[0].Error != "" {
+		return nil, APIError{Message: statuses[0].Error}
+	}
+	res, err = api.Order(request, GroupingNa)
+	if err != nil {
+		return nil, err
+	}
+	result := &ReplaceOrderResult{Path: ReplacePathCancelReplace, Cloid: order.Cloid, Response: res}
+	if statuses := res.Response.Data.Statuses; len(statuses) == 1 {
+		result.Oid = statuses[0].OrderID()
+	}
+	return result, nil
+}
+
+// modifyOrderWire validates the target of a modify request and converts its new order to its wire representation.
+func (api *ExchangeAPI) modifyOrderWire(req ModifyOrderRequest) (OrderWire, error) {
+	if req.Oid == nil {
+		if req.Cloid == "" {
+			return OrderWire{}, APIError{Message: fmt.Sprintf("Missing oid or cloid to modify %s order", req.Order.Coin)}
+		}
+		if _, err := HexToInt(req.Cloid); err != nil {
+			return OrderWire{}, err
+		}
+	}
+	if err := api.checkRisk([]OrderRequest{req.Order}); err != nil {
+		return OrderWire{}, err
+	}
+	info := api.GetMeta(req.Order)
+	return req.Order.ToWire(info), nil
+}
+
+// Bulk modify orders
+// Each order is identified by its OrderID or, if OrderID is nil, by its Cloid.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#modify-multiple-orders
+func (api *ExchangeAPI) BulkModifyOrders(modifyRequests []OrderRequest) (*OrderResponse, error) {
+	requests := make([]ModifyOrderRequest, 0, len(modifyRequests))
+	for _, req := range modifyRequests {
+		requests = append(requests, ModifyOrderRequest{Oid: req.OrderID, Cloid: req.Cloid, Order: req})
+	}
+	return api.BatchModifyOrders(requests)
+}
+
+// BatchModifyOrders modifies several resting orders atomically in a single signed request.
+// Each order is identified by its Oid or, if Oid is nil, by its Cloid.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#modify-multiple-orders
+func (api *ExchangeAPI) BatchModifyOrders(modifyRequests []ModifyOrderRequest) (*OrderResponse, error) {
+	wires := []any{}
+
+	for _, req := range modifyRequests {
+		order, err := api.modifyOrderWire(req)
+		if err != nil {
+			return nil, err
+		}
+		if req.Oid != nil {
+			wires = append(wires, ModifyOrderWire{OrderID: *req.Oid, Order: order})
+		} else {
+			wires = append(wires, ModifyOrderByCloidWire{Cloid: req.Cloid, Order: order})
+		}
+	}
+	action := BatchModifyAction{
+		Type:     "batchModify",
+		Modifies: wires,
+	}
+
+	timestamp := GetNonce()
+	vVal, rVal, sVal, signErr := api.SignL1Action(action, timestamp)
+	if signErr != nil {
+		return nil, signErr
+	}
+	request := ExchangeRequest{
+		Action:       action,
+		Nonce:        timestamp,
+		Signature:    ToTypedSig(rVal, sVal, vVal),
+		VaultAddress: api.VaultAddress(),
+	}
+	return MakeUniversalRequest[OrderResponse](api, request)
+}
+
+// Bulk modify orders
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#modify-multiple-orders
+func (api *ExchangeAPI) BulkModifyOrdersByCloid(modifyRequests []OrderRequest) (*OrderResponse, error) {
+	wires := []ModifyOrderByCloidWire{}
+
+	for _, req := range modifyRequests {
+		info := api.GetMeta(req)
+		wires = append(wires, req.ToModifyByCloidWire(info))
+	}
+	action := ModifyOrderByCloidAction{
+		Type:     "batchModify",
+		Modifies: wires,
+	}
+
+	timestamp := GetNonce()
+	vVal, rVal, sVal, signErr := api.SignL1Action(action, timestamp)
+	if signErr != nil {
+		return nil, signErr
+	}
+	request := ExchangeRequest{
+		Action:       action,
+		Nonce:        timestamp,
+		Signature:    ToTypedSig(rVal, sVal, vVal),
+		VaultAddress: api.VaultAddress(),
+	}
+	return MakeUniversalRequest[OrderResponse](api, request)
+}
+
+// Cancel exact order by Client Order Id
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#cancel-order-s-by-cloid
+func (api *ExchangeAPI) CancelOrderByCloid(coin string, clientOID string) (*OrderResponse, error) {
+	timestamp := GetNonce()
+	action := CancelCloidOrderAction{
+		Type: "cancelByCloid",
+		Cancels: []CancelCloidWire{
+			{
+				Asset: api.meta[coin].AssetID,
+				Cloid: clientOID,
+			},
+		},
+	}
+	v, r, s, err := api.SignL1Action(action, timestamp)
+	if err != nil {
+		api.debug("Error signing L1 action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:       action,
+		Nonce:        timestamp,
+		Signature:    ToTypedSig(r, s, v),
+		VaultAddress: api.VaultAddress(),
+	}
+	return MakeUniversalRequest[OrderResponse](api, request)
+}
+
+// Update leverage for a coin
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#update-leverage
+// The leverage is checked against the max leverage and the only isolated flag of the asset before signing.
+func (api *ExchangeAPI) UpdateLeverage(coin string, isCross bool, leverage int) (*DefaultExchangeResponse, error) {
+	info, ok := api.meta[coin]
+	if !ok {
+		return nil, APIError{Message: fmt.Sprintf("Unknown coin: %s", coin)}
+	}
+	if leverage < 1 || info.MaxLeverage > 0 && leverage > info.MaxLeverage {
+		return nil, APIError{Message: fmt.Sprintf("Invalid leverage value %d for %s: must be between 1 and %d", leverage, coin, info.MaxLeverage)}
+	}
+	if isCross && info.OnlyIsolated {
+		return nil, APIError{Message: fmt.Sprintf("Cross margin is not available for %s, use isolated margin", coin)}
+	}
+	timestamp := GetNonce()
+	action := UpdateLeverageAction{
+		Type:     "updateLeverage",
+		Asset:    info.AssetID,
+		IsCross:  isCross,
+		Leverage: leverage,
+	}
+	v, r, s, err := api.SignL1Action(action, timestamp)
+	if err != nil {
+		api.debug("Error signing L1 action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:       action,
+		Nonce:        timestamp,
+		Signature:    ToTypedSig(r, s, v),
+		VaultAddress: api.VaultAddress(),
+	}
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
+}
+
+// Initiate a withdraw request
+// The amount is in USDC and includes the WITHDRAW_FEE charged by the bridge.
+// The withdrawal is signed for the network of the api, see SetSignatureChainID to sign from another chain.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#initiate-a-withdrawal-request
+func (api *ExchangeAPI) Withdraw(destination string, amount float64) (*WithdrawResponse, error) {
+	if amount <= WITHDRAW_FEE {
+		return nil, APIError{Message: fmt.Sprintf("Withdraw amount must be greater than the fee of %v USDC", WITHDRAW_FEE)}
+	}
+	nonce := GetNonce()
+	signatureChainID, chainType := api.getChainParams()
+	action := WithdrawAction{
+		Type:             "withdraw3",
+		SignatureChainID: signatureChainID,
+		HyperliquidChain: chainType,
+		Destination:      destination,
+		Amount:           SizeToWire(amount, USDC_SZ_DECIMALS),
+		Time:             nonce,
+	}
+	v, r, s, err := api.SignWithdrawAction(action)
+	if err != nil {
+		api.debug("Error signing withdraw action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:    action,
+		Nonce:     nonce,
+		Signature: ToTypedSig(r, s, v),
+	}
+	res, err := MakeUniversalRequest[WithdrawResponse](api, request)
+	if err != nil {
+		return nil, err
+	}
+	res.Nonce = int64(nonce)
+	res.Fee = WITHDRAW_FEE
+	return res, nil
+}
+
+// Place a TWAP order
+// The order is split into slices executed every 30 seconds over the given minutes.
+// If randomize is true the slice sizes and timings are randomized.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#place-a-twap-order
+func (api *ExchangeAPI) PlaceTwapOrder(coin string, sz float64, isBuy bool, minutes int, randomize bool, reduceOnly bool) (*TwapOrderResponse, error) {
+	info, ok := api.meta[coin]
+	if !ok {
+		return nil, APIError{Message: fmt.Sprintf("Unknown coin: %s", coin)}
+	}
+	timestamp := GetNonce()
+	action := TwapOrderAction{
+		Type: "twapOrder",
+		Twap: TwapOrderWire{
+			Asset:      info.AssetID,
+			IsBuy:      isBuy,
+			Sz:         SizeToWire(sz, info.SzDecimals),
+			ReduceOnly: reduceOnly,
+			Minutes:    minutes,
+			Randomize:  randomize,
+		},
+	}
+	v, r, s, err := api.SignL1Action(action, timestamp)
+	if err != nil {
+		api.debug("Error signing L1 action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:       action,
+		Nonce:        timestamp,
+		Signature:    ToTypedSig(r, s, v),
+		VaultAddress: api.VaultAddress(),
+	}
+	return MakeUniversalRequest[TwapOrderResponse](api, request)
+}
+
+// Cancel a running TWAP order
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#cancel-a-twap-order
+func (api *ExchangeAPI) CancelTwapOrder(coin string, twapID int) (*TwapCancelResponse, error) {
+	info, ok := api.meta[coin]
+	if !ok {
+		return nil, APIError{Message: fmt.Sprintf("Unknown coin: %s", coin)}
+	}
+	timestamp := GetNonce()
+	action := TwapCancelAction{
+		Type:   "twapCancel",
+		Asset:  info.AssetID,
+		TwapID: twapID,
+	}
+	v, r, s, err := api.SignL1Action(action, timestamp)
+	if err != nil {
+		api.debug("Error signing L1 action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:       action,
+		Nonce:        timestamp,
+		Signature:    ToTypedSig(r, s, v),
+		VaultAddress: api.VaultAddress(),
+	}
+	return MakeUniversalRequest[TwapCancelResponse](api, request)
+}
+
+// Schedule a cancel-all of the open orders (dead man's switch)
+// The time is in milliseconds and must be at least 5 seconds in the future.
+// Pass nil to remove the scheduled cancel.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#schedule-cancel-dead-mans-switch
+func (api *ExchangeAPI) ScheduleCancel(time *int64) (*DefaultExchangeResponse, error) {
+	timestamp := GetNonce()
+	action := ScheduleCancelAction{
+		Type: "scheduleCancel",
+		Time: time,
+	}
+	v, r, s, err := api.SignL1Action(action, timestamp)
+	if err != nil {
+		api.debug("Error signing L1 action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:       action,
+		Nonce:        timestamp,
+		Signature:    ToTypedSig(r, s, v),
+		VaultAddress: api.VaultAddress(),
+	}
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
+}
+
+// SetMarginMode switches the margin mode of coin to isolated or cross margin.
+// The leverage is submitted again with the new mode: the leverage of the position if there is one,
+// otherwise DEFAULT_LEVERAGE capped at the max leverage of the asset.
+// The switch is refused while there are open orders on the coin, and verified
+// on the position of the clearinghouse state if there is one.
+func (api *ExchangeAPI) SetMarginMode(coin string, isolated bool) (*DefaultExchangeResponse, error) {
+	info, ok := api.meta[coin]
+	if !ok {
+		return nil, APIError{Message: fmt.Sprintf("Unknown coin: %s", coin)}
+	}
+	user := api.VaultAddress()
+	if user == "" {
+		user = api.AccountAddress()
+	}
+	orders, err := api.infoAPI.GetOpenOrders(user)
+	if err != nil {
+		return nil, err
+	}
+	for _, order := range *orders {
+		if order.Coin == coin {
+			return nil, APIError{Message: fmt.Sprintf("Cannot switch the margin mode of %s with open orders", coin)}
+		}
+	}
+	state, err := api.infoAPI.GetUserState(user)
+	if err != nil {
+		return nil, err
+	}
+	leverage := DEFAULT_LEVERAGE
+	if info.MaxLeverage > 0 {
+		leverage = min(leverage, info.MaxLeverage)
+	}
+	hasPosition := false
+	for _, position := range state.AssetPositions {
+		if position.Position.Coin == coin && position.Position.Leverage.Value > 0 {
+			leverage, hasPosition = position.Position.Leverage.Value, true
+		}
+	}
+	res, err := api.UpdateLeverage(coin, !isolated, leverage)
+	if err != nil || !hasPosition {
+		return res, err
+	}
+	// Check that the position uses the new margin mode
+	state, err = api.infoAPI.GetUserState(user)
+	if err != nil {
+		return nil, err
+	}
+	want := "cross"
+	if isolated {
+		want = "isolated"
+	}
+	for _, position := range state.AssetPositions {
+		if position.Position.Coin == coin && position.Position.Leverage.Type != want {
+			return nil, APIError{Message: fmt.Sprintf("Margin mode of %s is still %s", coin, position.Position.Leverage.Type)}
+		}
+	}
+	return res, nil
+}
+
+// Add or remove margin from an isolated position
+// ntli is the USD amount to add, a negative amount removes margin.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#update-isolated-margin
+func (api *ExchangeAPI) UpdateIsolatedMargin(coin string, isBuy bool, ntli float64) (*DefaultExchangeResponse, error) {
+	info, ok := api.meta[coin]
+	if !ok {
+		return nil, APIError{Message: fmt.Sprintf("Unknown coin: %s", coin)}
+	}
+	timestamp := GetNonce()
+	action := UpdateIsolatedMarginAction{
+		Type:  "updateIsolatedMargin",
+		Asset: info.AssetID,
+		IsBuy: isBuy,
+		Ntli:  UsdToWireInt(ntli),
+	}
+	v, r, s, err := api.SignL1Action(action, timestamp)
+	if err != nil {
+		api.debug("Error signing L1 action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:       action,
+		Nonce:        timestamp,
+		Signature:    ToTypedSig(r, s, v),
+		VaultAddress: api.VaultAddress(),
+	}
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
+}
+
+// Top up the margin of an isolated-only position to reach the target leverage
+// The margin to add is computed by the exchange from the position and the leverage.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#top-up-isolated-only-margin
+func (api *ExchangeAPI) TopUpIsolatedOnlyMargin(coin string, leverage float64) (*DefaultExchangeResponse, error) {
+	info, ok := api.meta[coin]
+	if !ok {
+		return nil, APIError{Message: fmt.Sprintf("Unknown coin: %s", coin)}
+	}
+	if leverage <= 0 {
+		return nil, APIError{Message: fmt.Sprintf("Invalid leverage: %v", leverage)}
+	}
+	timestamp := GetNonce()
+	action := TopUpIsolatedOnlyMarginAction{
+		Type:     "topUpIsolatedOnlyMargin",
+		Asset:    info.AssetID,
+		Leverage: strconv.FormatFloat(leverage, 'f', -1, 64),
+	}
+	v, r, s, err := api.SignL1Action(action, timestamp)
+	if err != nil {
+		api.debug("Error signing L1 action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:       action,
+		Nonce:        timestamp,
+		Signature:    ToTypedSig(r, s, v),
+		VaultAddress: api.VaultAddress(),
+	}
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
+}
+
+// Send USDC to another address on Hyperliquid
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#core-usdc-transfer
+func (api *ExchangeAPI) UsdSend(destination string, amount float64) (*DefaultExchangeResponse, error) {
+	nonce := GetNonce()
+	signatureChainID, chainType := api.getChainParams()
+	action := UsdSendAction{
+		Type:             "usdSend",
+		SignatureChainID: signatureChainID,
+		HyperliquidChain: chainType,
+		Destination:      destination,
+		Amount:           SizeToWire(amount, USDC_SZ_DECIMALS),
+		Time:             nonce,
+	}
+	v, r, s, err := api.SignUsdSendAction(action)
+	if err != nil {
+		api.debug("Error signing usdSend action: %s", err)
+		return nil, err
+	}
+	// User signed actions are not sent on behalf of a vault.
+	request := ExchangeRequest{
+		Action:    action,
+		Nonce:     nonce,
+		Signature: ToTypedSig(r, s, v),
+	}
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
+}
+
+// Send a spot token to another address on Hyperliquid
+// The token is the name of the token (e.g. "PURR"), the amount is rounded to the token wei decimals.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#core-spot-transfer
+func (api *ExchangeAPI) SpotSend(destination string, token string, amount float64) (*DefaultExchangeResponse, error) {
+	info, err := api.spotTokenInfo(token)
+	if err != nil {
+		return nil, err
+	}
+	nonce := GetNonce()
+	signatureChainID, chainType := api.getChainParams()
+	action := SpotSendAction{
+		Type:             "spotSend",
+		SignatureChainID: signatureChainID,
+		HyperliquidChain: chainType,
+		Destination:      destination,
+		Token:            fmt.Sprintf("%s:%s", token, info.TokenID),
+		Amount:           SizeToWire(amount, info.WeiDecimals),
+		Time:             nonce,
+	}
+	v, r, s, err := api.SignSpotSendAction(action)
+	if err != nil {
+		api.debug("Error signing spotSend action: %s", err)
+		return nil, err
+	}
+	// User signed actions are not sent on behalf of a vault.
+	request := ExchangeRequest{
+		Action:    action,
+		Nonce:     nonce,
+		Signature: ToTypedSig(r, s, v),
+	}
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
+}
+
+// spotTokenInfo returns the spot meta of a token.
+// Tokens that are not traded as a base token (e.g. USDC) are resolved from the spot meta.
+func (api *ExchangeAPI) spotTokenInfo(token string) (AssetInfo, error) {
+	if info, ok := api.spotMeta[token]; ok && info.TokenID != "" {
+		return info, nil
+	}
+	if api.infoAPI == nil {
+		return AssetInfo{}, APIError{Message: fmt.Sprintf("Unknown token: %s", token)}
+	}
+	spotMeta, err := api.infoAPI.GetSpotMeta()
+	if err != nil {
+		return AssetInfo{}, err
+	}
+	for _, t := range spotMeta.Tokens {
+		if t.Name == token {
+			return AssetInfo{SzDecimals: t.SzDecimals, WeiDecimals: t.WeiDecimals, TokenID: t.TokenID}, nil
+		}
+	}
+	return AssetInfo{}, APIError{Message: fmt.Sprintf("Unknown token: %s", token)}
+}
+
+// Transfer USDC between the spot and perp balances
+// If toPerp is true the amount is moved from spot to perp, otherwise from perp to spot.
+// When a vault address is set the transfer is made for that sub-account.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#transfer-from-spot-account-to-perp-account-and-vice-versa
+func (api *ExchangeAPI) TransferBetweenSpotAndPerp(amount float64, toPerp bool) (*DefaultExchangeResponse, error) {
+	nonce := GetNonce()
+	signatureChainID, chainType := api.getChainParams()
+	wireAmount := SizeToWire(amount, USDC_SZ_DECIMALS)
+	if api.VaultAddress() != "" {
+		wireAmount = fmt.Sprintf("%s subaccount:%s", wireAmount, api.VaultAddress())
+	}
+	action := UsdClassTransferAction{
+		Type:             "usdClassTransfer",
+		SignatureChainID: signatureChainID,
+		HyperliquidChain: chainType,
+		Amount:           wireAmount,
+		ToPerp:           toPerp,
+		Nonce:            nonce,
+	}
+	v, r, s, err := api.SignUsdClassTransferAction(action)
+	if err != nil {
+		api.debug("Error signing usdClassTransfer action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:    action,
+		Nonce:     nonce,
+		Signature: ToTypedSig(r, s, v),
+	}
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
+}
+
+// Transfer USDC between the default perp dex and a builder-deployed perp dex
+// If toPerp is true the amount is moved to the builder-deployed dex, otherwise back to the default dex.
+func (api *ExchangeAPI) PerpDexClassTransfer(dex string, amount float64, toPerp bool) (*DefaultExchangeResponse, error) {
+	if dex == "" {
+		return nil, APIError{Message: "Missing perp dex name"}
+	}
+	nonce := GetNonce()
+	signatureChainID, chainType := api.getChainParams()
+	action := PerpDexClassTransferAction{
+		Type:             "perpDexClassTransfer",
+		SignatureChainID: signatureChainID,
+		HyperliquidChain: chainType,
+		Dex:              dex,
+		Token:            "USDC",
+		Amount:           SizeToWire(amount, USDC_SZ_DECIMALS),
+		ToPerp:           toPerp,
+		Nonce:            nonce,
+	}
+	v, r, s, err := api.SignPerpDexClassTransferAction(action)
+	if err != nil {
+		api.debug("Error signing perpDexClassTransfer action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:    action,
+		Nonce:     nonce,
+		Signature: ToTypedSig(r, s, v),
+	}
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
+}
+
+// Transfer USDC between the perp balances of the master account and a sub-account
+// If isDeposit is true the amount is moved from the master account to the sub-account.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#transfer-to-and-from-sub-account
+func (api *ExchangeAPI) SubAccountTransfer(subAccount string, isDeposit bool, usd float64) (*DefaultExchangeResponse, error) {
+	timestamp := GetNonce()
+	action := SubAccountTransferAction{
+		Type:           "subAccountTransfer",
+		SubAccountUser: subAccount,
+		IsDeposit:      isDeposit,
+		Usd:            UsdToWireInt(usd),
+	}
+	v, r, s, err := api.SignL1ActionAsUser(action, timestamp)
+	if err != nil {
+		api.debug("Error signing L1 action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:    action,
+		Nonce:     timestamp,
+		Signature: ToTypedSig(r, s, v),
+	}
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
+}
+
+// Create a sub-account with the given name
+// The address of the new sub-account is returned in Response.Data, it can be used with SetAccountAddress or SetVaultAddress.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#create-sub-account
+func (api *ExchangeAPI) CreateSubAccount(name string) (*CreateSubAccountResponse, error) {
+	timestamp := GetNonce()
+	action := CreateSubAccountAction{
+		Type: "createSubAccount",
+		Name: name,
+	}
+	v, r, s, err := api.SignL1ActionAsUser(action, timestamp)
+	if err != nil {
+		api.debug("Error signing L1 action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:    action,
+		Nonce:     timestamp,
+		Signature: ToTypedSig(r, s, v),
+	}
+	return MakeUniversalRequest[CreateSubAccountResponse](api, request)
+}
+
+// Transfer a spot token between the master account and a sub-account
+// If isDeposit is true the amount is moved from the master account to the sub-account.
+// The token is the name of the token (e.g. "PURR"), the amount is rounded to the token wei decimals.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#transfer-to-and-from-sub-account
+func (api *ExchangeAPI) SubAccountSpotTransfer(subAccount string, isDeposit bool, token string, amount float64) (*DefaultExchangeResponse, error) {
+	info, err := api.spotTokenInfo(token)
+	if err != nil {
+		return nil, err
+	}
+	timestamp := GetNonce()
+	action := SubAccountSpotTransferAction{
+		Type:           "subAccountSpotTransfer",
+		SubAccountUser: subAccount,
+		IsDeposit:      isDeposit,
+		Token:          fmt.Sprintf("%s:%s", token, info.TokenID),
+		Amount:         SizeToWire(amount, info.WeiDecimals),
+	}
+	v, r, s, err := api.SignL1ActionAsUser(action, timestamp)
+	if err != nil {
+		api.debug("Error signing L1 action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:    action,
+		Nonce:     timestamp,
+		Signature: ToTypedSig(r, s, v),
+	}
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
+}
+
+// Deposit USDC into or withdraw USDC from a vault
+// If isDeposit is false the usd amount is withdrawn from the vault.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#deposit-or-withdraw-from-a-vault
+func (api *ExchangeAPI) VaultTransfer(vaultAddress string, isDeposit bool, usd float64) (*DefaultExchangeResponse, error) {
+	timestamp := GetNonce()
+	action := VaultTransferAction{
+		Type:         "vaultTransfer",
+		VaultAddress: vaultAddress,
+		IsDeposit:    isDeposit,
+		Usd:          UsdToWireInt(usd),
+	}
+	v, r, s, err := api.SignL1ActionAsUser(action, timestamp)
+	if err != nil {
+		api.debug("Error signing L1 action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:    action,
+		Nonce:     timestamp,
+		Signature: ToTypedSig(r, s, v),
+	}
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
+}
+
+// Create a vault led by the account
+// The initialUsd amount is deposited by the leader and must be at least 100 USDC.
+// The address of the new vault is returned in Response.Data.
+func (api *ExchangeAPI) CreateVault(name, description string, initialUsd float64) (*CreateVaultResponse, error) {
+	timestamp := GetNonce()
+	action := CreateVaultAction{
+		Type:        "createVault",
+		Name:        name,
+		Description: description,
+		InitialUsd:  UsdToWireInt(initialUsd),
+		Nonce:       timestamp,
+	}
+	v, r, s, err := api.SignL1ActionAsUser(action, timestamp)
+	if err != nil {
+		api.debug("Error signing L1 action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:    action,
+		Nonce:     timestamp,
+		Signature: ToTypedSig(r, s, v),
+	}
+	return MakeUniversalRequest[CreateVaultResponse](api, request)
+}
+
+// Modify the configuration of a vault led by the account
+// A nil allowDeposits or alwaysCloseOnWithdraw keeps the current value of the setting.
+func (api *ExchangeAPI) VaultModify(vaultAddress string, allowDeposits *bool, alwaysCloseOnWithdraw *bool) (*DefaultExchangeResponse, error) {
+	timestamp := GetNonce()
+	action := VaultModifyAction{
+		Type:                  "vaultModify",
+		VaultAddress:          vaultAddress,
+		AllowDeposits:         allowDeposits,
+		AlwaysCloseOnWithdraw: alwaysCloseOnWithdraw,
+	}
+	v, r, s, err := api.SignL1ActionAsUser(action, timestamp)
+	if err != nil {
+		api.debug("Error signing L1 action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:    action,
+		Nonce:     timestamp,
+		Signature: ToTypedSig(r, s, v),
+	}
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
+}
+
+// Distribute USDC from a vault led by the account to its depositors
+// The leader commission is paid out as part of the distribution.
+// An usd amount of 0 distributes the whole vault equity and closes the vault.
+func (api *ExchangeAPI) VaultDistribute(vaultAddress string, usd float64) (*DefaultExchangeResponse, error) {
+	timestamp := GetNonce()
+	action := VaultDistributeAction{
+		Type:         "vaultDistribute",
+		VaultAddress: vaultAddress,
+		Usd:          UsdToWireInt(usd),
+	}
+	v, r, s, err := api.SignL1ActionAsUser(action, timestamp)
+	if err != nil {
+		api.debug("Error signing L1 action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:    action,
+		Nonce:     timestamp,
+		Signature: ToTypedSig(r, s, v),
+	}
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
+}
+
+// Withdraw the leader equity from a vault led by the account
+// The leader must keep at least 5% of the vault equity while the vault is open,
+// use VaultDistribute with an amount of 0 to close the vault instead.
+func (api *ExchangeAPI) VaultLeaderWithdraw(vaultAddress string, usd float64) (*DefaultExchangeResponse, error) {
+	return api.VaultTransfer(vaultAddress, false, usd)
+}
+
+// Approve an agent (API wallet) to sign L1 actions on behalf of the account
+// The agentName is optional, an unnamed agent replaces the previous unnamed agent.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#approve-an-api-wallet
+func (api *ExchangeAPI) ApproveAgent(agentAddress string, agentName string) (*DefaultExchangeResponse, error) {
+	nonce := GetNonce()
+	signatureChainID, chainType := api.getChainParams()
+	action := ApproveAgentAction{
+		Type:             "approveAgent",
+		SignatureChainID: signatureChainID,
+		HyperliquidChain: chainType,
+		AgentAddress:     agentAddress,
+		AgentName:        agentName,
+		Nonce:            nonce,
+	}
+	v, r, s, err := api.SignApproveAgentAction(action)
+	if err != nil {
+		api.debug("Error signing approveAgent action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:    action,
+		Nonce:     nonce,
+		Signature: ToTypedSig(r, s, v),
+	}
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
+}
+
+// Generate a new agent key, approve it and return an ExchangeAPI signing with the agent key
+// The returned ExchangeAPI keeps the account and vault addresses of api.
+// The agent private key can be saved from KeyManager().PrivateKeyStr to reuse the agent later.
+func (api *ExchangeAPI) ApproveNewAgent(agentName string) (*ExchangeAPI, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	agentAPI := *api
+	if err := agentAPI.SetPrivateKey(hex.EncodeToString(crypto.FromECDSA(key))); err != nil {
+		return nil, err
+	}
+	if _, err := api.ApproveAgent(agentAPI.KeyManager().PublicAddressHex(), agentName); err != nil {
+		return nil, err
+	}
+	return &agentAPI, nil
+}
+
+// Approve a maximum fee rate for a builder
+// The maxFeeRate is a percent string (e.g. "0.01%"), the approval is required before sending orders with the builder.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#approve-a-builder-fee
+func (api *ExchangeAPI) ApproveBuilderFee(builder string, maxFeeRate string) (*DefaultExchangeResponse, error) {
+	nonce := GetNonce()
+	signatureChainID, chainType := api.getChainParams()
+	action := ApproveBuilderFeeAction{
+		Type:             "approveBuilderFee",
+		SignatureChainID: signatureChainID,
+		HyperliquidChain: chainType,
+		MaxFeeRate:       maxFeeRate,
+		Builder:          builder,
+		Nonce:            nonce,
+	}
+	v, r, s, err := api.SignApproveBuilderFeeAction(action)
+	if err != nil {
+		api.debug("Error signing approveBuilderFee action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:    action,
+		Nonce:     nonce,
+		Signature: ToTypedSig(r, s, v),
+	}
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
+}
+
+// Set the referral code used by the account
+// The referrer can only be set once, before the account has traded.
+func (api *ExchangeAPI) SetReferrer(code string) (*DefaultExchangeResponse, error) {
+	timestamp := GetNonce()
+	action := SetReferrerAction{
+		Type: "setReferrer",
+		Code: code,
+	}
+	v, r, s, err := api.SignL1ActionAsUser(action, timestamp)
+	if err != nil {
+		api.debug("Error signing L1 action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:    action,
+		Nonce:     timestamp,
+		Signature: ToTypedSig(r, s, v),
+	}
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
+}
+
+// Register a referral code for the account
+// Other accounts can then use the code with SetReferrer.
+func (api *ExchangeAPI) RegisterReferrer(code string) (*DefaultExchangeResponse, error) {
+	timestamp := GetNonce()
+	action := RegisterReferrerAction{
+		Type: "registerReferrer",
+		Code: code,
+	}
+	v, r, s, err := api.SignL1ActionAsUser(action, timestamp)
+	if err != nil {
+		api.debug("Error signing L1 action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:    action,
+		Nonce:     timestamp,
+		Signature: ToTypedSig(r, s, v),
+	}
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
+}
+
+// Delegate staked HYPE to a validator or undelegate it
+// The wei amount is in the HYPE wei decimals (e.g. 1 HYPE = 100000000 wei).
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#delegate-or-undelegate-stake-from-validator
+func (api *ExchangeAPI) TokenDelegate(validator string, wei uint64, isUndelegate bool) (*DefaultExchangeResponse, error) {
+	nonce := GetNonce()
+	signatureChainID, chainType := api.getChainParams()
+	action := TokenDelegateAction{
+		Type:             "tokenDelegate",
+		SignatureChainID: signatureChainID,
+		HyperliquidChain: chainType,
+		Validator:        validator,
+		Wei:              wei,
+		IsUndelegate:     isUndelegate,
+		Nonce:            nonce,
+	}
+	v, r, s, err := api.SignTokenDelegateAction(action)
+	if err != nil {
+		api.debug("Error signing tokenDelegate action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:    action,
+		Nonce:     nonce,
+		Signature: ToTypedSig(r, s, v),
+	}
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
+}
+
+// Move HYPE from the spot balance to the staking balance
+// The wei amount is in the HYPE wei decimals (e.g. 1 HYPE = 100000000 wei).
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#deposit-into-staking
+func (api *ExchangeAPI) CDeposit(wei uint64) (*DefaultExchangeResponse, error) {
+	return api.stakingTransfer("cDeposit", wei)
+}
+
+// Move HYPE from the staking balance back to the spot balance
+// The withdrawal is subject to the staking unstaking queue.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#withdraw-from-staking
+func (api *ExchangeAPI) CWithdraw(wei uint64) (*DefaultExchangeResponse, error) {
+	return api.stakingTransfer("cWithdraw", wei)
+}
+
+func (api *ExchangeAPI) stakingTransfer(actionType string, wei uint64) (*DefaultExchangeResponse, error) {
+	nonce := GetNonce()
+	signatureChainID, chainType := api.getChainParams()
+	action := StakingTransferAction{
+		Type:             actionType,
+		SignatureChainID: signatureChainID,
+		HyperliquidChain: chainType,
+		Wei:              wei,
+		Nonce:            nonce,
+	}
+	v, r, s, err := api.SignStakingTransferAction(action)
+	if err != nil {
+		api.debug("Error signing %s action: %s", actionType, err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:    action,
+		Nonce:     nonce,
+		Signature: ToTypedSig(r, s, v),
+	}
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
+}
+
+// Reserve additional request weight for the account
+// Each unit of weight costs 0.0005 USDC, taken from the perp balance.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#reserve-additional-actions
+func (api *ExchangeAPI) ReserveRequestWeight(weight int) (*DefaultExchangeResponse, error) {
+	if weight <= 0 {
+		return nil, APIError{Message: fmt.Sprintf("Invalid weight: %d", weight)}
+	}
+	timestamp := GetNonce()
+	action := ReserveRequestWeightAction{
+		Type:   "reserveRequestWeight",
+		Weight: weight,
+	}
+	v, r, s, err := api.SignL1ActionAsUser(action, timestamp)
+	if err != nil {
+		api.debug("Error signing L1 action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:    action,
+		Nonce:     timestamp,
+		Signature: ToTypedSig(r, s, v),
+	}
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
+}
+
+// Send a noop action with the given nonce
+// It marks the nonce as used, so a pending action signed with the same nonce can no longer be executed.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#invalidate-pending-nonce-noop
+func (api *ExchangeAPI) Noop(nonce uint64) (*DefaultExchangeResponse, error) {
+	action := NoopAction{
+		Type: "noop",
+	}
+	v, r, s, err := api.SignL1Action(action, nonce)
+	if err != nil {
+		api.debug("Error signing L1 action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:       action,
+		Nonce:        nonce,
+		Signature:    ToTypedSig(r, s, v),
+		VaultAddress: api.VaultAddress(),
+	}
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
+}
+
+// Convert the account to a multi-sig user
+// Once converted, actions of the account must be signed by threshold of the signers, see GetMultiSigSigners.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/hypercore/multi-sig
+func (api *ExchangeAPI) ConvertToMultiSigUser(signers []string, threshold int) (*DefaultExchangeResponse, error) {
+	if threshold <= 0 || threshold > len(signers) {
+		return nil, APIError{Message: fmt.Sprintf("Invalid threshold %d for %d signers", threshold, len(signers))}
+	}
+	authorizedUsers := make([]string, len(signers))
+	for i, signer := range signers {
+		authorizedUsers[i] = strings.ToLower(signer)
+	}
+	sort.Strings(authorizedUsers)
+	signersJSON, err := json.Marshal(MultiSigSigners{AuthorizedUsers: authorizedUsers, Threshold: threshold})
+	if err != nil {
+		return nil, err
+	}
+	nonce := GetNonce()
+	signatureChainID, chainType := api.getChainParams()
+	action := ConvertToMultiSigUserAction{
+		Type:             "convertToMultiSigUser",
+		SignatureChainID: signatureChainID,
+		HyperliquidChain: chainType,
+		Signers:          string(signersJSON),
+		Nonce:            nonce,
+	}
+	v, r, s, err := api.SignConvertToMultiSigUserAction(action)
+	if err != nil {
+		api.debug("Error signing convertToMultiSigUser action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:    action,
+		Nonce:     nonce,
+		Signature: ToTypedSig(r, s, v),
+	}
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
+}
+
+// Submit an action of a multi-sig user
+// The signatures are the signatures of the inner action collected from the authorized users,
+// they must have been made with the same nonce and with the api key as the outer signer.
+// The api key must belong to one of the authorized users, it signs the outer multiSig action.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/hypercore/multi-sig
+func (api *ExchangeAPI) MultiSig(multiSigUser string, innerAction any, signatures []RsvSignature, nonce uint64) (*MultiSigResponse, error) {
+	if api.KeyManager() == nil {
+		return nil, APIError{Message: "API key not set"}
+	}
+	signatureChainID, _ := api.getChainParams()
+	action := MultiSigAction{
+		Type:             "multiSig",
+		SignatureChainID: signatureChainID,
+		Signatures:       signatures,
+		Payload: MultiSigPayload{
+			MultiSigUser: strings.ToLower(multiSigUser),
+			OuterSigner:  strings.ToLower(api.KeyManager().PublicAddressHex()),
+			Action:       innerAction,
+		},
+	}
+	v, r, s, err := api.SignMultiSigAction(action, nonce)
+	if err != nil {
+		api.debug("Error signing multiSig action: %s", err)
+		return nil, err
+	}
+	request := ExchangeRequest{
+		Action:       action,
+		Nonce:        nonce,
+		Signature:    ToTypedSig(r, s, v),
+		VaultAddress: api.VaultAddress(),
+	}
+	return MakeUniversalRequest[MultiSigResponse](api, request)
+}
+
+// Register a new spot token as the winner of the spot deploy gas auction
+// The maxGas is the maximum gas (in HYPE wei) the deployer is willing to pay for the auction.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/deploying-hip-1-and-hip-2-assets
+func (api *ExchangeAPI) SpotDeployRegisterToken(name string, szDecimals int, weiDecimals int, maxGas int, fullName string) (*DefaultExchangeResponse, error) {
+	return api.spotDeploy(SpotDeployAction{
+		Type: "spotDeploy",
+		RegisterToken2: &SpotDeployRegisterToken2{
+			Spec: SpotDeployTokenSpec{
+				Name:        name,
+				SzDecimals:  szDecimals,
+				WeiDecimals: weiDecimals,
+			},
+			MaxGas:   maxGas,
+			FullName: fullName,
+		},
+	})
+}
+
+// Set the genesis balances of a deployed spot token
+// The userAndWei balances are credited to users, the existingTokenAndWei balances
+// are credited to the holders of existing tokens pro rata.
+// It can be called several times before SpotDeployGenesis.
+func (api *ExchangeAPI) SpotDeployUserGenesis(token int, userAndWei []UserGenesisBalance, existingTokenAndWei []TokenGenesisBalance) (*DefaultExchangeResponse, error) {
+	users := make([][]any, len(userAndWei))
+	for i, balance := range userAndWei {
+		users[i] = []any{strings.ToLower(balance.User), balance.Wei}
+	}
+	tokens := make([][]any, len(existingTokenAndWei))
+	for i, balance := range existingTokenAndWei {
+		tokens[i] = []any{balance.Token, balance.Wei}
+	}
+	return api.spotDeploy(SpotDeployAction{
+		Type: "spotDeploy",
+		UserGenesis: &SpotDeployUserGenesis{
+			Token:               token,
+			UserAndWei:          users,
+			ExistingTokenAndWei: tokens,
+		},
+	})
+}
+
+// Finalize the genesis of a deployed spot token
+// The maxSupply (in wei) must equal the sum of the genesis balances.
+// If noHyperliquidity is true the token is deployed without the Hyperliquidity market maker.
+func (api *ExchangeAPI) SpotDeployGenesis(token int, maxSupply string, noHyperliquidity bool) (*DefaultExchangeResponse, error) {
+	return api.spotDeploy(SpotDeployAction{
+		Type: "spotDeploy",
+		Genesis: &SpotDeployGenesis{
+			Token:            token,
+			MaxSupply:        maxSupply,
+			NoHyperliquidity: noHyperliquidity,
+		},
+	})
+}
+
+// Register the spot pair of a deployed token
+// The quoteToken is usually USDC (token 0).
+func (api *ExchangeAPI) SpotDeployRegisterSpot(baseToken int, quoteToken int) (*DefaultExchangeResponse, error) {
+	return api.spotDeploy(SpotDeployAction{
+		Type: "spotDeploy",
+		RegisterSpot: &SpotDeployRegisterSpot{
+			Tokens: [2]int{baseToken, quoteToken},
+		},
+	})
+}
+
+// Register the Hyperliquidity market maker of a spot pair
+// Hyperliquidity places nOrders orders of orderSz starting from startPx.
+// nSeededLevels is optional, it is the number of levels the deployer seeds with USDC.
+func (api *ExchangeAPI) SpotDeployRegisterHyperliquidity(spot int, startPx float64, orderSz float64, nOrders int, nSeededLevels *int) (*DefaultExchangeResponse, error) {
+	return api.spotDeploy(SpotDeployAction{
+		Type: "spotDeploy",
+		RegisterHyperliquidity: &SpotDeployRegisterHyperliquidity{
+			Spot:          spot,
+			StartPx:       strconv.FormatFloat(startPx, 'f', -1, 64),
+			OrderSz:       strconv.FormatFloat(orderSz, 'f', -1, 64),
+			NOrders:       nOrders,
+			NSeededLevels: nSeededLevels,
+		},
+	})
+}
+
+// Set the share of the trading fees of a spot token paid to the deployer
+// The share is a percent string (e.g. "50%"), it can only be decreased.
+func (api *ExchangeAPI) SpotDeploySetDeployerTradingFeeShare(token int, share string) (*DefaultExchangeResponse, error) {
+	return api.spotDeploy(SpotDeployAction{
+		Type: "spotDeploy",
+		SetDeployerTradingFeeShare: &SpotDeploySetDeployerTradingFeeShare{
+			Token: token,
+			Share: share,
+		},
+	})
+}
+
+func (api *ExchangeAPI) spotDeploy(action SpotDeployAction) (*DefaultExchangeResponse, error) {
 	timestamp := GetNonce()
-	action := CancelOidOrderAction{
-		Type:    "cancel",
-		Cancels: cancels,
-	}
-	v, r, s, err := api.SignL1Action(action, timestamp)
+	v, r, s, err := api.SignL1ActionAsUser(action, timestamp)
 	if err != nil {
 		api.debug("Error signing L1 action: %s", err)
 		return nil, err
 	}
 	request := ExchangeRequest{
-		Action:       action,
-		Nonce:        timestamp,
-		Signature:    ToTypedSig(r, s, v),
-		VaultAddress: api.VaultAddress(),
+		Action:    action,
+		Nonce:     timestamp,
+		Signature: ToTypedSig(r, s, v),
 	}
-	return MakeUniversalRequest[OrderResponse](api, request)
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
 }
 
-// Bulk modify orders
-// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#modify-multiple-orders
-func (api *ExchangeAPI) BulkModifyOrders(modifyRequests []OrderRequest) (*OrderResponse, error) {
-	wires := []ModifyOrderWire{}
-
-	for _, req := range modifyRequests {
-		info := api.GetMeta(req)
-		wires = append(wires, req.ToModifyWire(info))
-	}
-	action := ModifyOrderAction{
-		Type:     "batchModify",
-		Modifies: wires,
+// Register an asset on a builder-deployed perp dex
+// The maxGas (in HYPE wei) is only needed when bidding in the perp deploy gas auction, it can be nil otherwise.
+// The schema is only set when registering the first asset, which creates the dex.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/deploying-hip-3-assets
+func (api *ExchangeAPI) PerpDeployRegisterAsset(dex string, maxGas *int, coin string, szDecimals int, oraclePx string, marginTableID int, onlyIsolated bool, schema *PerpDexSchema) (*DefaultExchangeResponse, error) {
+	if schema != nil && schema.OracleUpdater != nil {
+		oracleUpdater := strings.ToLower(*schema.OracleUpdater)
+		schema = &PerpDexSchema{FullName: schema.FullName, CollateralToken: schema.CollateralToken, OracleUpdater: &oracleUpdater}
 	}
+	return api.perpDeploy(PerpDeployAction{
+		Type: "perpDeploy",
+		RegisterAsset: &PerpDeployRegisterAsset{
+			MaxGas: maxGas,
+			AssetRequest: PerpDeployAssetRequest{
+				Coin:          coin,
+				SzDecimals:    szDecimals,
+				OraclePx:      oraclePx,
+				MarginTableID: marginTableID,
+				OnlyIsolated:  onlyIsolated,
+			},
+			Dex:    dex,
+			Schema: schema,
+		},
+	})
+}
 
-	timestamp := GetNonce()
-	vVal, rVal, sVal, signErr := api.SignL1Action(action, timestamp)
-	if signErr != nil {
-		return nil, signErr
-	}
-	request := ExchangeRequest{
-		Action:       action,
-		Nonce:        timestamp,
-		Signature:    ToTypedSig(rVal, sVal, vVal),
-		VaultAddress: api.VaultAddress(),
+// Update the oracle and mark prices of the assets of a builder-deployed perp dex
+// The prices are keyed by coin, markPxs may contain several sets of mark prices that are combined by the exchange.
+// externalPerpPxs are the prices of the assets on external perp venues.
+func (api *ExchangeAPI) PerpDeploySetOracle(dex string, oraclePxs map[string]string, markPxs []map[string]string, externalPerpPxs map[string]string) (*DefaultExchangeResponse, error) {
+	markPxsWire := make([][][2]string, len(markPxs))
+	for i, pxs := range markPxs {
+		markPxsWire[i] = sortedPxs(pxs)
 	}
-	return MakeUniversalRequest[OrderResponse](api, request)
+	return api.perpDeploy(PerpDeployAction{
+		Type: "perpDeploy",
+		SetOracle: &PerpDeploySetOracle{
+			Dex:             dex,
+			OraclePxs:       sortedPxs(oraclePxs),
+			MarkPxs:         markPxsWire,
+			ExternalPerpPxs: sortedPxs(externalPerpPxs),
+		},
+	})
 }
 
-// Bulk modify orders
-// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#modify-multiple-orders
-func (api *ExchangeAPI) BulkModifyOrdersByCloid(modifyRequests []OrderRequest) (*OrderResponse, error) {
-	wires := []ModifyOrderByCloidWire{}
-
-	for _, req := range modifyRequests {
-		info := api.GetMeta(req)
-		wires = append(wires, req.ToModifyByCloidWire(info))
-	}
-	action := ModifyOrderByCloidAction{
-		Type:     "batchModify",
-		Modifies: wires,
+// sortedPxs returns the prices as [coin, px] pairs sorted by coin, as expected by the exchange.
+func sortedPxs(pxs map[string]string) [][2]string {
+	pairs := make([][2]string, 0, len(pxs))
+	for coin, px := range pxs {
+		pairs = append(pairs, [2]string{coin, px})
 	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i][0] < pairs[j][0] })
+	return pairs
+}
 
+func (api *ExchangeAPI) perpDeploy(action PerpDeployAction) (*DefaultExchangeResponse, error) {
 	timestamp := GetNonce()
-	vVal, rVal, sVal, signErr := api.SignL1Action(action, timestamp)
-	if signErr != nil {
-		return nil, signErr
+	v, r, s, err := api.SignL1ActionAsUser(action, timestamp)
+	if err != nil {
+		api.debug("Error signing L1 action: %s", err)
+		return nil, err
 	}
 	request := ExchangeRequest{
-		Action:       action,
-		Nonce:        timestamp,
-		Signature:    ToTypedSig(rVal, sVal, vVal),
-		VaultAddress: api.VaultAddress(),
+		Action:    action,
+		Nonce:     timestamp,
+		Signature: ToTypedSig(r, s, v),
 	}
-	return MakeUniversalRequest[OrderResponse](api, request)
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
 }
 
-// Cancel exact order by Client Order Id
-// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#cancel-order-s-by-cloid
-func (api *ExchangeAPI) CancelOrderByCloid(coin string, clientOID string) (*OrderResponse, error) {
+// Switch the HyperEVM transactions of the account between small and big blocks
+// Big blocks have a higher gas limit and are used to deploy large contracts.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/hyperevm/dual-block-architecture
+func (api *ExchangeAPI) SetUsingBigBlocks(enable bool) (*DefaultExchangeResponse, error) {
 	timestamp := GetNonce()
-	action := CancelCloidOrderAction{
-		Type: "cancelByCloid",
-		Cancels: []CancelCloidWire{
-			{
-				Asset: api.meta[coin].AssetID,
-				Cloid: clientOID,
-			},
-		},
+	action := EvmUserModifyAction{
+		Type:           "evmUserModify",
+		UsingBigBlocks: enable,
 	}
-	v, r, s, err := api.SignL1Action(action, timestamp)
+	v, r, s, err := api.SignL1ActionAsUser(action, timestamp)
 	if err != nil {
 		api.debug("Error signing L1 action: %s", err)
 		return nil, err
 	}
 	request := ExchangeRequest{
-		Action:       action,
-		Nonce:        timestamp,
-		Signature:    ToTypedSig(r, s, v),
-		VaultAddress: api.VaultAddress(),
+		Action:    action,
+		Nonce:     timestamp,
+		Signature: ToTypedSig(r, s, v),
 	}
-	return MakeUniversalRequest[OrderResponse](api, request)
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
 }
 
-// Update leverage for a coin
-// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#update-leverage
-func (api *ExchangeAPI) UpdateLeverage(coin string, isCross bool, leverage int) (*DefaultExchangeResponse, error) {
+// Claim the pending referral and staking rewards of the account
+// The rewards are credited to the spot balance.
+func (api *ExchangeAPI) ClaimRewards() (*DefaultExchangeResponse, error) {
 	timestamp := GetNonce()
-	action := UpdateLeverageAction{
-		Type:     "updateLeverage",
-		Asset:    api.meta[coin].AssetID,
-		IsCross:  isCross,
-		Leverage: leverage,
+	action := ClaimRewardsAction{
+		Type: "claimRewards",
 	}
-	v, r, s, err := api.SignL1Action(action, timestamp)
+	v, r, s, err := api.SignL1ActionAsUser(action, timestamp)
 	if err != nil {
 		api.debug("Error signing L1 action: %s", err)
 		return nil, err
 	}
 	request := ExchangeRequest{
-		Action:       action,
-		Nonce:        timestamp,
-		Signature:    ToTypedSig(r, s, v),
-		VaultAddress: api.VaultAddress(),
+		Action:    action,
+		Nonce:     timestamp,
+		Signature: ToTypedSig(r, s, v),
 	}
 	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
 }
 
-// Initiate a withdraw request
-// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#initiate-a-withdrawal-request
-func (api *ExchangeAPI) Withdraw(destination string, amount float64) (*WithdrawResponse, error) {
-	nonce := GetNonce()
-	action := WithdrawAction{
-		Type:        "withdraw3",
-		Destination: destination,
-		Amount:      SizeToWire(amount, USDC_SZ_DECIMALS),
-		Time:        nonce,
+// Jail the validator whose signer is the api key
+// A jailed validator stops voting and earning rewards, it is used before maintenance of the node.
+func (api *ExchangeAPI) CSignerJailSelf() (*DefaultExchangeResponse, error) {
+	return api.validatorAction(CSignerJailSelfAction{Type: "CSignerAction"})
+}
+
+// Unjail the validator whose signer is the api key
+func (api *ExchangeAPI) CSignerUnjailSelf() (*DefaultExchangeResponse, error) {
+	return api.validatorAction(CSignerUnjailSelfAction{Type: "CSignerAction"})
+}
+
+// Change the profile of the validator of the account
+// Only the non-nil fields of the change are updated.
+func (api *ExchangeAPI) CValidatorChangeProfile(change ValidatorProfileChange) (*DefaultExchangeResponse, error) {
+	if change.Signer != nil {
+		signer := strings.ToLower(*change.Signer)
+		change.Signer = &signer
 	}
-	signatureChainID, chainType := api.getChainParams()
-	action.HyperliquidChain = chainType
-	action.SignatureChainID = signatureChainID
-	v, r, s, err := api.SignWithdrawAction(action)
+	return api.validatorAction(CValidatorChangeProfileAction{
+		Type:          "CValidatorAction",
+		ChangeProfile: change,
+	})
+}
+
+// Rotate the signer key of the validator of the account
+// The new signer is used by the node to sign votes and CSignerAction actions.
+func (api *ExchangeAPI) CValidatorRotateSigner(signer string) (*DefaultExchangeResponse, error) {
+	return api.CValidatorChangeProfile(ValidatorProfileChange{Signer: &signer})
+}
+
+func (api *ExchangeAPI) validatorAction(action any) (*DefaultExchangeResponse, error) {
+	timestamp := GetNonce()
+	v, r, s, err := api.SignL1ActionAsUser(action, timestamp)
 	if err != nil {
-		api.debug("Error signing withdraw action: %s", err)
+		api.debug("Error signing L1 action: %s", err)
 		return nil, err
 	}
-	request := &ExchangeRequest{
-		Action:       action,
-		Nonce:        nonce,
-		Signature:    ToTypedSig(r, s, v),
-		VaultAddress: api.VaultAddress(),
+	request := ExchangeRequest{
+		Action:    action,
+		Nonce:     timestamp,
+		Signature: ToTypedSig(r, s, v),
 	}
-	return MakeUniversalRequest[WithdrawResponse](api, request)
+	return MakeUniversalRequest[DefaultExchangeResponse](api, request)
 }
 
 //
@@ -316,6 +1861,77 @@ func (api *ExchangeAPI) Order(request OrderRequest, grouping Grouping) (*OrderRe
 	return api.BulkOrders([]OrderRequest{request}, grouping)
 }
 
+// Place an entry order with its take profit and stop loss orders in a single action
+// The grouping is GroupingNormalTpSl or GroupingPositionTpSl, see TpSlOrderRequest.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/exchange-endpoint#place-an-order
+func (api *ExchangeAPI) OrderWithTpSl(request TpSlOrderRequest, grouping Grouping) (*OrderResponse, error) {
+	if err := request.Validate(grouping); err != nil {
+		return nil, err
+	}
+	return api.BulkOrders(request.Requests(), grouping)
+}
+
+// Place a bracket order: a limit entry order with a take profit and a stop loss
+// The size is positive for a long entry and negative for a short entry.
+// The take profit and stop loss are market trigger orders for the entry size, they are active once the entry is filled.
+func (api *ExchangeAPI) BracketOrder(coin string, size float64, entryPx float64, tpPx float64, slPx float64) (*BracketOrderResult, error) {
+	info, ok := api.meta[coin]
+	if !ok {
+		return nil, APIError{Message: fmt.Sprintf("Unknown coin: %s", coin)}
+	}
+	isBuy := IsBuy(size)
+	if isBuy && !(tpPx > entryPx && entryPx > slPx) || !isBuy && !(tpPx < entryPx && entryPx < slPx) {
+		return nil, APIError{Message: fmt.Sprintf("Invalid bracket prices: entry %v, take profit %v, stop loss %v", entryPx, tpPx, slPx)}
+	}
+	trigger := func(px float64, tpsl TpSl) *OrderRequest {
+		return &OrderRequest{
+			Coin:  coin,
+			IsBuy: !isBuy,
+			Sz:    math.Abs(size),
+			// The limit price of a market trigger order is its maximum slippage
+			LimitPx: CalculateSlippage(!isBuy, px, DEFAULT_SLIPPAGE),
+			OrderType: OrderType{
+				Trigger: &TriggerOrderType{
+					IsMarket:  true,
+					TriggerPx: PriceToWire(px, PERP_MAX_DECIMALS, info.SzDecimals),
+					TpSl:      tpsl,
+				},
+			},
+			ReduceOnly: true,
+		}
+	}
+	request := TpSlOrderRequest{
+		Entry: &OrderRequest{
+			Coin:      coin,
+			IsBuy:     isBuy,
+			Sz:        math.Abs(size),
+			LimitPx:   entryPx,
+			OrderType: OrderType{Limit: &LimitOrderType{Tif: TifGtc}},
+		},
+		TakeProfit: trigger(tpPx, TriggerTp),
+		StopLoss:   trigger(slPx, TriggerSl),
+	}
+	res, err := api.OrderWithTpSl(request, GroupingNormalTpSl)
+	if err != nil {
+		return nil, err
+	}
+	statuses := res.Response.Data.Statuses
+	if len(statuses) != 3 {
+		return nil, APIError{Message: fmt.Sprintf("Unexpected bracket order statuses: %+v", statuses)}
+	}
+	for _, status := range statuses {
+		if err := status.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return &BracketOrderResult{
+		EntryOid:      statuses[0].OrderID(),
+		TakeProfitOid: statuses[1].OrderID(),
+		StopLossOid:   statuses[2].OrderID(),
+		Response:      res,
+	}, nil
+}
+
 // Endpoint returns the base endpoint for the /exchange service.
 func (api *ExchangeAPI) Endpoint() string {
 	return api.baseEndpoint
@@ -370,6 +1986,38 @@ func (api *ExchangeAPI) MarketOrder(coin string, size float64, slippage *float64
 	return api.Order(orderRequest, GroupingNa)
 }
 
+// MarketOrderSmart places a market order priced from the depth of the L2 book
+// instead of the mid price. The limit price is the worst level needed to fill the size,
+// and the order is aborted with an InsufficientLiquidityError if the book cannot fill it
+// within the slippage.
+//
+//	MarketOrderSmart("BTC", 0.1, nil) // Buy 0.1 BTC
+//	MarketOrderSmart("BTC", -0.1, &slippage) // Sell 0.1 BTC with slippage
+func (api *ExchangeAPI) MarketOrderSmart(coin string, size float64, slippage *float64, clientOID ...string) (*OrderResponse, error) {
+	slpg := GetSlippage(slippage)
+	isBuy := IsBuy(size)
+	finalPx, err := api.DepthPrice(coin, isBuy, math.Abs(size), slpg)
+	if err != nil {
+		return nil, err
+	}
+	orderRequest := OrderRequest{
+		Coin:    coin,
+		IsBuy:   isBuy,
+		Sz:      math.Abs(size),
+		LimitPx: finalPx,
+		OrderType: OrderType{
+			Limit: &LimitOrderType{
+				Tif: TifIoc,
+			},
+		},
+		ReduceOnly: false,
+	}
+	if len(clientOID) > 0 {
+		orderRequest.Cloid = clientOID[0]
+	}
+	return api.Order(orderRequest, GroupingNa)
+}
+
 // MarketOrderSpot is a market order for a spot coin.
 // It is used to buy/sell a spot coin.
 // Limit order with TIF=IOC and px=market price * (1 +- slippage).
@@ -398,6 +2046,81 @@ func (api *ExchangeAPI) MarketOrderSpot(coin string, size float64, slippage *flo
 	return api.OrderSpot(orderRequest, GroupingNa)
 }
 
+// SpotLimitOrder places a limit order on a spot pair given by its human name (e.g. "HYPE/USDC").
+// The pair is resolved from the spot meta, so the size and price are rounded with the decimals of the base token
+// and the order is sent for the @index asset of the pair.
+// Size determines the amount of the base token to buy/sell.
+// See the constants TifGtc, TifIoc, TifAlo.
+//
+//	SpotLimitOrder(TifGtc, "HYPE/USDC", 1.5, 25) // Buy 1.5 HYPE at 25 USDC
+//	SpotLimitOrder(TifGtc, "HYPE/USDC", -1.5, 30) // Sell 1.5 HYPE at 30 USDC
+func (api *ExchangeAPI) SpotLimitOrder(orderType string, pairName string, size float64, px float64, cloid ...string) (*OrderResponse, error) {
+	if orderType != TifGtc && orderType != TifIoc && orderType != TifAlo {
+		return nil, APIError{Message: fmt.Sprintf("Invalid order type: %s. Available types: %s, %s, %s", orderType, TifGtc, TifIoc, TifAlo)}
+	}
+	pair, err := api.infoAPI.GetSpotPair(pairName)
+	if err != nil {
+		return nil, err
+	}
+	return api.spotOrder(pair, size, px, orderType, cloid)
+}
+
+// SpotMarketOrder places a market order on a spot pair given by its human name (e.g. "HYPE/USDC").
+// Limit order with TIF=IOC and px=mid price of the pair * (1 +- slippage).
+// Size determines the amount of the base token to buy/sell.
+//
+//	SpotMarketOrder("HYPE/USDC", 1.5, nil) // Buy 1.5 HYPE
+//	SpotMarketOrder("HYPE/USDC", -1.5, &slippage) // Sell 1.5 HYPE with slippage
+func (api *ExchangeAPI) SpotMarketOrder(pairName string, size float64, slippage *float64, cloid ...string) (*OrderResponse, error) {
+	spotMetaAndCtxs, err := api.infoAPI.GetSpotMetaAndAssetCtxs()
+	if err != nil {
+		return nil, err
+	}
+	pair, ok := spotMetaAndCtxs.SpotMeta.Pair(pairName)
+	if !ok {
+		return nil, APIError{Message: fmt.Sprintf("Unknown spot pair: %s", pairName)}
+	}
+	var marketPx float64
+	for _, ctx := range spotMetaAndCtxs.AssetCtxs {
+		if ctx.Coin == pair.Coin && ctx.MidPx != nil {
+			marketPx = *ctx.MidPx
+		}
+	}
+	if marketPx == 0 {
+		return nil, APIError{Message: fmt.Sprintf("No mid price for spot pair %s", pair.Name)}
+	}
+	px := CalculateSlippage(IsBuy(size), marketPx, GetSlippage(slippage))
+	return api.spotOrder(&pair, size, px, TifIoc, cloid)
+}
+
+// spotOrder places a limit order on a resolved spot pair.
+func (api *ExchangeAPI) spotOrder(pair *SpotPair, size float64, px float64, tif string, cloid []string) (*OrderResponse, error) {
+	orderRequest := OrderRequest{
+		Coin:    pair.Coin,
+		IsBuy:   IsBuy(size),
+		Sz:      math.Abs(size),
+		LimitPx: px,
+		OrderType: OrderType{
+			Limit: &LimitOrderType{
+				Tif: tif,
+			},
+		},
+		spotAsset: &AssetInfo{
+			SzDecimals:  pair.SzDecimals,
+			WeiDecimals: pair.WeiDecimals,
+			AssetID:     pair.Index,
+			SpotName:    pair.Coin,
+		},
+	}
+	if len(cloid) > 0 {
+		if _, err := HexToInt(cloid[0]); err != nil {
+			return nil, err
+		}
+		orderRequest.Cloid = cloid[0]
+	}
+	return api.OrderSpot(orderRequest, GroupingNa)
+}
+
 // LimitOrder places a limit order
 // Size determines the amount of the coin to buy/sell.
 // See the constants TifGtc, TifIoc, TifAlo.
@@ -428,8 +2151,90 @@ func (api *ExchangeAPI) LimitOrder(orderType string, coin string, size float64,
 	return api.Order(orderRequest, GroupingNa)
 }
 
+// PostOnlyOrder places a post only (Alo) limit order.
+// If the order is rejected because it would have crossed the book, it is repriced one tick
+// away from the best bid/offer reported in the rejection (or from px) and placed again, up to reprices times.
+// The response of the last attempt is returned.
+//
+//	PostOnlyOrder("ETH", 0.1, 2000, false, 3) // Bid 0.1 ETH at 2000 or the best price below the ask
+func (api *ExchangeAPI) PostOnlyOrder(coin string, size float64, px float64, reduceOnly bool, reprices int) (*OrderResponse, error) {
+	isBuy := IsBuy(size)
+	maxDecimals := PERP_MAX_DECIMALS
+	if strings.ContainsAny(coin, "@-") {
+		maxDecimals = SPOT_MAX_DECIMALS
+	}
+	szDecimals := api.GetMeta(OrderRequest{Coin: coin}).SzDecimals
+	for attempt := 0; ; attempt++ {
+		res, err := api.LimitOrder(TifAlo, coin, size, px, reduceOnly)
+		if err != nil || attempt == reprices || len(res.Response.Data.Statuses) != 1 {
+			return res, err
+		}
+		msg := res.Response.Data.Statuses[0].Error
+		if !strings.HasPrefix(msg, postOnlyRejection) {
+			return res, nil
+		}
+		var bid, ask float64
+		if _, bbo, found := strings.Cut(msg, "bbo was "); found {
+			if n, err := fmt.Sscanf(bbo, "%g@%g", &bid, &ask); n != 2 {
+				api.debug("Failed to parse bbo %q of post only rejection: %s", bbo, err)
+			}
+		}
+		if isBuy {
+			if ask > 0 && ask < px {
+				px = ask
+			}
+			px -= PriceTick(px, maxDecimals, szDecimals)
+		} else {
+			if bid > px {
+				px = bid
+			}
+			px += PriceTick(px, maxDecimals, szDecimals)
+		}
+		px, _ = strconv.ParseFloat(PriceToWire(px, maxDecimals, szDecimals), 64)
+		api.debug("Post only order rejected: %s, repricing to %v", msg, px)
+	}
+}
+
 // Close all positions for a given coin. They are closing with a market order.
 func (api *ExchangeAPI) ClosePosition(coin string) (*OrderResponse, error) {
+	return api.closePosition(coin, func(positionSz float64) (float64, error) {
+		return positionSz, nil
+	})
+}
+
+// ClosePositionPartial closes sz of the position of coin with a reduce only market order.
+// The size is capped at the size of the position.
+//
+//	ClosePositionPartial("ETH", 0.5) // Close 0.5 ETH of the position
+func (api *ExchangeAPI) ClosePositionPartial(coin string, sz float64) (*OrderResponse, error) {
+	if sz <= 0 {
+		return nil, APIError{Message: fmt.Sprintf("Invalid size: %v", sz)}
+	}
+	return api.closePosition(coin, func(positionSz float64) (float64, error) {
+		return math.Min(sz, positionSz), nil
+	})
+}
+
+// ClosePositionPercent closes percent (0-100] of the position of coin with a reduce only market order.
+// The size is rounded down to the size decimals of the coin.
+//
+//	ClosePositionPercent("ETH", 25) // Close a quarter of the position
+func (api *ExchangeAPI) ClosePositionPercent(coin string, percent float64) (*OrderResponse, error) {
+	if percent <= 0 || percent > 100 {
+		return nil, APIError{Message: fmt.Sprintf("Invalid percent: %v", percent)}
+	}
+	return api.closePosition(coin, func(positionSz float64) (float64, error) {
+		factor := pow10(api.meta[coin].SzDecimals)
+		sz := math.Floor(positionSz*percent/100*factor) / factor
+		if sz <= 0 {
+			return 0, APIError{Message: fmt.Sprintf("%v%% of the %s position is below the minimum size", percent, coin)}
+		}
+		return sz, nil
+	})
+}
+
+// closePosition closes the size returned by size, given the absolute size of the position of coin.
+func (api *ExchangeAPI) closePosition(coin string, size func(positionSz float64) (float64, error)) (*OrderResponse, error) {
 	// Get all positions and find the one for the coin
 	// Then just make MarketOpen with the reverse size
 	state, err := api.infoAPI.GetUserState(api.AccountAddress())
@@ -446,9 +2251,12 @@ func (api *ExchangeAPI) ClosePosition(coin string) (*OrderResponse, error) {
 		if coin != item.Coin {
 			continue
 		}
-		size := item.Szi
+		sz, err := size(math.Abs(item.Szi))
+		if err != nil {
+			return nil, err
+		}
 		// reverse the position to close
-		isBuy := !IsBuy(size)
+		isBuy := !IsBuy(item.Szi)
 		finalPx := api.SlippagePrice(coin, isBuy, slippage)
 		orderType := OrderType{
 			Limit: &LimitOrderType{
@@ -458,7 +2266,7 @@ func (api *ExchangeAPI) ClosePosition(coin string) (*OrderResponse, error) {
 		orderRequest := OrderRequest{
 			Coin:       coin,
 			IsBuy:      isBuy,
-			Sz:         math.Abs(size),
+			Sz:         sz,
 			LimitPx:    finalPx,
 			OrderType:  orderType,
 			ReduceOnly: true,
@@ -483,6 +2291,29 @@ func (api *ExchangeAPI) CancelOrderByOID(coin string, orderID int) (*OrderRespon
 	return api.BulkCancelOrders([]CancelOidWire{{Asset: assetID, Oid: orderID}})
 }
 
+// CancelOrdersByCloid cancels the orders of coin identified by their client order ids
+// in a single cancelByCloid action, without resolving their oids first.
+//
+//	CancelOrdersByCloid("ETH", []string{"0x00000000000000000000000000000001", "0x00000000000000000000000000000002"})
+func (api *ExchangeAPI) CancelOrdersByCloid(coin string, cloids []string) (*OrderResponse, error) {
+	meta := api.meta
+	if strings.ContainsAny(coin, "@-") {
+		meta = api.spotMeta
+	}
+	info, ok := meta[coin]
+	if !ok {
+		return nil, APIError{Message: fmt.Sprintf("Unknown coin: %s", coin)}
+	}
+	cancels := make([]CancelCloidWire, 0, len(cloids))
+	for _, cloid := range cloids {
+		if _, err := HexToInt(cloid); err != nil {
+			return nil, err
+		}
+		cancels = append(cancels, CancelCloidWire{Asset: info.AssetID, Cloid: cloid})
+	}
+	return api.BulkCancelOrdersByCloid(cancels)
+}
+
 func (api *ExchangeAPI) BulkCancelOrdersByCloid(cancels []CancelCloidWire) (*OrderResponse, error) {
 	if len(cancels) == 0 {
 		return nil, APIError{Message: "no cloID entries provided"}
@@ -541,27 +2372,145 @@ func (api *ExchangeAPI) CancelAllOrders() (*OrderResponse, error) {
 	return api.BulkCancelOrders(cancels)
 }
 
+// KillSwitch is an emergency shutdown of the account (or vault if set):
+// it cancels every open order, closes every position with reduce only market orders
+// and arms a scheduleCancel in KILL_SWITCH_CANCEL_DELAY for the orders placed meanwhile.
+// Every step is run even if a previous one failed, see the report for the outcome.
+// The closes are not checked against the RiskGuard.
+func (api *ExchangeAPI) KillSwitch() *KillSwitchReport {
+	report := &KillSwitchReport{CloseErrors: map[string]string{}}
+	user := api.VaultAddress()
+	if user == "" {
+		user = api.AccountAddress()
+	}
+
+	// Cancel all open orders across coins
+	if orders, err := api.infoAPI.GetOpenOrders(user); err != nil {
+		report.CancelErr = err
+	} else if len(*orders) > 0 {
+		var cancels []CancelOidWire
+		var unknown []string
+		for _, order := range *orders {
+			asset, ok := api.openOrderAssetID(order.Coin)
+			if !ok {
+				unknown = append(unknown, fmt.Sprintf("%s oid %d", order.Coin, order.Oid))
+				continue
+			}
+			cancels = append(cancels, CancelOidWire{Asset: asset, Oid: int(order.Oid)})
+		}
+		if len(cancels) > 0 {
+			if res, err := api.BulkCancelOrders(cancels); err != nil {
+				report.CancelErr = err
+			} else {
+				for _, status := range res.Response.Data.Statuses {
+					if status.Error == "" {
+						report.CanceledOrders++
+					}
+				}
+			}
+		}
+		if report.CancelErr == nil && len(unknown) > 0 {
+			report.CancelErr = APIError{Message: fmt.Sprintf("Unknown coin of open orders: %s", strings.Join(unknown, ", "))}
+		}
+	}
+
+	// Close all positions in a single order action
+	if state, err := api.infoAPI.GetUserState(user); err != nil {
+		report.CloseErr = err
+	} else if mids, err := api.infoAPI.GetAllMids(); err != nil {
+		report.CloseErr = err
+	} else {
+		var closes []OrderRequest
+		for _, position := range state.AssetPositions {
+			item := position.Position
+			if item.Szi == 0 {
+				continue
+			}
+			if _, ok := api.openOrderAssetID(item.Coin); !ok {
+				report.CloseErrors[item.Coin] = "unknown coin"
+				continue
+			}
+			mid, err := strconv.ParseFloat((*mids)[item.Coin], 64)
+			if err != nil {
+				report.CloseErrors[item.Coin] = fmt.Sprintf("no mid price: %s", err)
+				continue
+			}
+			isBuy := !IsBuy(item.Szi)
+			closes = append(closes, OrderRequest{
+				Coin:       item.Coin,
+				IsBuy:      isBuy,
+				Sz:         math.Abs(item.Szi),
+				LimitPx:    CalculateSlippage(isBuy, mid, DEFAULT_SLIPPAGE),
+				OrderType:  OrderType{Limit: &LimitOrderType{Tif: TifIoc}},
+				ReduceOnly: true,
+			})
+		}
+		if len(closes) > 0 {
+			// The closes bypass the RiskGuard so that no rule can block the shutdown
+			if request, err := api.signBulkOrders(closes, GroupingNa); err != nil {
+				report.CloseErr = err
+			} else if res, err := MakeUniversalRequest[OrderResponse](api, *request); err != nil {
+				report.CloseErr = err
+			} else {
+				statuses := res.Response.Data.Statuses
+				for i, req := range closes {
+					if i < len(statuses) && statuses[i].Error != "" {
+						report.CloseErrors[req.Coin] = statuses[i].Error
+					} else {
+						report.ClosedPositions = append(report.ClosedPositions, req.Coin)
+					}
+				}
+			}
+		}
+	}
+
+	// Arm the dead man's switch for anything placed concurrently
+	cancelTime := time.Now().Add(KILL_SWITCH_CANCEL_DELAY).UnixMilli()
+	if res, err := api.ScheduleCancel(&cancelTime); err != nil {
+		report.ScheduleCancelErr = err
+	} else if res.Status != "ok" {
+		report.ScheduleCancelErr = APIError{Message: fmt.Sprintf("scheduleCancel failed with status %s", res.Status)}
+	} else {
+		report.ScheduleCancelTime = cancelTime
+	}
+	return report
+}
+
+// openOrderAssetID returns the asset id of the coin of an open order.
+// Spot orders are reported with the pair name (e.g. "PURR/USDC" or "@107").
+// Returns false if the coin is in neither the perp nor the spot meta.
+func (api *ExchangeAPI) openOrderAssetID(coin string) (int, bool) {
+	if info, ok := api.meta[coin]; ok {
+		return info.AssetID, true
+	}
+	for _, info := range api.spotMeta {
+		if info.SpotName == coin {
+			return info.AssetID + 10000, true
+		}
+	}
+	return 0, false
+}
+
 // GetMeta returns the asset info for the given request.
 // If the request is a spot request, it returns the spot meta map.
 // Returns empty AssetInfo if coin not found in meta map.
 func (api *ExchangeAPI) GetMeta(req OrderRequest) AssetInfo {
+	assetInfo, _ := api.lookupMeta(req)
+	return assetInfo
+}
+
+// lookupMeta returns the asset info for the given request, false if the coin is unknown.
+func (api *ExchangeAPI) lookupMeta(req OrderRequest) (AssetInfo, bool) {
 	if req.Coin == "" {
-		return AssetInfo{}
+		return AssetInfo{}, false
+	}
+	if req.spotAsset != nil {
+		return *req.spotAsset, true
 	}
-
 	meta := api.meta
 	if req.isSpot() {
-		if api.spotMeta == nil {
-			return AssetInfo{}
-		}
 		meta = api.spotMeta
-	} else if api.meta == nil {
-		return AssetInfo{}
 	}
-
 	assetInfo, exists := meta[req.Coin]
-	if !exists {
-		return AssetInfo{}
-	}
-	return assetInfo
+	return assetInfo, exists
 }
@@ -19,16 +19,19 @@ func (api *ExchangeAPI) SignUserSignableAction(action any, payloadTypes []apityp
 	if err != nil {
 		return 0, [32]byte{}, [32]byte{}, err
 	}
+	// The signatureChainId is the chain id of the signing domain
+	signatureChainID, _ := message["signatureChainId"].(string)
 	// Remove unnecessary fields for signing
 	delete(message, "type")
 	delete(message, "signatureChainId")
 
 	signRequest := &SignRequest{
-		DomainName:  "HyperliquidSignTransaction",
-		PrimaryType: primaryType,
-		DType:       payloadTypes,
-		DTypeMsg:    message,
-		IsMainNet:   api.IsMainnet(),
+		DomainName:       "HyperliquidSignTransaction",
+		PrimaryType:      primaryType,
+		DType:            payloadTypes,
+		DTypeMsg:         message,
+		IsMainNet:        api.IsMainnet(),
+		SignatureChainID: signatureChainID,
 	}
 	return api.Sign(signRequest)
 }
@@ -42,6 +45,17 @@ func (api *ExchangeAPI) SignL1Action(action any, timestamp uint64) (byte, [32]by
 	return api.Sign(srequest)
 }
 
+// SignL1ActionAsUser signs an L1 action for the account itself, ignoring the vault address.
+// It is used by the actions that can't be made on behalf of a vault (e.g. sub-account management).
+func (api *ExchangeAPI) SignL1ActionAsUser(action any, timestamp uint64) (byte, [32]byte, [32]byte, error) {
+	srequest, err := api.BuildEIP712Message(action, timestamp, "")
+	if err != nil {
+		api.debug("Error building EIP712 message: %s", err)
+		return 0, [32]byte{}, [32]byte{}, err
+	}
+	return api.Sign(srequest)
+}
+
 func (api *ExchangeAPI) BuildEIP712Message(action any, timestamp uint64, vaultAddress string) (*SignRequest, error) {
 	hash, err := buildActionHash(action, vaultAddress, timestamp)
 	if err != nil {
@@ -88,3 +102,252 @@ func (api *ExchangeAPI) SignWithdrawAction(action WithdrawAction) (byte, [32]byt
 	}
 	return api.SignUserSignableAction(action, types, "HyperliquidTransaction:Withdraw")
 }
+
+func (api *ExchangeAPI) SignUsdSendAction(action UsdSendAction) (byte, [32]byte, [32]byte, error) {
+	types := []apitypes.Type{
+		{
+			Name: "hyperliquidChain",
+			Type: "string",
+		},
+		{
+			Name: "destination",
+			Type: "string",
+		},
+		{
+			Name: "amount",
+			Type: "string",
+		},
+		{
+			Name: "time",
+			Type: "uint64",
+		},
+	}
+	return api.SignUserSignableAction(action, types, "HyperliquidTransaction:UsdSend")
+}
+
+func (api *ExchangeAPI) SignSpotSendAction(action SpotSendAction) (byte, [32]byte, [32]byte, error) {
+	types := []apitypes.Type{
+		{
+			Name: "hyperliquidChain",
+			Type: "string",
+		},
+		{
+			Name: "destination",
+			Type: "string",
+		},
+		{
+			Name: "token",
+			Type: "string",
+		},
+		{
+			Name: "amount",
+			Type: "string",
+		},
+		{
+			Name: "time",
+			Type: "uint64",
+		},
+	}
+	return api.SignUserSignableAction(action, types, "HyperliquidTransaction:SpotSend")
+}
+
+func (api *ExchangeAPI) SignUsdClassTransferAction(action UsdClassTransferAction) (byte, [32]byte, [32]byte, error) {
+	types := []apitypes.Type{
+		{
+			Name: "hyperliquidChain",
+			Type: "string",
+		},
+		{
+			Name: "amount",
+			Type: "string",
+		},
+		{
+			Name: "toPerp",
+			Type: "bool",
+		},
+		{
+			Name: "nonce",
+			Type: "uint64",
+		},
+	}
+	return api.SignUserSignableAction(action, types, "HyperliquidTransaction:UsdClassTransfer")
+}
+
+func (api *ExchangeAPI) SignPerpDexClassTransferAction(action PerpDexClassTransferAction) (byte, [32]byte, [32]byte, error) {
+	types := []apitypes.Type{
+		{
+			Name: "hyperliquidChain",
+			Type: "string",
+		},
+		{
+			Name: "dex",
+			Type: "string",
+		},
+		{
+			Name: "token",
+			Type: "string",
+		},
+		{
+			Name: "amount",
+			Type: "string",
+		},
+		{
+			Name: "toPerp",
+			Type: "bool",
+		},
+		{
+			Name: "nonce",
+			Type: "uint64",
+		},
+	}
+	return api.SignUserSignableAction(action, types, "HyperliquidTransaction:PerpDexClassTransfer")
+}
+
+func (api *ExchangeAPI) SignApproveAgentAction(action ApproveAgentAction) (byte, [32]byte, [32]byte, error) {
+	types := []apitypes.Type{
+		{
+			Name: "hyperliquidChain",
+			Type: "string",
+		},
+		{
+			Name: "agentAddress",
+			Type: "address",
+		},
+		{
+			Name: "agentName",
+			Type: "string",
+		},
+		{
+			Name: "nonce",
+			Type: "uint64",
+		},
+	}
+	message, err := StructToMap(action)
+	if err != nil {
+		return 0, [32]byte{}, [32]byte{}, err
+	}
+	// An unnamed agent is signed with an empty name but sent without the agentName field
+	message["agentName"] = action.AgentName
+	return api.SignUserSignableAction(message, types, "HyperliquidTransaction:ApproveAgent")
+}
+
+func (api *ExchangeAPI) SignApproveBuilderFeeAction(action ApproveBuilderFeeAction) (byte, [32]byte, [32]byte, error) {
+	types := []apitypes.Type{
+		{
+			Name: "hyperliquidChain",
+			Type: "string",
+		},
+		{
+			Name: "maxFeeRate",
+			Type: "string",
+		},
+		{
+			Name: "builder",
+			Type: "address",
+		},
+		{
+			Name: "nonce",
+			Type: "uint64",
+		},
+	}
+	return api.SignUserSignableAction(action, types, "HyperliquidTransaction:ApproveBuilderFee")
+}
+
+func (api *ExchangeAPI) SignTokenDelegateAction(action TokenDelegateAction) (byte, [32]byte, [32]byte, error) {
+	types := []apitypes.Type{
+		{
+			Name: "hyperliquidChain",
+			Type: "string",
+		},
+		{
+			Name: "validator",
+			Type: "address",
+		},
+		{
+			Name: "wei",
+			Type: "uint64",
+		},
+		{
+			Name: "isUndelegate",
+			Type: "bool",
+		},
+		{
+			Name: "nonce",
+			Type: "uint64",
+		},
+	}
+	return api.SignUserSignableAction(action, types, "HyperliquidTransaction:TokenDelegate")
+}
+
+func (api *ExchangeAPI) SignStakingTransferAction(action StakingTransferAction) (byte, [32]byte, [32]byte, error) {
+	types := []apitypes.Type{
+		{
+			Name: "hyperliquidChain",
+			Type: "string",
+		},
+		{
+			Name: "wei",
+			Type: "uint64",
+		},
+		{
+			Name: "nonce",
+			Type: "uint64",
+		},
+	}
+	primaryType := "HyperliquidTransaction:CDeposit"
+	if action.Type == "cWithdraw" {
+		primaryType = "HyperliquidTransaction:CWithdraw"
+	}
+	return api.SignUserSignableAction(action, types, primaryType)
+}
+
+func (api *ExchangeAPI) SignConvertToMultiSigUserAction(action ConvertToMultiSigUserAction) (byte, [32]byte, [32]byte, error) {
+	types := []apitypes.Type{
+		{
+			Name: "hyperliquidChain",
+			Type: "string",
+		},
+		{
+			Name: "signers",
+			Type: "string",
+		},
+		{
+			Name: "nonce",
+			Type: "uint64",
+		},
+	}
+	return api.SignUserSignableAction(action, types, "HyperliquidTransaction:ConvertToMultiSigUser")
+}
+
+// SignMultiSigAction signs a multi-sig action as its outer signer.
+// The hash of the action without its type is signed as a user signed SendMultiSig message.
+func (api *ExchangeAPI) SignMultiSigAction(action MultiSigAction, nonce uint64) (byte, [32]byte, [32]byte, error) {
+	action.Type = ""
+	hash, err := buildActionHash(action, api.VaultAddress(), nonce)
+	if err != nil {
+		api.debug("Error building multi-sig action hash: %s", err)
+		return 0, [32]byte{}, [32]byte{}, err
+	}
+	_, chainType := api.getChainParams()
+	envelope := SendMultiSigEnvelope{
+		SignatureChainID:   action.SignatureChainID,
+		HyperliquidChain:   chainType,
+		MultiSigActionHash: hash.Hex(),
+		Nonce:              nonce,
+	}
+	types := []apitypes.Type{
+		{
+			Name: "hyperliquidChain",
+			Type: "string",
+		},
+		{
+			Name: "multiSigActionHash",
+			Type: "bytes32",
+		},
+		{
+			Name: "nonce",
+			Type: "uint64",
+		},
+	}
+	return api.SignUserSignableAction(envelope, types, "HyperliquidTransaction:SendMultiSig")
+}
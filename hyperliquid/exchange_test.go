@@ -1,11 +1,22 @@
 package hyperliquid
 
 import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"log"
 	"math"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 )
 
 func GetExchangeAPI() *ExchangeAPI {
@@ -23,6 +34,79 @@ func GetExchangeAPI() *ExchangeAPI {
 	return exchangeAPI
 }
 
+// TestExchangeRequest is an exchange request received by the local test server.
+type TestExchangeRequest struct {
+	Action       json.RawMessage `json:"action"`
+	Nonce        uint64          `json:"nonce"`
+	Signature    RsvSignature    `json:"signature"`
+	VaultAddress string          `json:"vaultAddress"`
+}
+
+// GetTestExchangeAPI returns an ExchangeAPI signing with a random key and sending
+// its requests to a local server that answers every request with the result of handle.
+func GetTestExchangeAPI(t *testing.T, handle func(req TestExchangeRequest) any) *ExchangeAPI {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req TestExchangeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Decode() error = %v", err)
+		}
+		if req.Signature.R == "" || req.Signature.S == "" {
+			t.Errorf("request %s is not signed", req.Action)
+		}
+		json.NewEncoder(w).Encode(handle(req))
+	}))
+	t.Cleanup(server.Close)
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	exchangeAPI := &ExchangeAPI{
+		Client:       *NewClient(false),
+		baseEndpoint: "/exchange",
+		meta:         map[string]AssetInfo{"ETH": {AssetID: 1, SzDecimals: 4}},
+		spotMeta:     map[string]AssetInfo{"PURR": {AssetID: 0, SzDecimals: 0, WeiDecimals: 5, SpotName: "PURR/USDC", TokenID: "0xc4bf3f870c0e9465323c0b6ed28096c2"}},
+	}
+	exchangeAPI.baseURL = server.URL
+	if err := exchangeAPI.SetPrivateKey(hex.EncodeToString(crypto.FromECDSA(key))); err != nil {
+		t.Fatal(err)
+	}
+	exchangeAPI.SetAccountAddress(crypto.PubkeyToAddress(key.PublicKey).Hex())
+	if GLOBAL_DEBUG {
+		exchangeAPI.SetDebugActive()
+	}
+	return exchangeAPI
+}
+
+// recoverUserSignedAction returns the address that signed the user signed action of the request.
+func recoverUserSignedAction(t *testing.T, req TestExchangeRequest, types []apitypes.Type, primaryType string) string {
+	var message map[string]any
+	if err := json.Unmarshal(req.Action, &message); err != nil {
+		t.Fatal(err)
+	}
+	signatureChainID, _ := message["signatureChainId"].(string)
+	delete(message, "type")
+	delete(message, "signatureChainId")
+	typedData := SignRequestToEIP712TypedData(&SignRequest{
+		DomainName:       "HyperliquidSignTransaction",
+		PrimaryType:      primaryType,
+		DType:            types,
+		DTypeMsg:         message,
+		IsMainNet:        false,
+		SignatureChainID: signatureChainID,
+	})
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		t.Fatalf("TypedDataAndHash() error = %v", err)
+	}
+	signature := append(hexutil.MustDecode(req.Signature.R), hexutil.MustDecode(req.Signature.S)...)
+	signature = append(signature, req.Signature.V-27)
+	pub, err := crypto.SigToPub(hash, signature)
+	if err != nil {
+		t.Fatalf("SigToPub() error = %v", err)
+	}
+	return crypto.PubkeyToAddress(*pub).Hex()
+}
+
 func TestExchangeAPI_Endpoint(t *testing.T) {
 	exchangeAPI := GetExchangeAPI()
 	res := exchangeAPI.Endpoint()
@@ -70,7 +154,7 @@ func TestExchangeAPI_UpdateLeverage(t *testing.T) {
 	_, err = exchangeAPI.UpdateLeverage("ETH", true, 2000)
 	if err == nil {
 		t.Errorf("UpdateLeverage() error = %v", err)
-	} else if err.Error() != "Invalid leverage value" {
+	} else if !strings.HasPrefix(err.Error(), "Invalid leverage value") {
 		t.Errorf("UpdateLeverage() error = %v expected Invalid leverage value", err)
 	}
 	t.Logf("UpdateLeverage() = %v", err)
@@ -382,3 +466,1615 @@ func TestExchageAPI_TestMarketOrderSpot(t *testing.T) {
 		t.Errorf("res.Response.Data.Statuses[0].Filled.AvgPx = %v", avgPrice)
 	}
 }
+
+func TestExchangeAPI_PlaceTwapOrder(t *testing.T) {
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action TwapOrderAction
+		json.Unmarshal(req.Action, &action)
+		expected := TwapOrderWire{Asset: 1, IsBuy: true, Sz: "0.5", ReduceOnly: false, Minutes: 30, Randomize: true}
+		if action.Type != "twapOrder" || action.Twap != expected {
+			t.Errorf("action = %+v, want %+v", action, expected)
+		}
+		return map[string]any{
+			"status":   "ok",
+			"response": map[string]any{"type": "twapOrder", "data": map[string]any{"status": map[string]any{"running": map[string]any{"twapId": 77738308}}}},
+		}
+	})
+	res, err := exchangeAPI.PlaceTwapOrder("ETH", 0.5, true, 30, true, false)
+	if err != nil {
+		t.Fatalf("PlaceTwapOrder() error = %v", err)
+	}
+	twapID, err := res.TwapID()
+	if err != nil || twapID != 77738308 {
+		t.Errorf("TwapID() = %v, %v, want 77738308", twapID, err)
+	}
+	if _, err := exchangeAPI.PlaceTwapOrder("UNKNOWN", 1, true, 30, false, false); err == nil {
+		t.Errorf("PlaceTwapOrder() expected unknown coin error")
+	}
+}
+
+func TestExchangeAPI_CancelTwapOrder(t *testing.T) {
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action TwapCancelAction
+		json.Unmarshal(req.Action, &action)
+		if action.Type != "twapCancel" || action.Asset != 1 || action.TwapID != 42 {
+			t.Errorf("action = %+v, want twapCancel of 42", action)
+		}
+		return map[string]any{
+			"status":   "ok",
+			"response": map[string]any{"type": "twapCancel", "data": map[string]any{"status": "success"}},
+		}
+	})
+	res, err := exchangeAPI.CancelTwapOrder("ETH", 42)
+	if err != nil {
+		t.Fatalf("CancelTwapOrder() error = %v", err)
+	}
+	if res.Response.Data.Status.Status != "success" {
+		t.Errorf("CancelTwapOrder() = %+v, want success", res)
+	}
+}
+
+func TestExchangeAPI_ScheduleCancel(t *testing.T) {
+	var actions []map[string]any
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action map[string]any
+		json.Unmarshal(req.Action, &action)
+		actions = append(actions, action)
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	cancelTime := time.Now().Add(time.Minute).UnixMilli()
+	if _, err := exchangeAPI.ScheduleCancel(&cancelTime); err != nil {
+		t.Fatalf("ScheduleCancel() error = %v", err)
+	}
+	if _, err := exchangeAPI.ScheduleCancel(nil); err != nil {
+		t.Fatalf("ScheduleCancel(nil) error = %v", err)
+	}
+	if len(actions) != 2 || actions[0]["type"] != "scheduleCancel" || int64(actions[0]["time"].(float64)) != cancelTime {
+		t.Errorf("actions = %v, want scheduleCancel at %v", actions, cancelTime)
+	}
+	if _, ok := actions[1]["time"]; ok {
+		t.Errorf("action = %v, want no time to remove the scheduled cancel", actions[1])
+	}
+}
+
+func TestExchangeAPI_BatchModifyOrders(t *testing.T) {
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action struct {
+			Type     string `json:"type"`
+			Modifies []struct {
+				Oid   any       `json:"oid"`
+				Order OrderWire `json:"order"`
+			} `json:"modifies"`
+		}
+		json.Unmarshal(req.Action, &action)
+		if action.Type != "batchModify" || len(action.Modifies) != 2 {
+			t.Fatalf("action = %+v, want batchModify of 2 orders", action)
+		}
+		if action.Modifies[0].Oid != float64(7) || action.Modifies[0].Order.LimitPx != "2000" || action.Modifies[0].Order.Asset != 1 {
+			t.Errorf("modify = %+v, want oid 7 at 2000", action.Modifies[0])
+		}
+		if action.Modifies[1].Oid != "0x00000000000000000000000000000001" || action.Modifies[1].Order.SizePx != "0.25" {
+			t.Errorf("modify = %+v, want cloid modify of 0.25", action.Modifies[1])
+		}
+		return map[string]any{
+			"status":   "ok",
+			"response": map[string]any{"type": "order", "data": map[string]any{"statuses": []any{"success", "success"}}},
+		}
+	})
+	oid := 7
+	orderType := OrderType{Limit: &LimitOrderType{Tif: TifGtc}}
+	res, err := exchangeAPI.BatchModifyOrders([]ModifyOrderRequest{
+		{Oid: &oid, Order: OrderRequest{Coin: "ETH", IsBuy: true, Sz: 0.1, LimitPx: 2000, OrderType: orderType}},
+		{Cloid: "0x00000000000000000000000000000001", Order: OrderRequest{Coin: "ETH", IsBuy: false, Sz: 0.25, LimitPx: 2100, OrderType: orderType}},
+	})
+	if err != nil {
+		t.Fatalf("BatchModifyOrders() error = %v", err)
+	}
+	if len(res.Response.Data.Statuses) != 2 {
+		t.Errorf("BatchModifyOrders() = %+v, want 2 statuses", res)
+	}
+	if _, err := exchangeAPI.BulkModifyOrders([]OrderRequest{
+		{OrderID: &oid, Coin: "ETH", IsBuy: true, Sz: 0.1, LimitPx: 2000, OrderType: orderType},
+		{Cloid: "0x00000000000000000000000000000001", Coin: "ETH", IsBuy: false, Sz: 0.25, LimitPx: 2100, OrderType: orderType},
+	}); err != nil {
+		t.Fatalf("BulkModifyOrders() error = %v", err)
+	}
+	if _, err := exchangeAPI.BatchModifyOrders([]ModifyOrderRequest{{Order: OrderRequest{Coin: "ETH"}}}); err == nil {
+		t.Errorf("BatchModifyOrders() expected missing oid error")
+	}
+}
+
+func TestExchangeAPI_UpdateIsolatedMargin(t *testing.T) {
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action UpdateIsolatedMarginAction
+		json.Unmarshal(req.Action, &action)
+		expected := UpdateIsolatedMarginAction{Type: "updateIsolatedMargin", Asset: 1, IsBuy: true, Ntli: -2500000}
+		if action != expected {
+			t.Errorf("action = %+v, want %+v", action, expected)
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	res, err := exchangeAPI.UpdateIsolatedMargin("ETH", true, -2.5)
+	if err != nil {
+		t.Fatalf("UpdateIsolatedMargin() error = %v", err)
+	}
+	if res.Status != "ok" {
+		t.Errorf("UpdateIsolatedMargin() = %+v, want ok", res)
+	}
+}
+
+func TestExchangeAPI_TopUpIsolatedOnlyMargin(t *testing.T) {
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action TopUpIsolatedOnlyMarginAction
+		json.Unmarshal(req.Action, &action)
+		expected := TopUpIsolatedOnlyMarginAction{Type: "topUpIsolatedOnlyMargin", Asset: 1, Leverage: "2.5"}
+		if action != expected {
+			t.Errorf("action = %+v, want %+v", action, expected)
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	if _, err := exchangeAPI.TopUpIsolatedOnlyMargin("ETH", 2.5); err != nil {
+		t.Fatalf("TopUpIsolatedOnlyMargin() error = %v", err)
+	}
+	if _, err := exchangeAPI.TopUpIsolatedOnlyMargin("ETH", 0); err == nil {
+		t.Errorf("TopUpIsolatedOnlyMargin() expected invalid leverage error")
+	}
+}
+
+func TestExchangeAPI_UsdSend(t *testing.T) {
+	var exchangeAPI *ExchangeAPI
+	exchangeAPI = GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action UsdSendAction
+		json.Unmarshal(req.Action, &action)
+		if action.Type != "usdSend" || action.Amount != "10.5" || action.Destination != "0x0000000000000000000000000000000000000001" {
+			t.Errorf("action = %+v, want usdSend of 10.5", action)
+		}
+		if action.SignatureChainID != "0x66eee" || action.HyperliquidChain != "Testnet" || action.Time != req.Nonce {
+			t.Errorf("action = %+v, want testnet chain params and time = nonce", action)
+		}
+		if req.VaultAddress != "" {
+			t.Errorf("vaultAddress = %v, want empty", req.VaultAddress)
+		}
+		types := []apitypes.Type{
+			{Name: "hyperliquidChain", Type: "string"},
+			{Name: "destination", Type: "string"},
+			{Name: "amount", Type: "string"},
+			{Name: "time", Type: "uint64"},
+		}
+		if signer := recoverUserSignedAction(t, req, types, "HyperliquidTransaction:UsdSend"); signer != exchangeAPI.AccountAddress() {
+			t.Errorf("signer = %v, want %v", signer, exchangeAPI.AccountAddress())
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	if _, err := exchangeAPI.UsdSend("0x0000000000000000000000000000000000000001", 10.5); err != nil {
+		t.Fatalf("UsdSend() error = %v", err)
+	}
+}
+
+func TestExchangeAPI_SpotSend(t *testing.T) {
+	var exchangeAPI *ExchangeAPI
+	exchangeAPI = GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action SpotSendAction
+		json.Unmarshal(req.Action, &action)
+		if action.Type != "spotSend" || action.Token != "PURR:0xc4bf3f870c0e9465323c0b6ed28096c2" || action.Amount != "1.23457" {
+			t.Errorf("action = %+v, want spotSend of 1.23457 PURR", action)
+		}
+		types := []apitypes.Type{
+			{Name: "hyperliquidChain", Type: "string"},
+			{Name: "destination", Type: "string"},
+			{Name: "token", Type: "string"},
+			{Name: "amount", Type: "string"},
+			{Name: "time", Type: "uint64"},
+		}
+		if signer := recoverUserSignedAction(t, req, types, "HyperliquidTransaction:SpotSend"); signer != exchangeAPI.AccountAddress() {
+			t.Errorf("signer = %v, want %v", signer, exchangeAPI.AccountAddress())
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	if _, err := exchangeAPI.SpotSend("0x0000000000000000000000000000000000000001", "PURR", 1.234567); err != nil {
+		t.Fatalf("SpotSend() error = %v", err)
+	}
+	if _, err := exchangeAPI.SpotSend("0x0000000000000000000000000000000000000001", "UNKNOWN", 1); err == nil {
+		t.Errorf("SpotSend() expected unknown token error")
+	}
+}
+
+func TestExchangeAPI_TransferBetweenSpotAndPerp(t *testing.T) {
+	var exchangeAPI *ExchangeAPI
+	var actions []UsdClassTransferAction
+	exchangeAPI = GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action UsdClassTransferAction
+		json.Unmarshal(req.Action, &action)
+		actions = append(actions, action)
+		types := []apitypes.Type{
+			{Name: "hyperliquidChain", Type: "string"},
+			{Name: "amount", Type: "string"},
+			{Name: "toPerp", Type: "bool"},
+			{Name: "nonce", Type: "uint64"},
+		}
+		if signer := recoverUserSignedAction(t, req, types, "HyperliquidTransaction:UsdClassTransfer"); signer != exchangeAPI.AccountAddress() {
+			t.Errorf("signer = %v, want %v", signer, exchangeAPI.AccountAddress())
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	if _, err := exchangeAPI.TransferBetweenSpotAndPerp(100, true); err != nil {
+		t.Fatalf("TransferBetweenSpotAndPerp() error = %v", err)
+	}
+	subAccount := "0x0000000000000000000000000000000000000002"
+	exchangeAPI.SetVaultAddress(subAccount)
+	if _, err := exchangeAPI.TransferBetweenSpotAndPerp(1.5, false); err != nil {
+		t.Fatalf("TransferBetweenSpotAndPerp() error = %v", err)
+	}
+	if len(actions) != 2 || actions[0].Amount != "100" || !actions[0].ToPerp || actions[0].Type != "usdClassTransfer" {
+		t.Errorf("actions = %+v, want transfer of 100 to perp", actions)
+	}
+	if actions[1].Amount != "1.5 subaccount:"+subAccount || actions[1].ToPerp {
+		t.Errorf("action = %+v, want sub-account transfer of 1.5 to spot", actions[1])
+	}
+}
+
+func TestExchangeAPI_PerpDexClassTransfer(t *testing.T) {
+	var exchangeAPI *ExchangeAPI
+	exchangeAPI = GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action PerpDexClassTransferAction
+		json.Unmarshal(req.Action, &action)
+		if action.Type != "perpDexClassTransfer" || action.Dex != "xyz" || action.Token != "USDC" || action.Amount != "25" || !action.ToPerp {
+			t.Errorf("action = %+v, want transfer of 25 USDC to xyz", action)
+		}
+		types := []apitypes.Type{
+			{Name: "hyperliquidChain", Type: "string"},
+			{Name: "dex", Type: "string"},
+			{Name: "token", Type: "string"},
+			{Name: "amount", Type: "string"},
+			{Name: "toPerp", Type: "bool"},
+			{Name: "nonce", Type: "uint64"},
+		}
+		if signer := recoverUserSignedAction(t, req, types, "HyperliquidTransaction:PerpDexClassTransfer"); signer != exchangeAPI.AccountAddress() {
+			t.Errorf("signer = %v, want %v", signer, exchangeAPI.AccountAddress())
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	if _, err := exchangeAPI.PerpDexClassTransfer("xyz", 25, true); err != nil {
+		t.Fatalf("PerpDexClassTransfer() error = %v", err)
+	}
+	if _, err := exchangeAPI.PerpDexClassTransfer("", 25, true); err == nil {
+		t.Errorf("PerpDexClassTransfer() expected missing dex error")
+	}
+}
+
+func TestExchangeAPI_SubAccountTransfer(t *testing.T) {
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action SubAccountTransferAction
+		json.Unmarshal(req.Action, &action)
+		expected := SubAccountTransferAction{Type: "subAccountTransfer", SubAccountUser: "0x0000000000000000000000000000000000000002", IsDeposit: true, Usd: 12340000}
+		if action != expected {
+			t.Errorf("action = %+v, want %+v", action, expected)
+		}
+		if req.VaultAddress != "" {
+			t.Errorf("vaultAddress = %v, want empty", req.VaultAddress)
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	exchangeAPI.SetVaultAddress("0x0000000000000000000000000000000000000003")
+	if _, err := exchangeAPI.SubAccountTransfer("0x0000000000000000000000000000000000000002", true, 12.34); err != nil {
+		t.Fatalf("SubAccountTransfer() error = %v", err)
+	}
+}
+
+func TestExchangeAPI_SubAccountSpotTransfer(t *testing.T) {
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action SubAccountSpotTransferAction
+		json.Unmarshal(req.Action, &action)
+		expected := SubAccountSpotTransferAction{
+			Type:           "subAccountSpotTransfer",
+			SubAccountUser: "0x0000000000000000000000000000000000000002",
+			IsDeposit:      false,
+			Token:          "PURR:0xc4bf3f870c0e9465323c0b6ed28096c2",
+			Amount:         "100",
+		}
+		if action != expected {
+			t.Errorf("action = %+v, want %+v", action, expected)
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	if _, err := exchangeAPI.SubAccountSpotTransfer("0x0000000000000000000000000000000000000002", false, "PURR", 100); err != nil {
+		t.Fatalf("SubAccountSpotTransfer() error = %v", err)
+	}
+}
+
+func TestExchangeAPI_CreateSubAccount(t *testing.T) {
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action CreateSubAccountAction
+		json.Unmarshal(req.Action, &action)
+		expected := CreateSubAccountAction{Type: "createSubAccount", Name: "bot"}
+		if action != expected {
+			t.Errorf("action = %+v, want %+v", action, expected)
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "createSubAccount", "data": "0x0000000000000000000000000000000000000002"}}
+	})
+	res, err := exchangeAPI.CreateSubAccount("bot")
+	if err != nil {
+		t.Fatalf("CreateSubAccount() error = %v", err)
+	}
+	if res.Response.Data != "0x0000000000000000000000000000000000000002" {
+		t.Errorf("Response.Data = %v, want the sub-account address", res.Response.Data)
+	}
+}
+
+func TestExchangeAPI_VaultTransfer(t *testing.T) {
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action VaultTransferAction
+		json.Unmarshal(req.Action, &action)
+		expected := VaultTransferAction{Type: "vaultTransfer", VaultAddress: "0x0000000000000000000000000000000000000003", IsDeposit: false, Usd: 5000000}
+		if action != expected {
+			t.Errorf("action = %+v, want %+v", action, expected)
+		}
+		if req.VaultAddress != "" {
+			t.Errorf("vaultAddress = %v, want empty", req.VaultAddress)
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	if _, err := exchangeAPI.VaultTransfer("0x0000000000000000000000000000000000000003", false, 5); err != nil {
+		t.Fatalf("VaultTransfer() error = %v", err)
+	}
+}
+
+func TestExchangeAPI_CreateVault(t *testing.T) {
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action CreateVaultAction
+		json.Unmarshal(req.Action, &action)
+		expected := CreateVaultAction{Type: "createVault", Name: "alpha", Description: "market making", InitialUsd: 100000000, Nonce: req.Nonce}
+		if action != expected {
+			t.Errorf("action = %+v, want %+v", action, expected)
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "createVault", "data": "0x0000000000000000000000000000000000000003"}}
+	})
+	res, err := exchangeAPI.CreateVault("alpha", "market making", 100)
+	if err != nil {
+		t.Fatalf("CreateVault() error = %v", err)
+	}
+	if res.Response.Data != "0x0000000000000000000000000000000000000003" {
+		t.Errorf("Response.Data = %v, want the vault address", res.Response.Data)
+	}
+}
+
+func TestExchangeAPI_VaultModify(t *testing.T) {
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action VaultModifyAction
+		json.Unmarshal(req.Action, &action)
+		if action.Type != "vaultModify" || action.VaultAddress != "0x0000000000000000000000000000000000000003" {
+			t.Errorf("action = %+v, want vaultModify of the vault", action)
+		}
+		if action.AllowDeposits == nil || *action.AllowDeposits || action.AlwaysCloseOnWithdraw != nil {
+			t.Errorf("action = %+v, want allowDeposits = false and alwaysCloseOnWithdraw unset", action)
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	allowDeposits := false
+	if _, err := exchangeAPI.VaultModify("0x0000000000000000000000000000000000000003", &allowDeposits, nil); err != nil {
+		t.Fatalf("VaultModify() error = %v", err)
+	}
+}
+
+func TestExchangeAPI_VaultDistribute(t *testing.T) {
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action VaultDistributeAction
+		json.Unmarshal(req.Action, &action)
+		expected := VaultDistributeAction{Type: "vaultDistribute", VaultAddress: "0x0000000000000000000000000000000000000003", Usd: 250000000}
+		if action != expected {
+			t.Errorf("action = %+v, want %+v", action, expected)
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	if _, err := exchangeAPI.VaultDistribute("0x0000000000000000000000000000000000000003", 250); err != nil {
+		t.Fatalf("VaultDistribute() error = %v", err)
+	}
+}
+
+func TestExchangeAPI_ApproveAgent(t *testing.T) {
+	var exchangeAPI *ExchangeAPI
+	var agents []string
+	exchangeAPI = GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action ApproveAgentAction
+		json.Unmarshal(req.Action, &action)
+		if action.Type != "approveAgent" || action.HyperliquidChain != "Testnet" || action.Nonce != req.Nonce {
+			t.Errorf("action = %+v, want testnet approveAgent with nonce = request nonce", action)
+		}
+		agents = append(agents, action.AgentAddress)
+		types := []apitypes.Type{
+			{Name: "hyperliquidChain", Type: "string"},
+			{Name: "agentAddress", Type: "address"},
+			{Name: "agentName", Type: "string"},
+			{Name: "nonce", Type: "uint64"},
+		}
+		if action.AgentName == "" {
+			req.Action, _ = json.Marshal(struct {
+				ApproveAgentAction
+				AgentName string `json:"agentName"`
+			}{ApproveAgentAction: action})
+		}
+		if signer := recoverUserSignedAction(t, req, types, "HyperliquidTransaction:ApproveAgent"); signer != exchangeAPI.AccountAddress() {
+			t.Errorf("signer = %v, want %v", signer, exchangeAPI.AccountAddress())
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	if _, err := exchangeAPI.ApproveAgent("0x0000000000000000000000000000000000000004", "bot"); err != nil {
+		t.Fatalf("ApproveAgent() error = %v", err)
+	}
+	agentAPI, err := exchangeAPI.ApproveNewAgent("")
+	if err != nil {
+		t.Fatalf("ApproveNewAgent() error = %v", err)
+	}
+	if agentAPI.KeyManager().PublicAddressHex() != agents[1] {
+		t.Errorf("agent address = %v, want %v", agentAPI.KeyManager().PublicAddressHex(), agents[1])
+	}
+	if agentAPI.AccountAddress() != exchangeAPI.AccountAddress() {
+		t.Errorf("AccountAddress() = %v, want %v", agentAPI.AccountAddress(), exchangeAPI.AccountAddress())
+	}
+	if exchangeAPI.KeyManager().PublicAddressHex() != exchangeAPI.AccountAddress() {
+		t.Errorf("ApproveNewAgent() changed the key of the approving api")
+	}
+}
+
+func TestExchangeAPI_ApproveBuilderFee(t *testing.T) {
+	var exchangeAPI *ExchangeAPI
+	exchangeAPI = GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action ApproveBuilderFeeAction
+		json.Unmarshal(req.Action, &action)
+		if action.Type != "approveBuilderFee" || action.MaxFeeRate != "0.01%" || action.Builder != "0x0000000000000000000000000000000000000005" {
+			t.Errorf("action = %+v, want approveBuilderFee of 0.01%%", action)
+		}
+		types := []apitypes.Type{
+			{Name: "hyperliquidChain", Type: "string"},
+			{Name: "maxFeeRate", Type: "string"},
+			{Name: "builder", Type: "address"},
+			{Name: "nonce", Type: "uint64"},
+		}
+		if signer := recoverUserSignedAction(t, req, types, "HyperliquidTransaction:ApproveBuilderFee"); signer != exchangeAPI.AccountAddress() {
+			t.Errorf("signer = %v, want %v", signer, exchangeAPI.AccountAddress())
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	if _, err := exchangeAPI.ApproveBuilderFee("0x0000000000000000000000000000000000000005", "0.01%"); err != nil {
+		t.Fatalf("ApproveBuilderFee() error = %v", err)
+	}
+}
+
+func TestExchangeAPI_SetReferrer(t *testing.T) {
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action SetReferrerAction
+		json.Unmarshal(req.Action, &action)
+		expected := SetReferrerAction{Type: "setReferrer", Code: "FRIEND"}
+		if action != expected {
+			t.Errorf("action = %+v, want %+v", action, expected)
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	if _, err := exchangeAPI.SetReferrer("FRIEND"); err != nil {
+		t.Fatalf("SetReferrer() error = %v", err)
+	}
+}
+
+func TestExchangeAPI_RegisterReferrer(t *testing.T) {
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action RegisterReferrerAction
+		json.Unmarshal(req.Action, &action)
+		expected := RegisterReferrerAction{Type: "registerReferrer", Code: "MYCODE"}
+		if action != expected {
+			t.Errorf("action = %+v, want %+v", action, expected)
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	if _, err := exchangeAPI.RegisterReferrer("MYCODE"); err != nil {
+		t.Fatalf("RegisterReferrer() error = %v", err)
+	}
+}
+
+func TestExchangeAPI_TokenDelegate(t *testing.T) {
+	var exchangeAPI *ExchangeAPI
+	exchangeAPI = GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action TokenDelegateAction
+		json.Unmarshal(req.Action, &action)
+		if action.Type != "tokenDelegate" || action.Validator != "0x0000000000000000000000000000000000000006" || action.Wei != 150000000 || !action.IsUndelegate {
+			t.Errorf("action = %+v, want undelegation of 150000000 wei", action)
+		}
+		types := []apitypes.Type{
+			{Name: "hyperliquidChain", Type: "string"},
+			{Name: "validator", Type: "address"},
+			{Name: "wei", Type: "uint64"},
+			{Name: "isUndelegate", Type: "bool"},
+			{Name: "nonce", Type: "uint64"},
+		}
+		if signer := recoverUserSignedAction(t, req, types, "HyperliquidTransaction:TokenDelegate"); signer != exchangeAPI.AccountAddress() {
+			t.Errorf("signer = %v, want %v", signer, exchangeAPI.AccountAddress())
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	if _, err := exchangeAPI.TokenDelegate("0x0000000000000000000000000000000000000006", 150000000, true); err != nil {
+		t.Fatalf("TokenDelegate() error = %v", err)
+	}
+}
+
+func TestExchangeAPI_StakingTransfer(t *testing.T) {
+	var exchangeAPI *ExchangeAPI
+	var actions []StakingTransferAction
+	exchangeAPI = GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action StakingTransferAction
+		json.Unmarshal(req.Action, &action)
+		actions = append(actions, action)
+		types := []apitypes.Type{
+			{Name: "hyperliquidChain", Type: "string"},
+			{Name: "wei", Type: "uint64"},
+			{Name: "nonce", Type: "uint64"},
+		}
+		primaryType := "HyperliquidTransaction:CDeposit"
+		if action.Type == "cWithdraw" {
+			primaryType = "HyperliquidTransaction:CWithdraw"
+		}
+		if signer := recoverUserSignedAction(t, req, types, primaryType); signer != exchangeAPI.AccountAddress() {
+			t.Errorf("signer = %v, want %v", signer, exchangeAPI.AccountAddress())
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	if _, err := exchangeAPI.CDeposit(100000000); err != nil {
+		t.Fatalf("CDeposit() error = %v", err)
+	}
+	if _, err := exchangeAPI.CWithdraw(50000000); err != nil {
+		t.Fatalf("CWithdraw() error = %v", err)
+	}
+	if len(actions) != 2 || actions[0].Type != "cDeposit" || actions[0].Wei != 100000000 || actions[1].Type != "cWithdraw" || actions[1].Wei != 50000000 {
+		t.Errorf("actions = %+v, want cDeposit of 100000000 and cWithdraw of 50000000", actions)
+	}
+}
+
+func TestExchangeAPI_Withdraw(t *testing.T) {
+	var exchangeAPI *ExchangeAPI
+	var actions []WithdrawAction
+	exchangeAPI = GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action WithdrawAction
+		json.Unmarshal(req.Action, &action)
+		actions = append(actions, action)
+		if action.Type != "withdraw3" || action.Amount != "20" || action.HyperliquidChain != "Testnet" || action.Time != req.Nonce {
+			t.Errorf("action = %+v, want testnet withdraw3 of 20 with time = nonce", action)
+		}
+		types := []apitypes.Type{
+			{Name: "hyperliquidChain", Type: "string"},
+			{Name: "destination", Type: "string"},
+			{Name: "amount", Type: "string"},
+			{Name: "time", Type: "uint64"},
+		}
+		if signer := recoverUserSignedAction(t, req, types, "HyperliquidTransaction:Withdraw"); signer != exchangeAPI.AccountAddress() {
+			t.Errorf("signer = %v, want %v", signer, exchangeAPI.AccountAddress())
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	res, err := exchangeAPI.Withdraw("0x0000000000000000000000000000000000000001", 20)
+	if err != nil {
+		t.Fatalf("Withdraw() error = %v", err)
+	}
+	if res.Nonce == 0 || uint64(res.Nonce) != actions[0].Time || res.Fee != WITHDRAW_FEE {
+		t.Errorf("Withdraw() = %+v, want nonce %v and fee %v", res, actions[0].Time, WITHDRAW_FEE)
+	}
+	if err := exchangeAPI.SetSignatureChainID("arbitrum"); err == nil {
+		t.Errorf("SetSignatureChainID() expected invalid chain id error")
+	}
+	if err := exchangeAPI.SetSignatureChainID("0x1"); err != nil {
+		t.Fatalf("SetSignatureChainID() error = %v", err)
+	}
+	if _, _, _, err := exchangeAPI.SignWithdrawAction(WithdrawAction{Type: "withdraw3", SignatureChainID: "0xzz"}); err == nil {
+		t.Errorf("SignWithdrawAction() expected invalid signatureChainId error")
+	}
+	if _, err := exchangeAPI.Withdraw("0x0000000000000000000000000000000000000001", 20); err != nil {
+		t.Fatalf("Withdraw() error = %v", err)
+	}
+	if actions[0].SignatureChainID != TESTNET_SIGNATURE_CHAIN_ID || actions[1].SignatureChainID != "0x1" {
+		t.Errorf("signatureChainIds = %v, %v, want %v, 0x1", actions[0].SignatureChainID, actions[1].SignatureChainID, TESTNET_SIGNATURE_CHAIN_ID)
+	}
+	if _, err := exchangeAPI.Withdraw("0x0000000000000000000000000000000000000001", WITHDRAW_FEE); err == nil {
+		t.Errorf("Withdraw() expected amount lower than fee error")
+	}
+}
+
+func TestExchangeAPI_ReserveRequestWeight(t *testing.T) {
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action ReserveRequestWeightAction
+		json.Unmarshal(req.Action, &action)
+		expected := ReserveRequestWeightAction{Type: "reserveRequestWeight", Weight: 1000}
+		if action != expected {
+			t.Errorf("action = %+v, want %+v", action, expected)
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	if _, err := exchangeAPI.ReserveRequestWeight(1000); err != nil {
+		t.Fatalf("ReserveRequestWeight() error = %v", err)
+	}
+	if _, err := exchangeAPI.ReserveRequestWeight(0); err == nil {
+		t.Errorf("ReserveRequestWeight() expected invalid weight error")
+	}
+}
+
+func TestExchangeAPI_Noop(t *testing.T) {
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action NoopAction
+		json.Unmarshal(req.Action, &action)
+		if action.Type != "noop" || req.Nonce != 1700000000000 {
+			t.Errorf("action = %+v with nonce %v, want noop with nonce 1700000000000", action, req.Nonce)
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	if _, err := exchangeAPI.Noop(1700000000000); err != nil {
+		t.Fatalf("Noop() error = %v", err)
+	}
+}
+
+func TestExchangeAPI_ConvertToMultiSigUser(t *testing.T) {
+	var exchangeAPI *ExchangeAPI
+	exchangeAPI = GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action ConvertToMultiSigUserAction
+		json.Unmarshal(req.Action, &action)
+		expected := `{"authorizedUsers":["0x000000000000000000000000000000000000000a","0x000000000000000000000000000000000000000b"],"threshold":2}`
+		if action.Type != "convertToMultiSigUser" || action.Signers != expected {
+			t.Errorf("action = %+v, want signers %v", action, expected)
+		}
+		types := []apitypes.Type{
+			{Name: "hyperliquidChain", Type: "string"},
+			{Name: "signers", Type: "string"},
+			{Name: "nonce", Type: "uint64"},
+		}
+		if signer := recoverUserSignedAction(t, req, types, "HyperliquidTransaction:ConvertToMultiSigUser"); signer != exchangeAPI.AccountAddress() {
+			t.Errorf("signer = %v, want %v", signer, exchangeAPI.AccountAddress())
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	signers := []string{"0x000000000000000000000000000000000000000B", "0x000000000000000000000000000000000000000a"}
+	if _, err := exchangeAPI.ConvertToMultiSigUser(signers, 2); err != nil {
+		t.Fatalf("ConvertToMultiSigUser() error = %v", err)
+	}
+	if _, err := exchangeAPI.ConvertToMultiSigUser(signers, 3); err == nil {
+		t.Errorf("ConvertToMultiSigUser() expected invalid threshold error")
+	}
+}
+
+func TestExchangeAPI_MultiSig(t *testing.T) {
+	var exchangeAPI *ExchangeAPI
+	multiSigUser := "0x000000000000000000000000000000000000000C"
+	signatures := []RsvSignature{{R: "0x01", S: "0x02", V: 27}, {R: "0x03", S: "0x04", V: 28}}
+	exchangeAPI = GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action struct {
+			Type             string         `json:"type"`
+			SignatureChainID string         `json:"signatureChainId"`
+			Signatures       []RsvSignature `json:"signatures"`
+			Payload          struct {
+				MultiSigUser string     `json:"multiSigUser"`
+				OuterSigner  string     `json:"outerSigner"`
+				Action       NoopAction `json:"action"`
+			} `json:"payload"`
+		}
+		json.Unmarshal(req.Action, &action)
+		if action.Type != "multiSig" || len(action.Signatures) != 2 || action.Payload.Action.Type != "noop" {
+			t.Errorf("action = %+v, want multiSig of a noop with 2 signatures", action)
+		}
+		if action.Payload.MultiSigUser != "0x000000000000000000000000000000000000000c" || action.Payload.OuterSigner != strings.ToLower(exchangeAPI.AccountAddress()) {
+			t.Errorf("payload = %+v, want lowercased multi-sig user and outer signer", action.Payload)
+		}
+		hash, err := buildActionHash(MultiSigAction{
+			SignatureChainID: action.SignatureChainID,
+			Signatures:       action.Signatures,
+			Payload:          MultiSigPayload{MultiSigUser: action.Payload.MultiSigUser, OuterSigner: action.Payload.OuterSigner, Action: action.Payload.Action},
+		}, "", req.Nonce)
+		if err != nil {
+			t.Fatal(err)
+		}
+		envelope, _ := json.Marshal(SendMultiSigEnvelope{SignatureChainID: action.SignatureChainID, HyperliquidChain: "Testnet", MultiSigActionHash: hash.Hex(), Nonce: req.Nonce})
+		types := []apitypes.Type{
+			{Name: "hyperliquidChain", Type: "string"},
+			{Name: "multiSigActionHash", Type: "bytes32"},
+			{Name: "nonce", Type: "uint64"},
+		}
+		if signer := recoverUserSignedAction(t, TestExchangeRequest{Action: envelope, Signature: req.Signature}, types, "HyperliquidTransaction:SendMultiSig"); signer != exchangeAPI.AccountAddress() {
+			t.Errorf("signer = %v, want %v", signer, exchangeAPI.AccountAddress())
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	if _, err := exchangeAPI.MultiSig(multiSigUser, NoopAction{Type: "noop"}, signatures, GetNonce()); err != nil {
+		t.Fatalf("MultiSig() error = %v", err)
+	}
+}
+
+func TestExchangeAPI_MultiSigCollector(t *testing.T) {
+	multiSigUser := "0x000000000000000000000000000000000000000c"
+	var submitted []RsvSignature
+	outerAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action MultiSigAction
+		json.Unmarshal(req.Action, &action)
+		submitted = action.Signatures
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	signerAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any { return nil })
+	outerSigner := outerAPI.AccountAddress()
+	action := NoopAction{Type: "noop"}
+	nonce := GetNonce()
+	collector, err := NewMultiSigCollector(multiSigUser, outerSigner, action, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, api := range []*ExchangeAPI{outerAPI, signerAPI} {
+		sig, err := api.SignMultiSigInner(multiSigUser, outerSigner, action, nonce)
+		if err != nil {
+			t.Fatalf("SignMultiSigInner() error = %v", err)
+		}
+		// the signature is made on the [multiSigUser, outerSigner, action] envelope
+		srequest, _ := api.BuildEIP712Message([]any{multiSigUser, strings.ToLower(outerSigner), action}, nonce, "")
+		hash, _, _ := apitypes.TypedDataAndHash(SignRequestToEIP712TypedData(srequest))
+		signature := append(hexutil.MustDecode(sig.Signature.R), hexutil.MustDecode(sig.Signature.S)...)
+		pub, err := crypto.SigToPub(hash, append(signature, sig.Signature.V-27))
+		if err != nil || crypto.PubkeyToAddress(*pub).Hex() != api.AccountAddress() {
+			t.Errorf("SignMultiSigInner() signature is not made by %v", api.AccountAddress())
+		}
+		blob, _ := json.Marshal(sig)
+		var decoded MultiSigSignature
+		json.Unmarshal(blob, &decoded)
+		if err := collector.Add(&decoded); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	other, _ := signerAPI.SignMultiSigInner(multiSigUser, outerSigner, action, nonce+1)
+	if err := collector.Add(other); err == nil {
+		t.Errorf("Add() expected error for a signature of another nonce")
+	}
+	if collector.Len() != 2 {
+		t.Errorf("Len() = %v, want 2", collector.Len())
+	}
+	if _, err := collector.Submit(signerAPI); err == nil {
+		t.Errorf("Submit() expected error for an api that is not the outer signer")
+	}
+	if _, err := collector.Submit(outerAPI); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if len(submitted) != 2 {
+		t.Errorf("submitted signatures = %v, want 2", submitted)
+	}
+}
+
+func TestExchangeAPI_SpotDeploy(t *testing.T) {
+	var actions []string
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		actions = append(actions, string(req.Action))
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	if _, err := exchangeAPI.SpotDeployRegisterToken("TEST", 2, 8, 1000000, "Test Token"); err != nil {
+		t.Fatalf("SpotDeployRegisterToken() error = %v", err)
+	}
+	users := []UserGenesisBalance{{User: "0x000000000000000000000000000000000000000A", Wei: "1000"}}
+	tokens := []TokenGenesisBalance{{Token: 1, Wei: "500"}}
+	if _, err := exchangeAPI.SpotDeployUserGenesis(7, users, tokens); err != nil {
+		t.Fatalf("SpotDeployUserGenesis() error = %v", err)
+	}
+	if _, err := exchangeAPI.SpotDeployGenesis(7, "1500", true); err != nil {
+		t.Fatalf("SpotDeployGenesis() error = %v", err)
+	}
+	if _, err := exchangeAPI.SpotDeployRegisterSpot(7, 0); err != nil {
+		t.Fatalf("SpotDeployRegisterSpot() error = %v", err)
+	}
+	if _, err := exchangeAPI.SpotDeployRegisterHyperliquidity(3, 1.5, 10, 100, nil); err != nil {
+		t.Fatalf("SpotDeployRegisterHyperliquidity() error = %v", err)
+	}
+	if _, err := exchangeAPI.SpotDeploySetDeployerTradingFeeShare(7, "50%"); err != nil {
+		t.Fatalf("SpotDeploySetDeployerTradingFeeShare() error = %v", err)
+	}
+	expected := []string{
+		`{"type":"spotDeploy","registerToken2":{"spec":{"name":"TEST","szDecimals":2,"weiDecimals":8},"maxGas":1000000,"fullName":"Test Token"}}`,
+		`{"type":"spotDeploy","userGenesis":{"token":7,"userAndWei":[["0x000000000000000000000000000000000000000a","1000"]],"existingTokenAndWei":[[1,"500"]]}}`,
+		`{"type":"spotDeploy","genesis":{"token":7,"maxSupply":"1500","noHyperliquidity":true}}`,
+		`{"type":"spotDeploy","registerSpot":{"tokens":[7,0]}}`,
+		`{"type":"spotDeploy","registerHyperliquidity":{"spot":3,"startPx":"1.5","orderSz":"10","nOrders":100}}`,
+		`{"type":"spotDeploy","setDeployerTradingFeeShare":{"token":7,"share":"50%"}}`,
+	}
+	if len(actions) != len(expected) {
+		t.Fatalf("actions = %v, want %v", actions, expected)
+	}
+	for i := range expected {
+		if actions[i] != expected[i] {
+			t.Errorf("action = %v, want %v", actions[i], expected[i])
+		}
+	}
+}
+
+func TestExchangeAPI_PerpDeploy(t *testing.T) {
+	var actions []string
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		actions = append(actions, string(req.Action))
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	oracleUpdater := "0x000000000000000000000000000000000000000D"
+	schema := &PerpDexSchema{FullName: "Test Dex", CollateralToken: 0, OracleUpdater: &oracleUpdater}
+	if _, err := exchangeAPI.PerpDeployRegisterAsset("test", nil, "test:ABC", 2, "10.5", 10, false, schema); err != nil {
+		t.Fatalf("PerpDeployRegisterAsset() error = %v", err)
+	}
+	oraclePxs := map[string]string{"test:XYZ": "2", "test:ABC": "10.5"}
+	markPxs := []map[string]string{{"test:ABC": "10.4"}}
+	if _, err := exchangeAPI.PerpDeploySetOracle("test", oraclePxs, markPxs, map[string]string{}); err != nil {
+		t.Fatalf("PerpDeploySetOracle() error = %v", err)
+	}
+	expected := []string{
+		`{"type":"perpDeploy","registerAsset":{"maxGas":null,"assetRequest":{"coin":"test:ABC","szDecimals":2,"oraclePx":"10.5","marginTableId":10,"onlyIsolated":false},"dex":"test","schema":{"fullName":"Test Dex","collateralToken":0,"oracleUpdater":"0x000000000000000000000000000000000000000d"}}}`,
+		`{"type":"perpDeploy","setOracle":{"dex":"test","oraclePxs":[["test:ABC","10.5"],["test:XYZ","2"]],"markPxs":[[["test:ABC","10.4"]]],"externalPerpPxs":[]}}`,
+	}
+	if len(actions) != len(expected) {
+		t.Fatalf("actions = %v, want %v", actions, expected)
+	}
+	for i := range expected {
+		if actions[i] != expected[i] {
+			t.Errorf("action = %v, want %v", actions[i], expected[i])
+		}
+	}
+}
+
+func TestExchangeAPI_SetUsingBigBlocks(t *testing.T) {
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action EvmUserModifyAction
+		json.Unmarshal(req.Action, &action)
+		expected := EvmUserModifyAction{Type: "evmUserModify", UsingBigBlocks: true}
+		if action != expected {
+			t.Errorf("action = %+v, want %+v", action, expected)
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	if _, err := exchangeAPI.SetUsingBigBlocks(true); err != nil {
+		t.Fatalf("SetUsingBigBlocks() error = %v", err)
+	}
+}
+
+func TestExchangeAPI_ClaimRewards(t *testing.T) {
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action ClaimRewardsAction
+		json.Unmarshal(req.Action, &action)
+		if action.Type != "claimRewards" {
+			t.Errorf("action = %+v, want claimRewards", action)
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	if _, err := exchangeAPI.ClaimRewards(); err != nil {
+		t.Fatalf("ClaimRewards() error = %v", err)
+	}
+}
+
+func TestExchangeAPI_ValidatorActions(t *testing.T) {
+	var actions []string
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		actions = append(actions, string(req.Action))
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	if _, err := exchangeAPI.CSignerJailSelf(); err != nil {
+		t.Fatalf("CSignerJailSelf() error = %v", err)
+	}
+	if _, err := exchangeAPI.CSignerUnjailSelf(); err != nil {
+		t.Fatalf("CSignerUnjailSelf() error = %v", err)
+	}
+	if _, err := exchangeAPI.CValidatorRotateSigner("0x000000000000000000000000000000000000000E"); err != nil {
+		t.Fatalf("CValidatorRotateSigner() error = %v", err)
+	}
+	expected := []string{
+		`{"type":"CSignerAction","jailSelf":null}`,
+		`{"type":"CSignerAction","unjailSelf":null}`,
+		`{"type":"CValidatorAction","changeProfile":{"node_ip":null,"name":null,"description":null,"unjailed":false,"disable_delegations":null,"commission_bps":null,"signer":"0x000000000000000000000000000000000000000e"}}`,
+	}
+	if len(actions) != len(expected) {
+		t.Fatalf("actions = %v, want %v", actions, expected)
+	}
+	for i := range expected {
+		if actions[i] != expected[i] {
+			t.Errorf("action = %v, want %v", actions[i], expected[i])
+		}
+	}
+}
+
+func TestExchangeAPI_BulkOrdersWithBuilder(t *testing.T) {
+	var actions []PlaceOrderAction
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action PlaceOrderAction
+		json.Unmarshal(req.Action, &action)
+		actions = append(actions, action)
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "order", "data": map[string]any{"statuses": []any{map[string]any{"resting": map[string]any{"oid": 1}}}}}}
+	})
+	builder := &Builder{Address: "0x000000000000000000000000000000000000000F", Fee: 10}
+	order := OrderRequest{Coin: "ETH", IsBuy: true, Sz: 0.1, LimitPx: 2000, OrderType: OrderType{Limit: &LimitOrderType{Tif: TifGtc}}, Builder: builder}
+	if _, err := exchangeAPI.BulkOrders([]OrderRequest{order, order}, GroupingNa); err != nil {
+		t.Fatalf("BulkOrders() error = %v", err)
+	}
+	if len(actions) != 1 || actions[0].Builder == nil || *actions[0].Builder != (Builder{Address: "0x000000000000000000000000000000000000000f", Fee: 10}) {
+		t.Errorf("actions = %+v, want builder 0x...0f with fee 10", actions)
+	}
+	other := order
+	other.Builder = nil
+	if _, err := exchangeAPI.BulkOrders([]OrderRequest{order, other}, GroupingNa); err == nil {
+		t.Errorf("BulkOrders() expected error for orders with different builders")
+	}
+	if _, err := exchangeAPI.BulkOrders([]OrderRequest{other}, GroupingNa); err != nil {
+		t.Fatalf("BulkOrders() error = %v", err)
+	}
+	if len(actions) != 2 || actions[1].Builder != nil {
+		t.Errorf("actions = %+v, want no builder", actions)
+	}
+}
+
+func TestExchangeAPI_OrderWithTpSl(t *testing.T) {
+	var actions []PlaceOrderAction
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action PlaceOrderAction
+		json.Unmarshal(req.Action, &action)
+		actions = append(actions, action)
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "order", "data": map[string]any{"statuses": []any{"waitingForFill", "waitingForTrigger", "waitingForTrigger"}}}}
+	})
+	trigger := func(px float64, tpsl TpSl) *OrderRequest {
+		return &OrderRequest{Coin: "ETH", IsBuy: false, Sz: 0.1, LimitPx: px, ReduceOnly: true,
+			OrderType: OrderType{Trigger: &TriggerOrderType{IsMarket: true, TriggerPx: PriceToWire(px, PERP_MAX_DECIMALS, 4), TpSl: tpsl}}}
+	}
+	entry := &OrderRequest{Coin: "ETH", IsBuy: true, Sz: 0.1, LimitPx: 2000, OrderType: OrderType{Limit: &LimitOrderType{Tif: TifGtc}}}
+	request := TpSlOrderRequest{Entry: entry, TakeProfit: trigger(2200, TriggerTp), StopLoss: trigger(1900, TriggerSl)}
+	if _, err := exchangeAPI.OrderWithTpSl(request, GroupingNormalTpSl); err != nil {
+		t.Fatalf("OrderWithTpSl() error = %v", err)
+	}
+	if len(actions) != 1 || actions[0].Grouping != GroupingNormalTpSl || len(actions[0].Orders) != 3 || actions[0].Orders[1].OrderType.Trigger.TpSl != TriggerTp {
+		t.Errorf("actions = %+v, want a normalTpsl action with entry, tp and sl", actions)
+	}
+	if _, err := exchangeAPI.OrderWithTpSl(TpSlOrderRequest{StopLoss: trigger(1900, TriggerSl)}, GroupingPositionTpSl); err != nil {
+		t.Fatalf("OrderWithTpSl() error = %v", err)
+	}
+	invalid := []struct {
+		name     string
+		request  TpSlOrderRequest
+		grouping Grouping
+	}{
+		{"missing entry", TpSlOrderRequest{TakeProfit: trigger(2200, TriggerTp)}, GroupingNormalTpSl},
+		{"entry with position grouping", request, GroupingPositionTpSl},
+		{"na grouping", request, GroupingNa},
+		{"no children", TpSlOrderRequest{Entry: entry}, GroupingNormalTpSl},
+		{"swapped children", TpSlOrderRequest{Entry: entry, TakeProfit: trigger(1900, TriggerSl)}, GroupingNormalTpSl},
+		{"different size", TpSlOrderRequest{Entry: entry, TakeProfit: &OrderRequest{Coin: "ETH", Sz: 0.2, ReduceOnly: true, OrderType: trigger(2200, TriggerTp).OrderType}}, GroupingNormalTpSl},
+		{"same side", TpSlOrderRequest{Entry: entry, TakeProfit: &OrderRequest{Coin: "ETH", IsBuy: true, Sz: 0.1, ReduceOnly: true, OrderType: trigger(2200, TriggerTp).OrderType}}, GroupingNormalTpSl},
+		{"not reduce only", TpSlOrderRequest{Entry: entry, TakeProfit: &OrderRequest{Coin: "ETH", Sz: 0.1, OrderType: trigger(2200, TriggerTp).OrderType}}, GroupingNormalTpSl},
+	}
+	for _, tt := range invalid {
+		if _, err := exchangeAPI.OrderWithTpSl(tt.request, tt.grouping); err == nil {
+			t.Errorf("OrderWithTpSl() expected error for %s", tt.name)
+		}
+	}
+	if len(actions) != 2 {
+		t.Errorf("invalid requests were sent: %+v", actions[2:])
+	}
+}
+
+func TestExchangeAPI_BracketOrder(t *testing.T) {
+	var actions []PlaceOrderAction
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action PlaceOrderAction
+		json.Unmarshal(req.Action, &action)
+		actions = append(actions, action)
+		statuses := []any{map[string]any{"filled": map[string]any{"oid": 10, "avgPx": "1999.5", "totalSz": "0.5"}}, map[string]any{"resting": map[string]any{"oid": 11}}, map[string]any{"resting": map[string]any{"oid": 12}}}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "order", "data": map[string]any{"statuses": statuses}}}
+	})
+	res, err := exchangeAPI.BracketOrder("ETH", -0.5, 2000, 1800, 2100)
+	if err != nil {
+		t.Fatalf("BracketOrder() error = %v", err)
+	}
+	if res.EntryOid != 10 || res.TakeProfitOid != 11 || res.StopLossOid != 12 {
+		t.Errorf("BracketOrder() = %+v, want oids 10, 11, 12", res)
+	}
+	orders := actions[0].Orders
+	if actions[0].Grouping != GroupingNormalTpSl || len(orders) != 3 || orders[0].IsBuy || !orders[1].IsBuy || !orders[2].ReduceOnly {
+		t.Fatalf("action = %+v, want a short entry with buy reduce only children", actions[0])
+	}
+	if orders[1].OrderType.Trigger.TriggerPx != "1800" || orders[2].OrderType.Trigger.TriggerPx != "2100" || orders[1].SizePx != "0.5" {
+		t.Errorf("children = %+v, want triggers at 1800 and 2100 of size 0.5", orders[1:])
+	}
+	if _, err := exchangeAPI.BracketOrder("ETH", 0.5, 2000, 1800, 2100); err == nil {
+		t.Errorf("BracketOrder() expected invalid prices error for a long entry")
+	}
+	if _, err := exchangeAPI.BracketOrder("UNKNOWN", 0.5, 2000, 2100, 1800); err == nil {
+		t.Errorf("BracketOrder() expected unknown coin error")
+	}
+}
+
+func TestExchangeAPI_MarketOrderSmart(t *testing.T) {
+	var actions []PlaceOrderAction
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action PlaceOrderAction
+		json.Unmarshal(req.Action, &action)
+		actions = append(actions, action)
+		statuses := []any{map[string]any{"filled": map[string]any{"oid": 10, "avgPx": "2001", "totalSz": "1.5"}}}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "order", "data": map[string]any{"statuses": statuses}}}
+	})
+	exchangeAPI.infoAPI = GetTestInfoAPI(t, func(req InfoRequest) any {
+		return map[string]any{"coin": "ETH", "time": 1, "levels": [][]map[string]any{
+			{{"px": "1999", "sz": "1", "n": 1}, {"px": "1990", "sz": "2", "n": 2}, {"px": "1900", "sz": "50", "n": 3}},
+			{{"px": "2001", "sz": "1", "n": 1}, {"px": "2002", "sz": "1", "n": 1}, {"px": "2100", "sz": "50", "n": 3}},
+		}}
+	})
+	if _, err := exchangeAPI.MarketOrderSmart("ETH", 1.5, nil); err != nil {
+		t.Fatalf("MarketOrderSmart() error = %v", err)
+	}
+	if order := actions[0].Orders[0]; !order.IsBuy || order.LimitPx != "2002" || order.OrderType.Limit.Tif != TifIoc {
+		t.Errorf("order = %+v, want an IOC buy at the second ask level 2002", order)
+	}
+	if _, err := exchangeAPI.MarketOrderSmart("ETH", -3, nil); err != nil {
+		t.Fatalf("MarketOrderSmart() error = %v", err)
+	}
+	if order := actions[1].Orders[0]; order.IsBuy || order.LimitPx != "1990" {
+		t.Errorf("order = %+v, want a sell at the second bid level 1990", order)
+	}
+	// The 2100 level is beyond the default slippage, so only 2 ETH can be bought
+	_, err := exchangeAPI.MarketOrderSmart("ETH", 5, nil)
+	var liquidityErr InsufficientLiquidityError
+	if !errors.As(err, &liquidityErr) || liquidityErr.Available != 2 {
+		t.Errorf("MarketOrderSmart() error = %v, want InsufficientLiquidityError with 2 available", err)
+	}
+	if len(actions) != 2 {
+		t.Errorf("sent %d orders, want no order when the book is too thin", len(actions))
+	}
+}
+
+func TestExchangeAPI_ClosePositionPartial(t *testing.T) {
+	var actions []PlaceOrderAction
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action PlaceOrderAction
+		json.Unmarshal(req.Action, &action)
+		actions = append(actions, action)
+		statuses := []any{map[string]any{"filled": map[string]any{"oid": 10, "avgPx": "2000", "totalSz": "0.5"}}}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "order", "data": map[string]any{"statuses": statuses}}}
+	})
+	exchangeAPI.infoAPI = GetTestInfoAPI(t, func(req InfoRequest) any {
+		if req.Type == "allMids" {
+			return map[string]string{"ETH": "2000"}
+		}
+		return map[string]any{"assetPositions": []any{map[string]any{"type": "oneWay", "position": map[string]any{"coin": "ETH", "szi": "-1.2345"}}}}
+	})
+	tests := []struct {
+		close func() (*OrderResponse, error)
+		want  string
+	}{
+		{func() (*OrderResponse, error) { return exchangeAPI.ClosePositionPartial("ETH", 0.5) }, "0.5"},
+		{func() (*OrderResponse, error) { return exchangeAPI.ClosePositionPartial("ETH", 5) }, "1.2345"},
+		{func() (*OrderResponse, error) { return exchangeAPI.ClosePositionPercent("ETH", 50) }, "0.6172"},
+		{func() (*OrderResponse, error) { return exchangeAPI.ClosePositionPercent("ETH", 100) }, "1.2345"},
+	}
+	for i, tt := range tests {
+		if _, err := tt.close(); err != nil {
+			t.Fatalf("close %d error = %v", i, err)
+		}
+		if order := actions[i].Orders[0]; !order.IsBuy || !order.ReduceOnly || order.SizePx != tt.want {
+			t.Errorf("close %d order = %+v, want a reduce only buy of %s", i, order, tt.want)
+		}
+	}
+	if _, err := exchangeAPI.ClosePositionPercent("ETH", 0.001); err == nil {
+		t.Errorf("ClosePositionPercent() expected minimum size error")
+	}
+	if _, err := exchangeAPI.ClosePositionPercent("ETH", 150); err == nil {
+		t.Errorf("ClosePositionPercent() expected invalid percent error")
+	}
+	if _, err := exchangeAPI.ClosePositionPartial("BTC", 1); err == nil {
+		t.Errorf("ClosePositionPartial() expected no position error")
+	}
+}
+
+func TestExchangeAPI_CancelOrdersByCloid(t *testing.T) {
+	var actions []CancelCloidOrderAction
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action CancelCloidOrderAction
+		json.Unmarshal(req.Action, &action)
+		actions = append(actions, action)
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "cancel", "data": map[string]any{"statuses": []any{"success", "success"}}}}
+	})
+	cloids := []string{"0x00000000000000000000000000000001", "0x00000000000000000000000000000002"}
+	if _, err := exchangeAPI.CancelOrdersByCloid("ETH", cloids); err != nil {
+		t.Fatalf("CancelOrdersByCloid() error = %v", err)
+	}
+	want := []CancelCloidWire{{Asset: 1, Cloid: cloids[0]}, {Asset: 1, Cloid: cloids[1]}}
+	if len(actions) != 1 || actions[0].Type != "cancelByCloid" || len(actions[0].Cancels) != 2 || actions[0].Cancels[0] != want[0] || actions[0].Cancels[1] != want[1] {
+		t.Errorf("actions = %+v, want a single cancelByCloid of %+v", actions, want)
+	}
+	if _, err := exchangeAPI.CancelOrdersByCloid("ETH", []string{"not a cloid"}); err == nil {
+		t.Errorf("CancelOrdersByCloid() expected invalid cloid error")
+	}
+	if _, err := exchangeAPI.CancelOrdersByCloid("UNKNOWN", cloids); err == nil {
+		t.Errorf("CancelOrdersByCloid() expected unknown coin error")
+	}
+}
+
+func TestExchangeAPI_ModifyOrderByCloid(t *testing.T) {
+	var actions []ModifyAction
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action ModifyAction
+		json.Unmarshal(req.Action, &action)
+		actions = append(actions, action)
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	cloid := "0x00000000000000000000000000000001"
+	order := OrderRequest{Coin: "ETH", IsBuy: true, Sz: 0.1, LimitPx: 1950, OrderType: OrderType{Limit: &LimitOrderType{Tif: TifGtc}}, Cloid: cloid}
+	if _, err := exchangeAPI.ModifyOrderByCloid(cloid, order); err != nil {
+		t.Fatalf("ModifyOrderByCloid() error = %v", err)
+	}
+	oid := 7
+	if _, err := exchangeAPI.ModifyOrder(ModifyOrderRequest{Oid: &oid, Order: order}); err != nil {
+		t.Fatalf("ModifyOrder() error = %v", err)
+	}
+	if actions[0].Type != "modify" || actions[0].Oid != cloid || actions[0].Order.LimitPx != "1950" || actions[0].Order.Cloid != cloid {
+		t.Errorf("action = %+v, want modify of %s at 1950", actions[0], cloid)
+	}
+	if actions[1].Oid != float64(7) {
+		t.Errorf("action = %+v, want modify of oid 7", actions[1])
+	}
+	if _, err := exchangeAPI.ModifyOrderByCloid("not a cloid", order); err == nil {
+		t.Errorf("ModifyOrderByCloid() expected invalid cloid error")
+	}
+	if _, err := exchangeAPI.ModifyOrder(ModifyOrderRequest{Order: order}); err == nil {
+		t.Errorf("ModifyOrder() expected missing oid error")
+	}
+}
+
+func TestExchangeAPI_PostOnlyOrder(t *testing.T) {
+	var orders []OrderWire
+	rejections := []string{
+		"Post only order would have immediately matched, bbo was 1999.9@2000.1",
+		"Post only order would have immediately matched",
+	}
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action PlaceOrderAction
+		json.Unmarshal(req.Action, &action)
+		orders = append(orders, action.Orders...)
+		var status any = map[string]any{"resting": map[string]any{"oid": 3}}
+		if len(orders) <= len(rejections) {
+			status = map[string]any{"error": rejections[len(orders)-1]}
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "order", "data": map[string]any{"statuses": []any{status}}}}
+	})
+	res, err := exchangeAPI.PostOnlyOrder("ETH", 0.1, 2001, false, 3)
+	if err != nil {
+		t.Fatalf("PostOnlyOrder() error = %v", err)
+	}
+	if res.Response.Data.Statuses[0].OrderID() != 3 {
+		t.Errorf("PostOnlyOrder() = %+v, want resting oid 3", res)
+	}
+	want := []string{"2001", "2000", "1999.9"}
+	if len(orders) != len(want) {
+		t.Fatalf("orders = %+v, want %d attempts", orders, len(want))
+	}
+	for i, order := range orders {
+		if order.LimitPx != want[i] || order.OrderType.Limit.Tif != TifAlo {
+			t.Errorf("attempt %d = %+v, want Alo at %s", i, order, want[i])
+		}
+	}
+
+	orders = nil
+	res, err = exchangeAPI.PostOnlyOrder("ETH", 0.1, 2001, false, 1)
+	if err != nil {
+		t.Fatalf("PostOnlyOrder() error = %v", err)
+	}
+	if len(orders) != 2 || res.Response.Data.Statuses[0].Error != rejections[1] {
+		t.Errorf("PostOnlyOrder() = %+v after %d attempts, want the rejection of the second attempt", res, len(orders))
+	}
+}
+
+func TestExchangeAPI_ReplaceOrder(t *testing.T) {
+	cloid := "0x00000000000000000000000000000001"
+	modifyError := ""
+	var types []string
+	var placed []OrderWire
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action struct {
+			Type     string            `json:"type"`
+			Orders   []OrderWire       `json:"orders"`
+			Modifies []ModifyOrderWire `json:"modifies"`
+		}
+		json.Unmarshal(req.Action, &action)
+		types = append(types, action.Type)
+		var status any = map[string]any{"resting": map[string]any{"oid": 11}}
+		switch action.Type {
+		case "batchModify":
+			if modifyError != "" {
+				status = map[string]any{"error": modifyError}
+			}
+		case "cancel":
+			status = "success"
+		case "order":
+			placed = append(placed, action.Orders...)
+			status = map[string]any{"resting": map[string]any{"oid": 12}}
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "order", "data": map[string]any{"statuses": []any{status}}}}
+	})
+	exchangeAPI.infoAPI = GetTestInfoAPI(t, func(req InfoRequest) any {
+		return map[string]any{"status": "order", "order": map[string]any{"status": "open", "order": map[string]any{
+			"coin": "ETH", "oid": 7, "cloid": cloid, "side": "A", "tif": "Alo", "limitPx": "2100", "sz": "0.1", "orderType": "Limit",
+		}}}
+	})
+	res, err := exchangeAPI.ReplaceOrder(7, 2050, 0.2)
+	if err != nil {
+		t.Fatalf("ReplaceOrder() error = %v", err)
+	}
+	if res.Path != ReplacePathModify || res.Oid != 11 || res.Cloid != cloid || len(types) != 1 {
+		t.Errorf("ReplaceOrder() = %+v after %v, want a modify to oid 11", res, types)
+	}
+
+	modifyError = "Cannot modify order"
+	types = nil
+	res, err = exchangeAPI.ReplaceOrder(7, 2050, 0.2)
+	if err != nil {
+		t.Fatalf("ReplaceOrder() error = %v", err)
+	}
+	if res.Path != ReplacePathCancelReplace || res.Oid != 12 || strings.Join(types, ",") != "batchModify,cancel,order" {
+		t.Errorf("ReplaceOrder() = %+v after %v, want a cancel and replace to oid 12", res, types)
+	}
+	if order := placed[0]; order.IsBuy || order.LimitPx != "2050" || order.SizePx != "0.2" || order.Cloid != cloid || order.OrderType.Limit.Tif != TifAlo {
+		t.Errorf("order = %+v, want an Alo sell of 0.2 at 2050 with the same cloid", order)
+	}
+}
+
+func TestExchangeAPI_ValidateOrder(t *testing.T) {
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		t.Errorf("ValidateOrder() sent %s", req.Action)
+		return nil
+	})
+	exchangeAPI.infoAPI = GetTestInfoAPI(t, marginTestInfoHandler(100, 0))
+	limit := OrderType{Limit: &LimitOrderType{Tif: TifGtc}}
+	request, err := exchangeAPI.ValidateOrder(OrderRequest{Coin: "ETH", IsBuy: true, Sz: 0.01, LimitPx: 2000.5, OrderType: limit})
+	if err != nil {
+		t.Fatalf("ValidateOrder() error = %v", err)
+	}
+	action := request.Action.(PlaceOrderAction)
+	if order := action.Orders[0]; order.Asset != 1 || order.LimitPx != "2000.5" || order.SizePx != "0.01" || request.Signature.R == "" {
+		t.Errorf("ValidateOrder() = %+v, want a signed order of 0.01 at 2000.5", request)
+	}
+	invalid := []OrderRequest{
+		{Coin: "UNKNOWN", IsBuy: true, Sz: 1, LimitPx: 2000, OrderType: limit},
+		{Coin: "ETH", IsBuy: true, Sz: 0.00001, LimitPx: 2000, OrderType: limit},
+		{Coin: "ETH", IsBuy: true, Sz: 0.01, LimitPx: 2000.55, OrderType: limit},
+		{Coin: "ETH", IsBuy: true, Sz: 0.001, LimitPx: 2000, OrderType: limit},
+		{Coin: "ETH", IsBuy: false, Sz: 0.01, LimitPx: 1900, ReduceOnly: true, OrderType: OrderType{Trigger: &TriggerOrderType{IsMarket: true, TriggerPx: "1900.01", TpSl: TriggerSl}}},
+	}
+	for i, req := range invalid {
+		if _, err := exchangeAPI.ValidateOrder(req); err == nil {
+			t.Errorf("ValidateOrder(%d) expected error for %+v", i, req)
+		}
+	}
+	// Reduce only orders are not subject to the minimum value
+	if _, err := exchangeAPI.ValidateOrder(OrderRequest{Coin: "ETH", IsBuy: false, Sz: 0.001, LimitPx: 2000, ReduceOnly: true, OrderType: limit}); err != nil {
+		t.Errorf("ValidateOrder() error = %v", err)
+	}
+}
+
+func TestExchangeAPI_OrderTooSmall(t *testing.T) {
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		t.Errorf("sent %s, want no request for a too small order", req.Action)
+		return nil
+	})
+	_, err := exchangeAPI.LimitOrder(TifGtc, "ETH", 0.004, 2000, false)
+	var tooSmall OrderTooSmallError
+	if !errors.Is(err, ErrOrderTooSmall) || !errors.As(err, &tooSmall) || tooSmall.Notional != 8 || tooSmall.MinNotional != MIN_ORDER_NOTIONAL {
+		t.Errorf("LimitOrder() error = %v, want ErrOrderTooSmall with a notional of 8", err)
+	}
+	// Reduce only orders are not subject to the minimum value, but must be at least one lot
+	_, err = exchangeAPI.LimitOrder(TifGtc, "ETH", -0.00004, 2000, true)
+	if !errors.As(err, &tooSmall) || tooSmall.Sz != 0 || tooSmall.MinSz != 0.0001 {
+		t.Errorf("LimitOrder() error = %v, want ErrOrderTooSmall below the minimum size 0.0001", err)
+	}
+	// Unknown coins are rejected before the size checks, whatever the size
+	limit := OrderType{Limit: &LimitOrderType{Tif: TifGtc}}
+	for _, sz := range []float64{0.5, 2} {
+		_, err = exchangeAPI.BulkOrders([]OrderRequest{{Coin: "FOO", IsBuy: true, Sz: sz, LimitPx: 20, OrderType: limit}}, GroupingNa)
+		if err == nil || errors.As(err, &tooSmall) || !strings.Contains(err.Error(), "Unknown coin") {
+			t.Errorf("BulkOrders(%v FOO) error = %v, want unknown coin", sz, err)
+		}
+	}
+}
+
+// marginTestInfoHandler answers the info requests of CanPlaceOrder for an account worth accountValue
+// with an ETH position of szi at 10x leverage.
+func marginTestInfoHandler(accountValue float64, szi float64) func(req InfoRequest) any {
+	return func(req InfoRequest) any {
+		if req.Type == "metaAndAssetCtxs" {
+			return []any{
+				map[string]any{"universe": []any{map[string]any{"name": "BTC", "szDecimals": 5, "maxLeverage": 40}, map[string]any{"name": "ETH", "szDecimals": 4, "maxLeverage": 25}}},
+				[]any{map[string]any{"markPx": "100000"}, map[string]any{"markPx": "2000"}},
+			}
+		}
+		var positions []any
+		if szi != 0 {
+			positions = append(positions, map[string]any{"type": "oneWay", "position": map[string]any{
+				"coin": "ETH", "szi": strconv.FormatFloat(szi, 'f', -1, 64), "leverage": map[string]any{"type": "cross", "value": 10}, "marginUsed": "20",
+			}})
+		}
+		return map[string]any{
+			"marginSummary":  map[string]any{"accountValue": strconv.FormatFloat(accountValue, 'f', -1, 64), "totalMarginUsed": "20"},
+			"assetPositions": positions,
+		}
+	}
+}
+
+func TestExchangeAPI_CanPlaceOrder(t *testing.T) {
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any { return nil })
+	exchangeAPI.infoAPI = GetTestInfoAPI(t, marginTestInfoHandler(100, 0.1))
+	limit := OrderType{Limit: &LimitOrderType{Tif: TifGtc}}
+	tests := []struct {
+		req      OrderRequest
+		allowed  bool
+		required float64
+		leverage int
+	}{
+		// 0.4 ETH at 2000 with the 10x leverage of the position
+		{OrderRequest{Coin: "ETH", IsBuy: true, Sz: 0.4, LimitPx: 2000, OrderType: limit}, true, 80, 10},
+		{OrderRequest{Coin: "ETH", IsBuy: true, Sz: 0.5, LimitPx: 2000, OrderType: limit}, false, 100, 10},
+		// 0.1 ETH of the sell closes the long position
+		{OrderRequest{Coin: "ETH", IsBuy: false, Sz: 0.5, LimitPx: 2000, OrderType: limit}, true, 80, 10},
+		// No BTC position, the default leverage is used
+		{OrderRequest{Coin: "BTC", IsBuy: true, Sz: 0.01, LimitPx: 100000, OrderType: limit}, true, 50, DEFAULT_LEVERAGE},
+	}
+	for i, tt := range tests {
+		check, err := exchangeAPI.CanPlaceOrder(tt.req)
+		if err != nil {
+			t.Fatalf("CanPlaceOrder(%d) error = %v", i, err)
+		}
+		if check.Allowed != tt.allowed || math.Abs(check.RequiredMargin-tt.required) > 1e-9 || check.Leverage != tt.leverage || check.AvailableMargin != 80 {
+			t.Errorf("CanPlaceOrder(%d) = %+v, want allowed %v, required %v at %dx of 80 available", i, check, tt.allowed, tt.required, tt.leverage)
+		}
+	}
+	if _, err := exchangeAPI.ValidateOrder(tests[1].req); err == nil {
+		t.Errorf("ValidateOrder() expected insufficient margin error")
+	}
+	if _, err := exchangeAPI.CanPlaceOrder(OrderRequest{Coin: "UNKNOWN", Sz: 1, LimitPx: 1}); err == nil {
+		t.Errorf("CanPlaceOrder() expected unknown coin error")
+	}
+}
+
+func TestExchangeAPI_KillSwitch(t *testing.T) {
+	var types []string
+	var closes []OrderWire
+	var cancels []CancelOidWire
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action struct {
+			Type    string          `json:"type"`
+			Orders  []OrderWire     `json:"orders"`
+			Cancels []CancelOidWire `json:"cancels"`
+		}
+		json.Unmarshal(req.Action, &action)
+		types = append(types, action.Type)
+		switch action.Type {
+		case "cancel":
+			cancels = action.Cancels
+			return map[string]any{"status": "ok", "response": map[string]any{"type": "cancel", "data": map[string]any{"statuses": []any{"success", map[string]any{"error": "Order was never placed, already canceled, or filled."}}}}}
+		case "order":
+			closes = action.Orders
+			statuses := []any{map[string]any{"filled": map[string]any{"oid": 5, "avgPx": "2000", "totalSz": "0.5"}}, map[string]any{"error": "Reduce only order would increase position."}}
+			return map[string]any{"status": "ok", "response": map[string]any{"type": "order", "data": map[string]any{"statuses": statuses}}}
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	exchangeAPI.meta["BTC"] = AssetInfo{AssetID: 0, SzDecimals: 5}
+	exchangeAPI.infoAPI = GetTestInfoAPI(t, func(req InfoRequest) any {
+		switch req.Type {
+		case "openOrders":
+			return []any{map[string]any{"coin": "ETH", "oid": 7, "side": "B"}, map[string]any{"coin": "PURR/USDC", "oid": 8, "side": "A"}, map[string]any{"coin": "FOO", "oid": 9, "side": "B"}}
+		case "allMids":
+			return map[string]string{"ETH": "2000", "BTC": "100000"}
+		}
+		return map[string]any{"assetPositions": []any{
+			map[string]any{"type": "oneWay", "position": map[string]any{"coin": "ETH", "szi": "0.5"}},
+			map[string]any{"type": "oneWay", "position": map[string]any{"coin": "BTC", "szi": "-0.01"}},
+			map[string]any{"type": "oneWay", "position": map[string]any{"coin": "FOO", "szi": "0.5"}},
+		}}
+	})
+	// No rule can block the shutdown
+	exchangeAPI.SetRiskGuard(&RiskGuard{Rules: []RiskRule{func(api *ExchangeAPI, req OrderRequest) error {
+		return errors.New("trading halted")
+	}}})
+	report := exchangeAPI.KillSwitch()
+	if strings.Join(types, ",") != "cancel,order,scheduleCancel" {
+		t.Fatalf("actions = %v, want cancel, order and scheduleCancel", types)
+	}
+	if len(cancels) != 2 || cancels[0] != (CancelOidWire{Asset: 1, Oid: 7}) || cancels[1] != (CancelOidWire{Asset: 10000, Oid: 8}) {
+		t.Errorf("cancels = %+v, want ETH oid 7 and PURR/USDC oid 8", cancels)
+	}
+	if len(closes) != 2 || closes[0].IsBuy || !closes[1].IsBuy || !closes[0].ReduceOnly || closes[1].SizePx != "0.01" {
+		t.Errorf("closes = %+v, want a reduce only sell of ETH and buy of 0.01 BTC", closes)
+	}
+	if report.CanceledOrders != 1 || len(report.ClosedPositions) != 1 || report.ClosedPositions[0] != "ETH" || report.CloseErrors["BTC"] == "" || report.ScheduleCancelTime == 0 {
+		t.Errorf("KillSwitch() = %+v, want 1 order canceled, ETH closed and BTC failed", report)
+	}
+	if report.CloseErrors["FOO"] != "unknown coin" {
+		t.Errorf("CloseErrors = %v, want the unknown FOO position", report.CloseErrors)
+	}
+	if report.CancelErr == nil || !strings.Contains(report.CancelErr.Error(), "FOO") {
+		t.Errorf("CancelErr = %v, want the unknown FOO order", report.CancelErr)
+	}
+	report.CancelErr = nil
+	report.CloseErrors["ARB"] = "Insufficient margin"
+	for i := 0; i < 10; i++ {
+		if err := report.Err(); err == nil || !strings.Contains(err.Error(), "ARB") {
+			t.Fatalf("Err() = %v, want the ARB close error first", err)
+		}
+	}
+}
+
+func TestExchangeAPI_UpdateLeverageValidation(t *testing.T) {
+	var actions []UpdateLeverageAction
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action UpdateLeverageAction
+		json.Unmarshal(req.Action, &action)
+		actions = append(actions, action)
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	exchangeAPI.meta["ETH"] = AssetInfo{AssetID: 1, SzDecimals: 4, MaxLeverage: 25}
+	exchangeAPI.meta["MEME"] = AssetInfo{AssetID: 2, SzDecimals: 0, MaxLeverage: 3, OnlyIsolated: true}
+	if _, err := exchangeAPI.UpdateLeverage("ETH", true, 25); err != nil {
+		t.Fatalf("UpdateLeverage() error = %v", err)
+	}
+	if _, err := exchangeAPI.UpdateLeverage("MEME", false, 3); err != nil {
+		t.Fatalf("UpdateLeverage() error = %v", err)
+	}
+	if len(actions) != 2 || actions[0].Asset != 1 || actions[0].Leverage != 25 || actions[1].Asset != 2 || actions[1].IsCross {
+		t.Errorf("actions = %+v, want ETH 25x cross and MEME 3x isolated", actions)
+	}
+	invalid := []struct {
+		coin     string
+		isCross  bool
+		leverage int
+	}{
+		{"ETH", true, 26},
+		{"ETH", true, 0},
+		{"MEME", true, 2},
+		{"UNKNOWN", true, 1},
+	}
+	for _, tt := range invalid {
+		if _, err := exchangeAPI.UpdateLeverage(tt.coin, tt.isCross, tt.leverage); err == nil {
+			t.Errorf("UpdateLeverage(%s, %v, %d) expected error", tt.coin, tt.isCross, tt.leverage)
+		}
+	}
+	if len(actions) != 2 {
+		t.Errorf("sent %d actions, want invalid updates rejected before signing", len(actions))
+	}
+}
+
+func TestExchangeAPI_SetMarginMode(t *testing.T) {
+	var actions []UpdateLeverageAction
+	leverageType, frozen := "cross", false
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action UpdateLeverageAction
+		json.Unmarshal(req.Action, &action)
+		actions = append(actions, action)
+		if action.Asset == 1 && !frozen {
+			leverageType = map[bool]string{true: "cross", false: "isolated"}[action.IsCross]
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	exchangeAPI.meta["ETH"] = AssetInfo{AssetID: 1, SzDecimals: 4, MaxLeverage: 25}
+	exchangeAPI.meta["MEME"] = AssetInfo{AssetID: 2, SzDecimals: 0, MaxLeverage: 5}
+	var openOrders []any
+	exchangeAPI.infoAPI = GetTestInfoAPI(t, func(req InfoRequest) any {
+		if req.Type == "openOrders" {
+			return openOrders
+		}
+		return map[string]any{"assetPositions": []any{map[string]any{"type": "oneWay", "position": map[string]any{
+			"coin": "ETH", "szi": "0.5", "leverage": map[string]any{"type": leverageType, "value": 10},
+		}}}}
+	})
+	if _, err := exchangeAPI.SetMarginMode("ETH", true); err != nil {
+		t.Fatalf("SetMarginMode() error = %v", err)
+	}
+	if _, err := exchangeAPI.SetMarginMode("MEME", false); err != nil {
+		t.Fatalf("SetMarginMode() error = %v", err)
+	}
+	if len(actions) != 2 || actions[0].IsCross || actions[0].Leverage != 10 || !actions[1].IsCross || actions[1].Leverage != 5 {
+		t.Errorf("actions = %+v, want ETH isolated at the position leverage and MEME cross at its max leverage", actions)
+	}
+	// The ETH position stays isolated after a switch to cross
+	frozen = true
+	if _, err := exchangeAPI.SetMarginMode("ETH", false); err == nil {
+		t.Errorf("SetMarginMode() expected verification error")
+	}
+	openOrders = []any{map[string]any{"coin": "ETH", "oid": 1, "side": "B"}}
+	if _, err := exchangeAPI.SetMarginMode("ETH", true); err == nil {
+		t.Errorf("SetMarginMode() expected open orders error")
+	}
+}
+
+func TestExchangeAPI_SpotOrders(t *testing.T) {
+	var actions []PlaceOrderAction
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action PlaceOrderAction
+		json.Unmarshal(req.Action, &action)
+		actions = append(actions, action)
+		statuses := []any{map[string]any{"resting": map[string]any{"oid": 10}}}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "order", "data": map[string]any{"statuses": statuses}}}
+	})
+	spotMeta := map[string]any{
+		"tokens": []any{
+			map[string]any{"name": "USDC", "index": 0, "szDecimals": 8, "weiDecimals": 8},
+			map[string]any{"name": "PURR", "index": 1, "szDecimals": 0, "weiDecimals": 5},
+			map[string]any{"name": "HYPE", "index": 150, "szDecimals": 2, "weiDecimals": 8},
+		},
+		"universe": []any{
+			map[string]any{"name": "PURR/USDC", "index": 0, "tokens": []int{1, 0}},
+			map[string]any{"name": "@107", "index": 107, "tokens": []int{150, 0}},
+		},
+	}
+	exchangeAPI.infoAPI = GetTestInfoAPI(t, func(req InfoRequest) any {
+		if req.Type == "spotMetaAndAssetCtxs" {
+			return []any{spotMeta, []any{map[string]any{"coin": "@107", "midPx": "25.0"}}}
+		}
+		return spotMeta
+	})
+	if _, err := exchangeAPI.SpotLimitOrder(TifGtc, "HYPE/USDC", 1.234, 24.123456); err != nil {
+		t.Fatalf("SpotLimitOrder() error = %v", err)
+	}
+	if order := actions[0].Orders[0]; order.Asset != 10107 || !order.IsBuy || order.SizePx != "1.23" || order.LimitPx != "24.123" || order.OrderType.Limit.Tif != TifGtc {
+		t.Errorf("order = %+v, want a GTC buy of 1.23 on asset 10107 at 24.123", order)
+	}
+	if _, err := exchangeAPI.SpotMarketOrder("hype/usdc", -2, nil); err != nil {
+		t.Fatalf("SpotMarketOrder() error = %v", err)
+	}
+	if order := actions[1].Orders[0]; order.Asset != 10107 || order.IsBuy || order.SizePx != "2" || order.LimitPx != "24.875" || order.OrderType.Limit.Tif != TifIoc {
+		t.Errorf("order = %+v, want an IOC sell of 2 on asset 10107 at 24.875", order)
+	}
+	if _, err := exchangeAPI.SpotLimitOrder(TifAlo, "PURR/USDC", -100, 0.2); err != nil {
+		t.Fatalf("SpotLimitOrder() error = %v", err)
+	}
+	if order := actions[2].Orders[0]; order.Asset != 10000 || order.SizePx != "100" {
+		t.Errorf("order = %+v, want a sell of 100 on asset 10000", order)
+	}
+	if _, err := exchangeAPI.SpotLimitOrder(TifGtc, "BTC/USDC", 1, 1); err == nil {
+		t.Errorf("SpotLimitOrder() expected unknown pair error")
+	}
+	if _, err := exchangeAPI.SpotMarketOrder("PURR/USDC", 1, nil); err == nil {
+		t.Errorf("SpotMarketOrder() expected no mid price error")
+	}
+	if len(actions) != 3 {
+		t.Errorf("sent %d orders, want 3", len(actions))
+	}
+}
+
+func TestExchangeAPI_TypedErrors(t *testing.T) {
+	var response any
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any { return response })
+
+	response = map[string]any{"status": "err", "response": "User or API Wallet 0x0000000000000000000000000000000000000001 does not exist."}
+	_, err := exchangeAPI.LimitOrder(TifGtc, "ETH", 0.01, 2000, false)
+	var signatureErr SignatureError
+	var apiErr APIError
+	if !errors.As(err, &signatureErr) || !errors.As(err, &apiErr) {
+		t.Errorf("LimitOrder() error = %v (%T), want SignatureError wrapping APIError", err, err)
+	}
+
+	response = map[string]any{"status": "err", "response": "Too many cumulative requests sent"}
+	_, err = exchangeAPI.LimitOrder(TifGtc, "ETH", 0.01, 2000, false)
+	var rateLimitErr RateLimitError
+	if !errors.As(err, &rateLimitErr) || !errors.As(err, &apiErr) || apiErr.Message != "Too many cumulative requests sent" {
+		t.Errorf("LimitOrder() error = %v (%T), want RateLimitError wrapping APIError", err, err)
+	}
+
+	statuses := []any{
+		map[string]any{"resting": map[string]any{"oid": 10}},
+		map[string]any{"error": "Insufficient margin to place order. asset=1"},
+		map[string]any{"error": "Order has invalid price."},
+	}
+	response = map[string]any{"status": "ok", "response": map[string]any{"type": "order", "data": map[string]any{"statuses": statuses}}}
+	res, err := exchangeAPI.LimitOrder(TifGtc, "ETH", 0.01, 2000, false)
+	if err != nil {
+		t.Fatalf("LimitOrder() error = %v", err)
+	}
+	results := res.Response.Data.Statuses
+	if err := results[0].Err(); err != nil {
+		t.Errorf("Err() = %v, want nil for a resting order", err)
+	}
+	var rejected OrderRejectedError
+	if err := results[1].Err(); !errors.As(err, &rejected) || !errors.As(err, &InsufficientMarginError{}) || rejected.Reason != "Insufficient margin to place order. asset=1" {
+		t.Errorf("Err() = %v, want OrderRejectedError wrapping InsufficientMarginError", err)
+	}
+	if err := results[2].Err(); !errors.As(err, &rejected) || errors.As(err, &InsufficientMarginError{}) || !errors.As(err, &apiErr) || err.Error() != "Order has invalid price." {
+		t.Errorf("Err() = %v, want OrderRejectedError with the reason of the exchange", err)
+	}
+
+	response = map[string]any{"status": "err", "response": "Unknown error"}
+	_, err = exchangeAPI.LimitOrder(TifGtc, "ETH", 0.01, 2000, false)
+	if !errors.As(err, &apiErr) || apiErr.Message != "Unknown error" {
+		t.Errorf("LimitOrder() error = %v (%T), want APIError", err, err)
+	}
+}
@@ -3,12 +3,13 @@ package hyperliquid
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 )
 
 type RsvSignature struct {
-	R string `json:"r"`
-	S string `json:"s"`
-	V byte   `json:"v"`
+	R string `json:"r" msgpack:"r"`
+	S string `json:"s" msgpack:"s"`
+	V byte   `json:"v" msgpack:"v"`
 }
 
 type ExchangeRequest struct {
@@ -19,10 +20,13 @@ type ExchangeRequest struct {
 }
 
 type AssetInfo struct {
-	SzDecimals  int
-	WeiDecimals int
-	AssetID     int
-	SpotName    string // for spot asset (e.g. "@107")
+	SzDecimals   int
+	WeiDecimals  int
+	AssetID      int
+	SpotName     string // for spot asset (e.g. "@107")
+	TokenID      string // for spot asset (e.g. "0xc4bf3f870c0e9465323c0b6ed28096c2")
+	MaxLeverage  int    // for perp asset, 0 if unknown
+	OnlyIsolated bool   // for perp asset that can't be traded with cross margin
 }
 
 type OrderRequest struct {
@@ -34,6 +38,17 @@ type OrderRequest struct {
 	OrderType  OrderType `json:"order_type"`
 	ReduceOnly bool      `json:"reduce_only"`
 	Cloid      string    `json:"cloid,omitempty"`
+	Builder    *Builder  `json:"builder,omitempty"` // optional builder receiving a fee on the order
+	// spotAsset is the asset of the spot pair resolved by SpotLimitOrder and SpotMarketOrder,
+	// it takes precedence over the meta maps
+	spotAsset *AssetInfo
+}
+
+// Builder is a builder receiving a fee on the orders of a PlaceOrderAction.
+// The Fee is in tenths of basis points (e.g. 10 is 1bp) and must be approved with ApproveBuilderFee.
+type Builder struct {
+	Address string `msgpack:"b" json:"b"`
+	Fee     int    `msgpack:"f" json:"f"`
 }
 
 type OrderType struct {
@@ -52,6 +67,10 @@ const (
 	TifFrontendMarket string = "FrontendMarket"
 )
 
+// postOnlyRejection starts the error of an Alo order that would have crossed the book, e.g.
+// "Post only order would have immediately matched, bbo was 1999.9@2000.1".
+const postOnlyRejection = "Post only order would have immediately matched"
+
 type TriggerOrderType struct {
 	IsMarket  bool   `json:"isMarket" msgpack:"isMarket"`
 	TriggerPx string `json:"triggerPx" msgpack:"triggerPx"`
@@ -67,6 +86,82 @@ type Grouping string
 
 const GroupingNa Grouping = "na"
 const GroupingTpSl Grouping = "positionTpsl"
+const GroupingPositionTpSl Grouping = GroupingTpSl
+const GroupingNormalTpSl Grouping = "normalTpsl"
+
+// TpSlOrderRequest is an entry order with its take profit and stop loss trigger orders.
+// With GroupingNormalTpSl the children are attached to the entry order and Entry is required.
+// With GroupingPositionTpSl the children are attached to the position and Entry must be nil.
+type TpSlOrderRequest struct {
+	Entry      *OrderRequest
+	TakeProfit *OrderRequest
+	StopLoss   *OrderRequest
+}
+
+// Requests returns the order requests in the order expected by the grouping: entry, take profit, stop loss.
+func (req *TpSlOrderRequest) Requests() []OrderRequest {
+	var requests []OrderRequest
+	for _, order := range []*OrderRequest{req.Entry, req.TakeProfit, req.StopLoss} {
+		if order != nil {
+			requests = append(requests, *order)
+		}
+	}
+	return requests
+}
+
+// Validate checks that the orders satisfy the rules of the grouping.
+// The children must be reduce only trigger orders on the other side of the entry (or position),
+// and with GroupingNormalTpSl their size must be the size of the entry.
+func (req *TpSlOrderRequest) Validate(grouping Grouping) error {
+	switch grouping {
+	case GroupingNormalTpSl:
+		if req.Entry == nil {
+			return APIError{Message: "normalTpsl grouping requires an entry order"}
+		}
+	case GroupingPositionTpSl:
+		if req.Entry != nil {
+			return APIError{Message: "positionTpsl grouping doesn't accept an entry order"}
+		}
+	default:
+		return APIError{Message: fmt.Sprintf("Invalid TP/SL grouping: %s", grouping)}
+	}
+	if req.TakeProfit == nil && req.StopLoss == nil {
+		return APIError{Message: "Missing take profit or stop loss order"}
+	}
+	var first *OrderRequest
+	for _, child := range []struct {
+		order *OrderRequest
+		tpsl  TpSl
+	}{{req.TakeProfit, TriggerTp}, {req.StopLoss, TriggerSl}} {
+		if child.order == nil {
+			continue
+		}
+		order := child.order
+		if order.OrderType.Trigger == nil || order.OrderType.Trigger.TpSl != child.tpsl {
+			return APIError{Message: fmt.Sprintf("The %s order must be a %s trigger order", child.tpsl, child.tpsl)}
+		}
+		if !order.ReduceOnly {
+			return APIError{Message: fmt.Sprintf("The %s order must be reduce only", child.tpsl)}
+		}
+		if first == nil {
+			first = order
+		}
+		if order.Coin != first.Coin || order.IsBuy != first.IsBuy {
+			return APIError{Message: "The take profit and stop loss orders must be on the same coin and side"}
+		}
+		if req.Entry != nil {
+			if order.Coin != req.Entry.Coin || order.IsBuy == req.Entry.IsBuy {
+				return APIError{Message: fmt.Sprintf("The %s order must be on the other side of the entry order", child.tpsl)}
+			}
+			if order.Sz != req.Entry.Sz {
+				return APIError{Message: fmt.Sprintf("The %s order size %v must be the entry size %v", child.tpsl, order.Sz, req.Entry.Sz)}
+			}
+		} else if order.Sz != first.Sz {
+			return APIError{Message: "The take profit and stop loss orders must have the same size"}
+		}
+	}
+	return nil
+}
 
 type Message struct {
 	Source       string `json:"source"`
@@ -99,11 +194,36 @@ type ModifyOrderByCloidAction struct {
 	Type     string                   `msgpack:"type" json:"type"`
 	Modifies []ModifyOrderByCloidWire `msgpack:"modifies" json:"modifies"`
 }
+
+// ModifyOrderRequest modifies a resting order.
+// The order is identified by its Oid or, if Oid is nil, by its Cloid.
+// Order holds the new parameters of the order.
+type ModifyOrderRequest struct {
+	Oid   *int
+	Cloid string
+	Order OrderRequest
+}
+
+// ModifyAction modifies a single order.
+// Oid is either the order id (int) or the client order id (hex string).
+type ModifyAction struct {
+	Type  string    `msgpack:"type" json:"type"`
+	Oid   any       `msgpack:"oid" json:"oid"`
+	Order OrderWire `msgpack:"order" json:"order"`
+}
+
 type ModifyOrderAction struct {
 	Type     string            `msgpack:"type" json:"type"`
 	Modifies []ModifyOrderWire `msgpack:"modifies" json:"modifies"`
 }
 
+// BatchModifyAction modifies several orders atomically, each identified by its order id or client order id.
+// Each modify is a ModifyOrderWire or a ModifyOrderByCloidWire.
+type BatchModifyAction struct {
+	Type     string `msgpack:"type" json:"type"`
+	Modifies []any  `msgpack:"modifies" json:"modifies"`
+}
+
 type OrderTypeWire struct {
 	Limit   *LimitOrderType   `json:"limit,omitempty" msgpack:"limit,omitempty"`
 	Trigger *TriggerOrderType `json:"trigger,omitempty" msgpack:"trigger,omitempty"`
@@ -113,6 +233,7 @@ type PlaceOrderAction struct {
 	Type     string      `msgpack:"type" json:"type"`
 	Orders   []OrderWire `msgpack:"orders" json:"orders"`
 	Grouping Grouping    `msgpack:"grouping" json:"grouping"`
+	Builder  *Builder    `msgpack:"builder,omitempty" json:"builder,omitempty"`
 }
 
 type OrderResponse struct {
@@ -168,6 +289,89 @@ func (sr *StatusResponse) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// OrderID returns the id of the order of the status, 0 if the order has no id yet (e.g. "waitingForFill").
+// Err returns an OrderRejectedError if the order was rejected, nil otherwise.
+func (sr *StatusResponse) Err() error {
+	if sr.Error == "" {
+		return nil
+	}
+	return newOrderRejectedError(sr.Error)
+}
+
+func (sr *StatusResponse) OrderID() int {
+	if sr.Resting.OrderID != 0 {
+		return sr.Resting.OrderID
+	}
+	return sr.Filled.OrderID
+}
+
+// BracketOrderResult holds the order ids of a bracket order.
+// The take profit and stop loss ids are 0 while they wait for the entry order to be filled.
+type BracketOrderResult struct {
+	EntryOid      int
+	TakeProfitOid int
+	StopLossOid   int
+	Response      *OrderResponse
+}
+
+// KillSwitchReport is the outcome of each step of KillSwitch.
+// A step that failed has its error set, the other steps are still run.
+type KillSwitchReport struct {
+	CanceledOrders     int               // Number of open orders canceled
+	CancelErr          error             // Error of the cancel of the open orders
+	ClosedPositions    []string          // Coins of the positions closed
+	CloseErrors        map[string]string // Coin of a position that could not be closed -> error
+	CloseErr           error             // Error of the close orders request
+	ScheduleCancelTime int64             // Time in milliseconds of the armed scheduleCancel
+	ScheduleCancelErr  error             // Error of the scheduleCancel
+}
+
+// Err returns the first error of the report, nil if every step succeeded.
+func (r *KillSwitchReport) Err() error {
+	if r.CancelErr != nil {
+		return r.CancelErr
+	}
+	if r.CloseErr != nil {
+		return r.CloseErr
+	}
+	coins := make([]string, 0, len(r.CloseErrors))
+	for coin := range r.CloseErrors {
+		coins = append(coins, coin)
+	}
+	if len(coins) > 0 {
+		sort.Strings(coins)
+		return APIError{Message: fmt.Sprintf("Failed to close %s position: %s", coins[0], r.CloseErrors[coins[0]])}
+	}
+	return r.ScheduleCancelErr
+}
+
+// MarginCheck is the result of CanPlaceOrder.
+// RequiredMargin is the initial margin the order would use at Leverage and
+// AvailableMargin the margin of the account that is not used yet.
+type MarginCheck struct {
+	Allowed         bool
+	RequiredMargin  float64
+	AvailableMargin float64
+	Leverage        int
+}
+
+// ReplacePath is the way an order was replaced by ReplaceOrder.
+type ReplacePath string
+
+const (
+	ReplacePathModify        ReplacePath = "modify"        // The order was modified in place
+	ReplacePathCancelReplace ReplacePath = "cancelReplace" // The order was canceled and placed again
+)
+
+// ReplaceOrderResult holds the identity of the order after ReplaceOrder.
+// Oid is 0 if the new order was rejected, see Response for the error.
+type ReplaceOrderResult struct {
+	Path     ReplacePath
+	Oid      int
+	Cloid    string
+	Response *OrderResponse
+}
+
 type CancelRequest struct {
 	OrderID int `json:"oid"`
 	Coin    int `json:"coin"`
@@ -225,6 +429,247 @@ type UpdateLeverageAction struct {
 	Leverage int    `msgpack:"leverage" json:"leverage"`
 }
 
+type UpdateIsolatedMarginAction struct {
+	Type  string `msgpack:"type" json:"type"`
+	Asset int    `msgpack:"asset" json:"asset"`
+	IsBuy bool   `msgpack:"isBuy" json:"isBuy"`
+	Ntli  int64  `msgpack:"ntli" json:"ntli"` // Amount in micro USD
+}
+
+type TopUpIsolatedOnlyMarginAction struct {
+	Type     string `msgpack:"type" json:"type"`
+	Asset    int    `msgpack:"asset" json:"asset"`
+	Leverage string `msgpack:"leverage" json:"leverage"`
+}
+
+type SubAccountTransferAction struct {
+	Type           string `msgpack:"type" json:"type"`
+	SubAccountUser string `msgpack:"subAccountUser" json:"subAccountUser"`
+	IsDeposit      bool   `msgpack:"isDeposit" json:"isDeposit"`
+	Usd            int64  `msgpack:"usd" json:"usd"` // Amount in micro USD
+}
+
+type SubAccountSpotTransferAction struct {
+	Type           string `msgpack:"type" json:"type"`
+	SubAccountUser string `msgpack:"subAccountUser" json:"subAccountUser"`
+	IsDeposit      bool   `msgpack:"isDeposit" json:"isDeposit"`
+	Token          string `msgpack:"token" json:"token"` // name:tokenId (e.g. "PURR:0xc4bf3f870c0e9465323c0b6ed28096c2")
+	Amount         string `msgpack:"amount" json:"amount"`
+}
+
+type CreateSubAccountAction struct {
+	Type string `msgpack:"type" json:"type"`
+	Name string `msgpack:"name" json:"name"`
+}
+
+// CreateSubAccountResponse is the response of a sub-account creation.
+// Response.Data is the address of the created sub-account.
+type CreateSubAccountResponse struct {
+	Status   string `json:"status"`
+	Response struct {
+		Type string `json:"type"`
+		Data string `json:"data"`
+	} `json:"response"`
+}
+
+type VaultTransferAction struct {
+	Type         string `msgpack:"type" json:"type"`
+	VaultAddress string `msgpack:"vaultAddress" json:"vaultAddress"`
+	IsDeposit    bool   `msgpack:"isDeposit" json:"isDeposit"`
+	Usd          int64  `msgpack:"usd" json:"usd"` // Amount in micro USD
+}
+
+type CreateVaultAction struct {
+	Type        string `msgpack:"type" json:"type"`
+	Name        string `msgpack:"name" json:"name"`
+	Description string `msgpack:"description" json:"description"`
+	InitialUsd  int64  `msgpack:"initialUsd" json:"initialUsd"` // Amount in micro USD
+	Nonce       uint64 `msgpack:"nonce" json:"nonce"`
+}
+
+// CreateVaultResponse is the response of a vault creation.
+// Response.Data is the address of the created vault.
+type CreateVaultResponse struct {
+	Status   string `json:"status"`
+	Response struct {
+		Type string `json:"type"`
+		Data string `json:"data"`
+	} `json:"response"`
+}
+
+type VaultModifyAction struct {
+	Type                  string `msgpack:"type" json:"type"`
+	VaultAddress          string `msgpack:"vaultAddress" json:"vaultAddress"`
+	AllowDeposits         *bool  `msgpack:"allowDeposits" json:"allowDeposits"`
+	AlwaysCloseOnWithdraw *bool  `msgpack:"alwaysCloseOnWithdraw" json:"alwaysCloseOnWithdraw"`
+}
+
+type VaultDistributeAction struct {
+	Type         string `msgpack:"type" json:"type"`
+	VaultAddress string `msgpack:"vaultAddress" json:"vaultAddress"`
+	Usd          int64  `msgpack:"usd" json:"usd"` // Amount in micro USD
+}
+
+type SetReferrerAction struct {
+	Type string `msgpack:"type" json:"type"`
+	Code string `msgpack:"code" json:"code"`
+}
+
+type RegisterReferrerAction struct {
+	Type string `msgpack:"type" json:"type"`
+	Code string `msgpack:"code" json:"code"`
+}
+
+type ReserveRequestWeightAction struct {
+	Type   string `msgpack:"type" json:"type"`
+	Weight int    `msgpack:"weight" json:"weight"`
+}
+
+type NoopAction struct {
+	Type string `msgpack:"type" json:"type"`
+}
+
+// SpotDeployAction is a spot deploy action, exactly one of the variants is set.
+type SpotDeployAction struct {
+	Type                       string                                `msgpack:"type" json:"type"`
+	RegisterToken2             *SpotDeployRegisterToken2             `msgpack:"registerToken2,omitempty" json:"registerToken2,omitempty"`
+	UserGenesis                *SpotDeployUserGenesis                `msgpack:"userGenesis,omitempty" json:"userGenesis,omitempty"`
+	Genesis                    *SpotDeployGenesis                    `msgpack:"genesis,omitempty" json:"genesis,omitempty"`
+	RegisterSpot               *SpotDeployRegisterSpot               `msgpack:"registerSpot,omitempty" json:"registerSpot,omitempty"`
+	RegisterHyperliquidity     *SpotDeployRegisterHyperliquidity     `msgpack:"registerHyperliquidity,omitempty" json:"registerHyperliquidity,omitempty"`
+	SetDeployerTradingFeeShare *SpotDeploySetDeployerTradingFeeShare `msgpack:"setDeployerTradingFeeShare,omitempty" json:"setDeployerTradingFeeShare,omitempty"`
+}
+
+type SpotDeployTokenSpec struct {
+	Name        string `msgpack:"name" json:"name"`
+	SzDecimals  int    `msgpack:"szDecimals" json:"szDecimals"`
+	WeiDecimals int    `msgpack:"weiDecimals" json:"weiDecimals"`
+}
+
+type SpotDeployRegisterToken2 struct {
+	Spec     SpotDeployTokenSpec `msgpack:"spec" json:"spec"`
+	MaxGas   int                 `msgpack:"maxGas" json:"maxGas"`
+	FullName string              `msgpack:"fullName,omitempty" json:"fullName,omitempty"`
+}
+
+type SpotDeployUserGenesis struct {
+	Token               int     `msgpack:"token" json:"token"`
+	UserAndWei          [][]any `msgpack:"userAndWei" json:"userAndWei"`                   // [user, wei] pairs
+	ExistingTokenAndWei [][]any `msgpack:"existingTokenAndWei" json:"existingTokenAndWei"` // [token, wei] pairs
+}
+
+// UserGenesisBalance is the genesis balance of a user in wei.
+type UserGenesisBalance struct {
+	User string
+	Wei  string
+}
+
+// TokenGenesisBalance is the genesis balance in wei shared by the holders of an existing token.
+type TokenGenesisBalance struct {
+	Token int
+	Wei   string
+}
+
+type SpotDeployGenesis struct {
+	Token            int    `msgpack:"token" json:"token"`
+	MaxSupply        string `msgpack:"maxSupply" json:"maxSupply"`
+	NoHyperliquidity bool   `msgpack:"noHyperliquidity,omitempty" json:"noHyperliquidity,omitempty"`
+}
+
+type SpotDeployRegisterSpot struct {
+	Tokens [2]int `msgpack:"tokens" json:"tokens"` // [base, quote]
+}
+
+type SpotDeployRegisterHyperliquidity struct {
+	Spot          int    `msgpack:"spot" json:"spot"`
+	StartPx       string `msgpack:"startPx" json:"startPx"`
+	OrderSz       string `msgpack:"orderSz" json:"orderSz"`
+	NOrders       int    `msgpack:"nOrders" json:"nOrders"`
+	NSeededLevels *int   `msgpack:"nSeededLevels,omitempty" json:"nSeededLevels,omitempty"`
+}
+
+type SpotDeploySetDeployerTradingFeeShare struct {
+	Token int    `msgpack:"token" json:"token"`
+	Share string `msgpack:"share" json:"share"` // percent string (e.g. "50%")
+}
+
+// PerpDeployAction is a perp deploy action, exactly one of the variants is set.
+type PerpDeployAction struct {
+	Type          string                   `msgpack:"type" json:"type"`
+	RegisterAsset *PerpDeployRegisterAsset `msgpack:"registerAsset,omitempty" json:"registerAsset,omitempty"`
+	SetOracle     *PerpDeploySetOracle     `msgpack:"setOracle,omitempty" json:"setOracle,omitempty"`
+}
+
+type PerpDeployAssetRequest struct {
+	Coin          string `msgpack:"coin" json:"coin"`
+	SzDecimals    int    `msgpack:"szDecimals" json:"szDecimals"`
+	OraclePx      string `msgpack:"oraclePx" json:"oraclePx"`
+	MarginTableID int    `msgpack:"marginTableId" json:"marginTableId"`
+	OnlyIsolated  bool   `msgpack:"onlyIsolated" json:"onlyIsolated"`
+}
+
+// PerpDexSchema describes a new builder-deployed perp dex.
+// OracleUpdater is the address allowed to set the oracle prices, the deployer if nil.
+type PerpDexSchema struct {
+	FullName        string  `msgpack:"fullName" json:"fullName"`
+	CollateralToken int     `msgpack:"collateralToken" json:"collateralToken"`
+	OracleUpdater   *string `msgpack:"oracleUpdater" json:"oracleUpdater"`
+}
+
+type PerpDeployRegisterAsset struct {
+	MaxGas       *int                   `msgpack:"maxGas" json:"maxGas"`
+	AssetRequest PerpDeployAssetRequest `msgpack:"assetRequest" json:"assetRequest"`
+	Dex          string                 `msgpack:"dex" json:"dex"`
+	Schema       *PerpDexSchema         `msgpack:"schema" json:"schema"`
+}
+
+type PerpDeploySetOracle struct {
+	Dex             string        `msgpack:"dex" json:"dex"`
+	OraclePxs       [][2]string   `msgpack:"oraclePxs" json:"oraclePxs"` // [coin, px] pairs sorted by coin
+	MarkPxs         [][][2]string `msgpack:"markPxs" json:"markPxs"`
+	ExternalPerpPxs [][2]string   `msgpack:"externalPerpPxs" json:"externalPerpPxs"`
+}
+
+type EvmUserModifyAction struct {
+	Type           string `msgpack:"type" json:"type"`
+	UsingBigBlocks bool   `msgpack:"usingBigBlocks" json:"usingBigBlocks"`
+}
+
+type ClaimRewardsAction struct {
+	Type string `msgpack:"type" json:"type"`
+}
+
+type CSignerJailSelfAction struct {
+	Type     string    `msgpack:"type" json:"type"`
+	JailSelf *struct{} `msgpack:"jailSelf" json:"jailSelf"` // always null
+}
+
+type CSignerUnjailSelfAction struct {
+	Type       string    `msgpack:"type" json:"type"`
+	UnjailSelf *struct{} `msgpack:"unjailSelf" json:"unjailSelf"` // always null
+}
+
+// ValidatorNodeIP is the ip address of a validator node.
+type ValidatorNodeIP struct {
+	Ip string `msgpack:"Ip" json:"Ip"`
+}
+
+// ValidatorProfileChange is a change of a validator profile, nil fields are left unchanged.
+type ValidatorProfileChange struct {
+	NodeIP             *ValidatorNodeIP `msgpack:"node_ip" json:"node_ip"`
+	Name               *string          `msgpack:"name" json:"name"`
+	Description        *string          `msgpack:"description" json:"description"`
+	Unjailed           bool             `msgpack:"unjailed" json:"unjailed"`
+	DisableDelegations *bool            `msgpack:"disable_delegations" json:"disable_delegations"`
+	CommissionBps      *int             `msgpack:"commission_bps" json:"commission_bps"`
+	Signer             *string          `msgpack:"signer" json:"signer"`
+}
+
+type CValidatorChangeProfileAction struct {
+	Type          string                 `msgpack:"type" json:"type"`
+	ChangeProfile ValidatorProfileChange `msgpack:"changeProfile" json:"changeProfile"`
+}
+
 type DefaultExchangeResponse struct {
 	Status   string `json:"status"`
 	Response struct {
@@ -272,7 +717,196 @@ type WithdrawAction struct {
 	SignatureChainID string `json:"signatureChainId" msgpack:"signatureChainId"`
 }
 
+// WithdrawResponse is the response of a withdraw request.
+// Nonce is the nonce of the request, the withdrawal is reported with it in the ledger updates.
+// Fee is the USDC fee deducted from the withdrawn amount.
 type WithdrawResponse struct {
-	Status string `json:"status"`
-	Nonce  int64  `json:"nonce"`
+	Status   string  `json:"status"`
+	Nonce    int64   `json:"nonce"`
+	Fee      float64 `json:"fee"`
+	Response struct {
+		Type string `json:"type"`
+	} `json:"response"`
+}
+
+type TwapOrderWire struct {
+	Asset      int    `msgpack:"a" json:"a"`
+	IsBuy      bool   `msgpack:"b" json:"b"`
+	Sz         string `msgpack:"s" json:"s"`
+	ReduceOnly bool   `msgpack:"r" json:"r"`
+	Minutes    int    `msgpack:"m" json:"m"`
+	Randomize  bool   `msgpack:"t" json:"t"`
+}
+
+type TwapOrderAction struct {
+	Type string        `msgpack:"type" json:"type"`
+	Twap TwapOrderWire `msgpack:"twap" json:"twap"`
+}
+
+// TwapStatus is the status of a placed TWAP order.
+// Running is set if the TWAP was accepted, Error otherwise.
+type TwapStatus struct {
+	Running *struct {
+		TwapID int64 `json:"twapId"`
+	} `json:"running,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type TwapOrderResponse struct {
+	Status   string `json:"status"`
+	Response struct {
+		Type string `json:"type"`
+		Data struct {
+			Status TwapStatus `json:"status"`
+		} `json:"data"`
+	} `json:"response"`
+}
+
+// TwapID returns the id of the placed TWAP order.
+// Returns an error if the TWAP was rejected.
+func (res *TwapOrderResponse) TwapID() (int64, error) {
+	status := res.Response.Data.Status
+	if status.Running == nil {
+		return 0, newOrderRejectedError(status.Error)
+	}
+	return status.Running.TwapID, nil
+}
+
+type TwapCancelAction struct {
+	Type   string `msgpack:"type" json:"type"`
+	Asset  int    `msgpack:"a" json:"a"`
+	TwapID int    `msgpack:"t" json:"t"`
+}
+
+// TwapCancelResponse is the response of a TWAP cancel.
+// Data.Status.Status is "success" if the TWAP was canceled, Data.Status.Error is set otherwise.
+type TwapCancelResponse struct {
+	Status   string `json:"status"`
+	Response struct {
+		Type string `json:"type"`
+		Data struct {
+			Status StatusResponse `json:"status"`
+		} `json:"data"`
+	} `json:"response"`
+}
+
+type ScheduleCancelAction struct {
+	Type string `msgpack:"type" json:"type"`
+	Time *int64 `msgpack:"time,omitempty" json:"time,omitempty"`
+}
+
+type UsdSendAction struct {
+	Type             string `json:"type" msgpack:"type"`
+	SignatureChainID string `json:"signatureChainId" msgpack:"signatureChainId"`
+	HyperliquidChain string `json:"hyperliquidChain" msgpack:"hyperliquidChain"`
+	Destination      string `json:"destination" msgpack:"destination"`
+	Amount           string `json:"amount" msgpack:"amount"`
+	Time             uint64 `json:"time" msgpack:"time"`
+}
+
+type SpotSendAction struct {
+	Type             string `json:"type" msgpack:"type"`
+	SignatureChainID string `json:"signatureChainId" msgpack:"signatureChainId"`
+	HyperliquidChain string `json:"hyperliquidChain" msgpack:"hyperliquidChain"`
+	Destination      string `json:"destination" msgpack:"destination"`
+	Token            string `json:"token" msgpack:"token"` // name:tokenId (e.g. "PURR:0xc4bf3f870c0e9465323c0b6ed28096c2")
+	Amount           string `json:"amount" msgpack:"amount"`
+	Time             uint64 `json:"time" msgpack:"time"`
+}
+
+type UsdClassTransferAction struct {
+	Type             string `json:"type" msgpack:"type"`
+	SignatureChainID string `json:"signatureChainId" msgpack:"signatureChainId"`
+	HyperliquidChain string `json:"hyperliquidChain" msgpack:"hyperliquidChain"`
+	Amount           string `json:"amount" msgpack:"amount"`
+	ToPerp           bool   `json:"toPerp" msgpack:"toPerp"`
+	Nonce            uint64 `json:"nonce" msgpack:"nonce"`
+}
+
+type PerpDexClassTransferAction struct {
+	Type             string `json:"type" msgpack:"type"`
+	SignatureChainID string `json:"signatureChainId" msgpack:"signatureChainId"`
+	HyperliquidChain string `json:"hyperliquidChain" msgpack:"hyperliquidChain"`
+	Dex              string `json:"dex" msgpack:"dex"`
+	Token            string `json:"token" msgpack:"token"`
+	Amount           string `json:"amount" msgpack:"amount"`
+	ToPerp           bool   `json:"toPerp" msgpack:"toPerp"`
+	Nonce            uint64 `json:"nonce" msgpack:"nonce"`
+}
+
+type ApproveAgentAction struct {
+	Type             string `json:"type" msgpack:"type"`
+	SignatureChainID string `json:"signatureChainId" msgpack:"signatureChainId"`
+	HyperliquidChain string `json:"hyperliquidChain" msgpack:"hyperliquidChain"`
+	AgentAddress     string `json:"agentAddress" msgpack:"agentAddress"`
+	AgentName        string `json:"agentName,omitempty" msgpack:"agentName,omitempty"` // omitted for an unnamed agent
+	Nonce            uint64 `json:"nonce" msgpack:"nonce"`
+}
+
+type ApproveBuilderFeeAction struct {
+	Type             string `json:"type" msgpack:"type"`
+	SignatureChainID string `json:"signatureChainId" msgpack:"signatureChainId"`
+	HyperliquidChain string `json:"hyperliquidChain" msgpack:"hyperliquidChain"`
+	MaxFeeRate       string `json:"maxFeeRate" msgpack:"maxFeeRate"` // percent string (e.g. "0.01%")
+	Builder          string `json:"builder" msgpack:"builder"`
+	Nonce            uint64 `json:"nonce" msgpack:"nonce"`
+}
+
+type TokenDelegateAction struct {
+	Type             string `json:"type" msgpack:"type"`
+	SignatureChainID string `json:"signatureChainId" msgpack:"signatureChainId"`
+	HyperliquidChain string `json:"hyperliquidChain" msgpack:"hyperliquidChain"`
+	Validator        string `json:"validator" msgpack:"validator"`
+	Wei              uint64 `json:"wei" msgpack:"wei"`
+	IsUndelegate     bool   `json:"isUndelegate" msgpack:"isUndelegate"`
+	Nonce            uint64 `json:"nonce" msgpack:"nonce"`
+}
+
+// StakingTransferAction is a cDeposit or cWithdraw action.
+type StakingTransferAction struct {
+	Type             string `json:"type" msgpack:"type"`
+	SignatureChainID string `json:"signatureChainId" msgpack:"signatureChainId"`
+	HyperliquidChain string `json:"hyperliquidChain" msgpack:"hyperliquidChain"`
+	Wei              uint64 `json:"wei" msgpack:"wei"`
+	Nonce            uint64 `json:"nonce" msgpack:"nonce"`
+}
+
+type ConvertToMultiSigUserAction struct {
+	Type             string `json:"type" msgpack:"type"`
+	SignatureChainID string `json:"signatureChainId" msgpack:"signatureChainId"`
+	HyperliquidChain string `json:"hyperliquidChain" msgpack:"hyperliquidChain"`
+	Signers          string `json:"signers" msgpack:"signers"` // json encoded MultiSigSigners
+	Nonce            uint64 `json:"nonce" msgpack:"nonce"`
+}
+
+// MultiSigPayload is the action of a multi-sig user submitted by one of its signers (the outer signer).
+type MultiSigPayload struct {
+	MultiSigUser string `json:"multiSigUser" msgpack:"multiSigUser"`
+	OuterSigner  string `json:"outerSigner" msgpack:"outerSigner"`
+	Action       any    `json:"action" msgpack:"action"`
+}
+
+type MultiSigAction struct {
+	Type             string          `json:"type" msgpack:"type,omitempty"` // not part of the multi-sig action hash
+	SignatureChainID string          `json:"signatureChainId" msgpack:"signatureChainId"`
+	Signatures       []RsvSignature  `json:"signatures" msgpack:"signatures"`
+	Payload          MultiSigPayload `json:"payload" msgpack:"payload"`
+}
+
+// SendMultiSigEnvelope is the user signed message of the outer signer of a multi-sig action.
+type SendMultiSigEnvelope struct {
+	SignatureChainID   string `json:"signatureChainId"`
+	HyperliquidChain   string `json:"hyperliquidChain"`
+	MultiSigActionHash string `json:"multiSigActionHash"`
+	Nonce              uint64 `json:"nonce"`
+}
+
+// MultiSigResponse is the response of a multi-sig action.
+// Response.Data is the response data of the inner action (e.g. the order statuses of an order action).
+type MultiSigResponse struct {
+	Status   string `json:"status"`
+	Response struct {
+		Type string          `json:"type"`
+		Data json.RawMessage `json:"data,omitempty"`
+	} `json:"response"`
 }
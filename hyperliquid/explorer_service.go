@@ -0,0 +1,90 @@
+package hyperliquid
+
+// IExplorerAPI is an interface for the explorer service.
+type IExplorerAPI interface {
+	IClient
+
+	BlockDetails(height int64) (*BlockDetails, error)
+	TxDetails(hash string) (*ExplorerTx, error)
+	UserDetails(address string) (*[]ExplorerTx, error)
+}
+
+// ExplorerAPI queries the L1 explorer.
+// It resolves the transaction hashes returned by fills and ledger updates.
+type ExplorerAPI struct {
+	Client
+	baseEndpoint string
+}
+
+// getExplorerURL returns the explorer URL based on the network type.
+func getExplorerURL(isMainnet bool) string {
+	if isMainnet {
+		return MAINNET_EXPLORER_URL
+	} else {
+		return TESTNET_EXPLORER_URL
+	}
+}
+
+// NewExplorerAPI returns a new instance of the ExplorerAPI struct.
+// It sets the base endpoint to "/explorer" of the RPC node.
+// The isMainnet parameter is used to set the network type.
+func NewExplorerAPI(isMainnet bool) *ExplorerAPI {
+	api := ExplorerAPI{
+		baseEndpoint: "/explorer",
+		Client:       *NewClient(isMainnet),
+	}
+	api.baseURL = getExplorerURL(isMainnet)
+	return &api
+}
+
+// Endpoint returns the base endpoint for the ExplorerAPI.
+func (api *ExplorerAPI) Endpoint() string {
+	return api.baseEndpoint
+}
+
+// Retrieve the details of a block and its transactions
+func (api *ExplorerAPI) BlockDetails(height int64) (*BlockDetails, error) {
+	request := ExplorerRequest{
+		Type:   "blockDetails",
+		Height: height,
+	}
+	response, err := MakeUniversalRequest[BlockDetailsResponse](api, request)
+	if err != nil {
+		return nil, err
+	}
+	return &response.BlockDetails, nil
+}
+
+// Retrieve the details of a transaction by its hash
+// The hash is the one returned in fills and ledger updates
+func (api *ExplorerAPI) TxDetails(hash string) (*ExplorerTx, error) {
+	request := ExplorerRequest{
+		Type: "txDetails",
+		Hash: hash,
+	}
+	response, err := MakeUniversalRequest[TxDetailsResponse](api, request)
+	if err != nil {
+		return nil, err
+	}
+	return &response.Tx, nil
+}
+
+// Retrieve the latest transactions of a user
+func (api *ExplorerAPI) UserDetails(address string) (*[]ExplorerTx, error) {
+	request := ExplorerRequest{
+		Type: "userDetails",
+		User: address,
+	}
+	response, err := MakeUniversalRequest[UserDetailsResponse](api, request)
+	if err != nil {
+		return nil, err
+	}
+	return &response.Txs, nil
+}
+
+// Retrieve the latest transactions of the account
+// The same as UserDetails but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *ExplorerAPI) AccountDetails() (*[]ExplorerTx, error) {
+	return api.UserDetails(api.AccountAddress())
+}
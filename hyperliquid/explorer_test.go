@@ -0,0 +1,85 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func GetTestExplorerAPI(t *testing.T, handle func(req ExplorerRequest) any) *ExplorerAPI {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/explorer" {
+			t.Errorf("request path = %v, want /explorer", r.URL.Path)
+		}
+		var req ExplorerRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Decode() error = %v", err)
+		}
+		json.NewEncoder(w).Encode(handle(req))
+	}))
+	t.Cleanup(server.Close)
+	api := NewExplorerAPI(false)
+	api.baseURL = server.URL
+	if GLOBAL_DEBUG {
+		api.SetDebugActive()
+	}
+	return api
+}
+
+func TestExplorerAPI_BlockDetails(t *testing.T) {
+	api := GetTestExplorerAPI(t, func(req ExplorerRequest) any {
+		if req.Type != "blockDetails" || req.Height != 100 {
+			t.Errorf("unexpected request %+v", req)
+		}
+		return map[string]any{
+			"type": "blockDetails",
+			"blockDetails": map[string]any{
+				"height": 100, "blockTime": 1700000000000, "hash": "0xabc", "proposer": "0x1", "numTxs": 1,
+				"txs": []any{map[string]any{"time": 1700000000000, "user": "0x2", "action": map[string]any{"type": "cancel"}, "block": 100, "hash": "0xdef", "error": nil}},
+			},
+		}
+	})
+	res, err := api.BlockDetails(100)
+	if err != nil {
+		t.Fatalf("BlockDetails() error = %v", err)
+	}
+	if res.Height != 100 || len(res.Txs) != 1 || res.Txs[0].ActionType() != "cancel" || res.Txs[0].Error != nil {
+		t.Errorf("BlockDetails() = %+v", res)
+	}
+}
+
+func TestExplorerAPI_TxDetails(t *testing.T) {
+	api := GetTestExplorerAPI(t, func(req ExplorerRequest) any {
+		if req.Type != "txDetails" || req.Hash != "0xdef" {
+			t.Errorf("unexpected request %+v", req)
+		}
+		return map[string]any{
+			"type": "txDetails",
+			"tx":   map[string]any{"time": 1700000000000, "user": "0x2", "action": map[string]any{"type": "order"}, "block": 100, "hash": "0xdef", "error": "Insufficient margin"},
+		}
+	})
+	res, err := api.TxDetails("0xdef")
+	if err != nil {
+		t.Fatalf("TxDetails() error = %v", err)
+	}
+	if res.Hash != "0xdef" || res.ActionType() != "order" || res.Error == nil || *res.Error != "Insufficient margin" {
+		t.Errorf("TxDetails() = %+v", res)
+	}
+}
+
+func TestExplorerAPI_UserDetails(t *testing.T) {
+	api := GetTestExplorerAPI(t, func(req ExplorerRequest) any {
+		if req.Type != "userDetails" || req.User != "0x2" {
+			t.Errorf("unexpected request %+v", req)
+		}
+		return map[string]any{"type": "userDetails", "txs": []any{}}
+	})
+	res, err := api.UserDetails("0x2")
+	if err != nil {
+		t.Fatalf("UserDetails() error = %v", err)
+	}
+	if len(*res) != 0 {
+		t.Errorf("UserDetails() = %+v, want no txs", res)
+	}
+}
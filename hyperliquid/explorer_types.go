@@ -0,0 +1,56 @@
+package hyperliquid
+
+import "encoding/json"
+
+// ExplorerRequest is the request of the explorer endpoint.
+// Only the field required by the request type must be set.
+type ExplorerRequest struct {
+	Type   string `json:"type"`
+	Height int64  `json:"height,omitempty"`
+	Hash   string `json:"hash,omitempty"`
+	User   string `json:"user,omitempty"`
+}
+
+// ExplorerTx is an L1 transaction.
+// Action is the raw signed action, its shape depends on the action type.
+type ExplorerTx struct {
+	Time   int64           `json:"time"`
+	User   string          `json:"user"`
+	Action json.RawMessage `json:"action"`
+	Block  int64           `json:"block"`
+	Hash   string          `json:"hash"`
+	Error  *string         `json:"error"` // Set if the action failed
+}
+
+// ActionType returns the type of the transaction action (e.g. "order", "cancel", "usdSend").
+func (tx *ExplorerTx) ActionType() string {
+	var action struct {
+		Type string `json:"type"`
+	}
+	json.Unmarshal(tx.Action, &action)
+	return action.Type
+}
+
+type BlockDetails struct {
+	Height    int64        `json:"height"`
+	BlockTime int64        `json:"blockTime"`
+	Hash      string       `json:"hash"`
+	Proposer  string       `json:"proposer"`
+	NumTxs    int          `json:"numTxs"`
+	Txs       []ExplorerTx `json:"txs"`
+}
+
+type BlockDetailsResponse struct {
+	Type         string       `json:"type"`
+	BlockDetails BlockDetails `json:"blockDetails"`
+}
+
+type TxDetailsResponse struct {
+	Type string     `json:"type"`
+	Tx   ExplorerTx `json:"tx"`
+}
+
+type UserDetailsResponse struct {
+	Type string       `json:"type"`
+	Txs  []ExplorerTx `json:"txs"`
+}
@@ -0,0 +1,176 @@
+package hyperliquid
+
+import (
+	"maps"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// FillStats are the statistics of the fills of a coin aggregated by a FillAggregator.
+type FillStats struct {
+	Coin        string
+	Fills       int
+	Volume      float64            // Traded size
+	Notional    float64            // Traded notional in quote currency
+	Position    float64            // Signed size after the last fill
+	AvgEntryPx  float64            // Average entry price of Position, 0 if flat
+	RealizedPnl float64            // Closed PnL reported by the exchange, without fees
+	Fees        map[string]float64 // Fees paid per fee token, negative for rebates. Spot buys pay the fee in the base token
+	LastTime    int64
+}
+
+// clone returns a copy of the stats that does not share the fees.
+func (stats *FillStats) clone() FillStats {
+	clone := *stats
+	clone.Fees = maps.Clone(stats.Fees)
+	return clone
+}
+
+// FillAggregator aggregates fills per coin.
+// Fills can be added from the REST endpoints (GetUserFills, GetUserFillsByTime, ...) and from
+// the websocket (userFills, AccountEvents), fills already added are ignored using their trade id.
+// The average entry price follows the position of the fills: it is the weighted average of
+// the increasing fills, unchanged by reducing fills and reset when the position flips.
+// The position is taken from the start position of the fills when reported by the exchange.
+//
+//	aggregator := hyperliquid.NewFillAggregator()
+//	fills, _ := info.GetUserFills(address)
+//	aggregator.AddFills(*fills)
+//	stats, ok := aggregator.Stats("ETH")
+type FillAggregator struct {
+	stats map[string]*FillStats
+	seen  map[fillKey]struct{}
+	mu    sync.RWMutex
+}
+
+// fillKey identifies a fill, a trade has a fill per side so the trade id is not unique per user
+// when both sides belong to the same user.
+type fillKey struct {
+	tid  int64
+	oid  int
+	coin string
+}
+
+// NewFillAggregator creates an empty FillAggregator.
+func NewFillAggregator() *FillAggregator {
+	return &FillAggregator{
+		stats: make(map[string]*FillStats),
+		seen:  make(map[fillKey]struct{}),
+	}
+}
+
+// Add adds a fill, it returns false if the fill was already added.
+func (aggregator *FillAggregator) Add(fill OrderFill) bool {
+	aggregator.mu.Lock()
+	defer aggregator.mu.Unlock()
+	return aggregator.add(&fill)
+}
+
+// AddFills adds fills in chronological order, whatever their order in fills.
+// It returns the number of fills that were not already added.
+func (aggregator *FillAggregator) AddFills(fills []OrderFill) int {
+	sorted := make([]OrderFill, len(fills))
+	copy(sorted, fills)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Time != sorted[j].Time {
+			return sorted[i].Time < sorted[j].Time
+		}
+		return sorted[i].Tid < sorted[j].Tid
+	})
+	aggregator.mu.Lock()
+	defer aggregator.mu.Unlock()
+	added := 0
+	for i := range sorted {
+		if aggregator.add(&sorted[i]) {
+			added++
+		}
+	}
+	return added
+}
+
+// Stats returns the statistics of coin, false if no fill of coin was added.
+func (aggregator *FillAggregator) Stats(coin string) (FillStats, bool) {
+	aggregator.mu.RLock()
+	defer aggregator.mu.RUnlock()
+	stats, ok := aggregator.stats[coin]
+	if !ok {
+		return FillStats{}, false
+	}
+	return stats.clone(), true
+}
+
+// AllStats returns the statistics of every coin sorted by coin.
+func (aggregator *FillAggregator) AllStats() []FillStats {
+	aggregator.mu.RLock()
+	defer aggregator.mu.RUnlock()
+	all := make([]FillStats, 0, len(aggregator.stats))
+	for _, stats := range aggregator.stats {
+		all = append(all, stats.clone())
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Coin < all[j].Coin })
+	return all
+}
+
+// Reset removes all the fills.
+func (aggregator *FillAggregator) Reset() {
+	aggregator.mu.Lock()
+	defer aggregator.mu.Unlock()
+	aggregator.stats = make(map[string]*FillStats)
+	aggregator.seen = make(map[fillKey]struct{})
+}
+
+// add applies a fill, the lock must be held.
+func (aggregator *FillAggregator) add(fill *OrderFill) bool {
+	key := fillKey{tid: fill.Tid, oid: fill.Oid, coin: fill.Coin}
+	if _, ok := aggregator.seen[key]; ok {
+		return false
+	}
+	aggregator.seen[key] = struct{}{}
+	stats, ok := aggregator.stats[fill.Coin]
+	if !ok {
+		stats = &FillStats{Coin: fill.Coin, Fees: map[string]float64{}}
+		aggregator.stats[fill.Coin] = stats
+	}
+	stats.Fills++
+	stats.Volume += fill.Sz
+	stats.Notional += fill.Sz * fill.Px
+	stats.RealizedPnl += fill.ClosedPnl
+	feeToken := fill.FeeToken
+	if feeToken == "" {
+		feeToken = "USDC"
+	}
+	stats.Fees[feeToken] += fill.Fee
+	stats.LastTime = max(stats.LastTime, fill.Time)
+
+	position := stats.Position
+	if startPosition, err := strconv.ParseFloat(fill.StartPosition, 64); err == nil {
+		position = startPosition
+	}
+	sz := fill.Sz
+	if fill.Side != "B" {
+		sz = -sz
+	}
+	next := position + sz
+	if math.Abs(next) < 1e-12 {
+		// Floating point residue of a closed position
+		next = 0
+	}
+	switch {
+	case next == 0:
+		stats.AvgEntryPx = 0
+	case position == 0 || position*next < 0:
+		// Opened or flipped
+		stats.AvgEntryPx = fill.Px
+	case math.Abs(next) > math.Abs(position):
+		// Increased, the entry price of a position opened before the first fill is approximated by the fill price
+		if stats.AvgEntryPx == 0 {
+			stats.AvgEntryPx = fill.Px
+		} else {
+			stats.AvgEntryPx = (stats.AvgEntryPx*math.Abs(position) + fill.Px*fill.Sz) / math.Abs(next)
+		}
+	}
+	stats.Position = next
+	return true
+}
@@ -0,0 +1,69 @@
+package hyperliquid
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFillAggregator(t *testing.T) {
+	aggregator := NewFillAggregator()
+	// Newest first, as returned by GetUserFills
+	fills := []OrderFill{
+		{Coin: "ETH", Side: "A", Px: 2300, Sz: 3, ClosedPnl: 400, Fee: 2, FeeToken: "USDC", Tid: 3, Oid: 3, Time: 300},
+		{Coin: "ETH", Side: "B", Px: 2200, Sz: 1, Fee: 1, FeeToken: "USDC", Tid: 2, Oid: 2, Time: 200},
+		{Coin: "ETH", Side: "B", Px: 2000, Sz: 1, Fee: 1, FeeToken: "USDC", Tid: 1, Oid: 1, Time: 100},
+		{Coin: "BTC", Side: "B", Px: 90000, Sz: 0.1, Fee: -0.5, FeeToken: "USDC", Tid: 4, Oid: 4, Time: 150},
+	}
+	if added := aggregator.AddFills(fills); added != 4 {
+		t.Errorf("AddFills() = %d, want 4", added)
+	}
+	stats, ok := aggregator.Stats("ETH")
+	if !ok {
+		t.Fatalf("Stats(ETH) expected stats")
+	}
+	if stats.Fills != 3 || stats.Volume != 5 || stats.Notional != 11100 || stats.Position != -1 ||
+		stats.AvgEntryPx != 2300 || stats.RealizedPnl != 400 || stats.Fees["USDC"] != 4 || stats.LastTime != 300 {
+		t.Errorf("Stats(ETH) = %+v", stats)
+	}
+
+	// The same fill received from the websocket is ignored
+	if aggregator.Add(fills[0]) {
+		t.Errorf("Add() expected duplicate fill to be ignored")
+	}
+	if aggregator.Add(OrderFill{Coin: "ETH", Side: "A", Px: 2400, Sz: 1, Tid: 5, Oid: 5, Time: 400}) != true {
+		t.Errorf("Add() expected new fill to be added")
+	}
+	if stats, _ := aggregator.Stats("ETH"); stats.Position != -2 || stats.AvgEntryPx != 2350 {
+		t.Errorf("Stats(ETH) = %+v, want short 2 at 2350", stats)
+	}
+	if all := aggregator.AllStats(); len(all) != 2 || all[0].Coin != "BTC" || all[0].Fees["USDC"] != -0.5 {
+		t.Errorf("AllStats() = %+v", all)
+	}
+
+	// The start position reported by the exchange takes precedence over the aggregated position
+	aggregator.Reset()
+	aggregator.Add(OrderFill{Coin: "SOL", Side: "B", Px: 100, Sz: 1, StartPosition: "2", Tid: 6, Time: 500})
+	aggregator.Add(OrderFill{Coin: "SOL", Side: "B", Px: 130, Sz: 0.3, StartPosition: "3", Tid: 7, Time: 600})
+	if stats, _ := aggregator.Stats("SOL"); stats.Position != 3.3 || math.Abs(stats.AvgEntryPx-(100*3+130*0.3)/3.3) > 1e-9 {
+		t.Errorf("Stats(SOL) = %+v", stats)
+	}
+	aggregator.Add(OrderFill{Coin: "SOL", Side: "A", Px: 120, Sz: 3.3, StartPosition: "3.3", Tid: 8, Time: 700})
+	if stats, _ := aggregator.Stats("SOL"); stats.Position != 0 || stats.AvgEntryPx != 0 {
+		t.Errorf("Stats(SOL) = %+v, want flat", stats)
+	}
+	if _, ok := aggregator.Stats("ETH"); ok {
+		t.Errorf("Stats(ETH) expected no stats after Reset")
+	}
+
+	// Spot buys pay the fee in the base token and sells in USDC
+	aggregator.Add(OrderFill{Coin: "@107", Side: "B", Px: 30, Sz: 10, Fee: 0.01, FeeToken: "HYPE", Tid: 9, Time: 800})
+	aggregator.Add(OrderFill{Coin: "@107", Side: "A", Px: 31, Sz: 10, Fee: 0.2, FeeToken: "USDC", Tid: 10, Time: 900})
+	stats, _ = aggregator.Stats("@107")
+	if len(stats.Fees) != 2 || stats.Fees["HYPE"] != 0.01 || stats.Fees["USDC"] != 0.2 {
+		t.Errorf("Stats(@107).Fees = %v, want 0.01 HYPE and 0.2 USDC", stats.Fees)
+	}
+	stats.Fees["USDC"] = 0
+	if stats, _ := aggregator.Stats("@107"); stats.Fees["USDC"] != 0.2 {
+		t.Errorf("Stats(@107).Fees = %v, want the fees unchanged by the caller", stats.Fees)
+	}
+}
@@ -1,5 +1,7 @@
 package hyperliquid
 
+import "net/http"
+
 type IHyperliquid interface {
 	IExchangeAPI
 	IInfoAPI
@@ -12,6 +14,7 @@ type Hyperliquid struct {
 
 // HyperliquidClientConfig represents the configuration options for the Hyperliquid client.
 // It allows configuring the network type, private key, and account address settings.
+// It is an Option, so it can be combined with the other options of NewHyperliquid.
 //
 // The configuration options include:
 //   - IsMainnet: Set to true for mainnet and false for testnet
@@ -23,29 +26,39 @@ type HyperliquidClientConfig struct {
 	AccountAddress string
 }
 
-// NewHyperliquid creates a new Hyperliquid API client.
-func NewHyperliquid(config *HyperliquidClientConfig) *Hyperliquid {
-	var defaultConfig *HyperliquidClientConfig
+func (config *HyperliquidClientConfig) apply(options *clientOptions) {
 	if config == nil {
-		defaultConfig = &HyperliquidClientConfig{
-			IsMainnet:      true,
-			PrivateKey:     "",
-			AccountAddress: "",
+		return
+	}
+	options.isMainnet = config.IsMainnet
+	options.privateKey = config.PrivateKey
+	options.accountAddress = config.AccountAddress
+}
+
+// NewHyperliquid creates a new Hyperliquid API client.
+// Without options it connects to the mainnet without private key.
+//
+//	hl := NewHyperliquid(&HyperliquidClientConfig{IsMainnet: false, PrivateKey: key, AccountAddress: address})
+//	hl := NewHyperliquid(WithMainnet(false), WithPrivateKey(key), WithAccountAddress(address), WithTimeout(10*time.Second))
+func NewHyperliquid(opts ...Option) *Hyperliquid {
+	options := clientOptions{isMainnet: true}
+	for _, opt := range opts {
+		if opt != nil {
+			opt.apply(&options)
 		}
-	} else {
-		defaultConfig = config
 	}
-	exchangeAPI := NewExchangeAPI(defaultConfig.IsMainnet)
-	exchangeAPI.SetPrivateKey(defaultConfig.PrivateKey)
-	exchangeAPI.SetAccountAddress(defaultConfig.AccountAddress)
-	infoAPI := NewInfoAPI(defaultConfig.IsMainnet)
-	infoAPI.SetAccountAddress(defaultConfig.AccountAddress)
+	client := options.client()
+	exchangeAPI := newExchangeAPI(client)
+	exchangeAPI.SetPrivateKey(options.privateKey)
+	exchangeAPI.SetAccountAddress(options.accountAddress)
+	infoAPI := newInfoAPI(client)
+	infoAPI.SetAccountAddress(options.accountAddress)
 	hl := &Hyperliquid{
 		ExchangeAPI: *exchangeAPI,
 		InfoAPI:     *infoAPI,
 	}
 
-	hl.UpdateVaultAddress(defaultConfig.AccountAddress)
+	hl.UpdateVaultAddress(options.accountAddress)
 	return hl
 }
 
@@ -54,6 +67,25 @@ func (h *Hyperliquid) SetDebugActive() {
 	h.InfoAPI.SetDebugActive()
 }
 
+// SetHTTPClient sets the HTTP client of all the requests. Pass nil to use http.DefaultClient.
+func (h *Hyperliquid) SetHTTPClient(httpClient *http.Client) {
+	h.ExchangeAPI.SetHTTPClient(httpClient)
+	h.InfoAPI.SetHTTPClient(httpClient)
+}
+
+// SetTransport sets the RoundTripper of the HTTP client of all the requests.
+func (h *Hyperliquid) SetTransport(transport http.RoundTripper) {
+	h.ExchangeAPI.SetTransport(transport)
+	h.InfoAPI.SetTransport(transport)
+}
+
+// SetRateLimiter limits all the requests with limiter, pass nil to disable the limit.
+// Set the same limiter on the WebSocketAPI to count its posts in the same limit.
+func (h *Hyperliquid) SetRateLimiter(limiter *RateLimiter) {
+	h.ExchangeAPI.SetRateLimiter(limiter)
+	h.InfoAPI.SetRateLimiter(limiter)
+}
+
 func (h *Hyperliquid) SetPrivateKey(privateKey string) error {
 	err := h.ExchangeAPI.SetPrivateKey(privateKey)
 	if err != nil {
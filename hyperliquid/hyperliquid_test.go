@@ -1,9 +1,18 @@
 package hyperliquid
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"sync"
 	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
 )
 
 func GetHyperliquidAPI() *Hyperliquid {
@@ -159,3 +168,155 @@ func TestHyperliquid_GoLimitOrders(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestNewHyperliquidOptions(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req InfoRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		requests[req.Type]++
+		count := requests[req.Type]
+		mu.Unlock()
+		switch req.Type {
+		case "meta":
+			// The first attempt fails and is retried
+			if count == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{"universe": []any{map[string]any{"name": "ETH", "szDecimals": 4, "maxLeverage": 25}}})
+		case "spotMeta":
+			json.NewEncoder(w).Encode(map[string]any{"tokens": []any{}, "universe": []any{}})
+		case "userRole":
+			json.NewEncoder(w).Encode(map[string]any{"role": "user"})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+	httpClient := &http.Client{}
+	logger := log.New()
+	hl := NewHyperliquid(
+		&HyperliquidClientConfig{IsMainnet: false, AccountAddress: "0x1"},
+		WithBaseURL(server.URL+"/"),
+		WithHTTPClient(httpClient),
+		WithTimeout(5*time.Second),
+		WithLogger(logger),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond}),
+	)
+	if hl.IsMainnet() || hl.AccountAddress() != "0x1" {
+		t.Errorf("NewHyperliquid() mainnet = %v, address = %s, want the testnet and 0x1", hl.IsMainnet(), hl.AccountAddress())
+	}
+	if hl.ExchangeAPI.baseURL != server.URL || hl.InfoAPI.baseURL != server.URL || hl.infoAPI.baseURL != server.URL {
+		t.Errorf("baseURL = %s, want %s", hl.ExchangeAPI.baseURL, server.URL)
+	}
+	if hl.ExchangeAPI.httpClient.Timeout != 5*time.Second || httpClient.Timeout != 0 {
+		t.Errorf("httpClient timeout = %v, want 5s without modifying the given client", hl.ExchangeAPI.httpClient.Timeout)
+	}
+	if hl.ExchangeAPI.Logger != logger || hl.InfoAPI.Logger != logger {
+		t.Errorf("Logger not set")
+	}
+	if info, ok := hl.ExchangeAPI.meta["ETH"]; !ok || info.MaxLeverage != 25 {
+		t.Errorf("meta = %+v, want ETH loaded from the base URL after a retry", hl.ExchangeAPI.meta)
+	}
+	if requests["meta"] != 2 {
+		t.Errorf("meta requested %d times, want 2", requests["meta"])
+	}
+
+	// Without retry policy the error is returned
+	hl.InfoAPI.SetRetryPolicy(nil)
+	if _, err := hl.InfoAPI.GetOpenOrders("0x1"); err == nil {
+		t.Errorf("GetOpenOrders() expected error")
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MinBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, backoff := range want {
+		if got := policy.backoff(i + 1); got != backoff {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, backoff)
+		}
+	}
+	if got := (&RetryPolicy{}).backoff(1); got != RETRY_MIN_BACKOFF {
+		t.Errorf("backoff(1) = %v, want %v", got, RETRY_MIN_BACKOFF)
+	}
+}
+
+// roundTripFunc is an http.RoundTripper answering the requests with a function.
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestHyperliquid_SetTransport(t *testing.T) {
+	var hosts []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		hosts = append(hosts, req.URL.Host)
+		var info InfoRequest
+		json.NewDecoder(req.Body).Decode(&info)
+		body := map[string]string{"meta": `{"universe":[]}`, "spotMeta": `{"tokens":[],"universe":[]}`, "userRole": `{"role":"user"}`}[info.Type]
+		if body == "" {
+			body = `[]`
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: http.Header{}}, nil
+	})
+	hl := NewHyperliquid(WithMainnet(false), WithTransport(transport))
+	// The meta and spot meta loaded by the APIs and the user role
+	if len(hosts) != 5 || http.DefaultClient.Transport != nil {
+		t.Errorf("requests = %v, want 5 requests through the transport without modifying http.DefaultClient", hosts)
+	}
+	hosts = nil
+	hl.SetHTTPClient(nil)
+	if hl.ExchangeAPI.HTTPClient() != http.DefaultClient || hl.infoAPI.HTTPClient() != http.DefaultClient {
+		t.Errorf("SetHTTPClient(nil) expected http.DefaultClient")
+	}
+	hl.SetTransport(transport)
+	hl.InfoAPI.GetOpenOrders("0x1")
+	hl.infoAPI.GetOpenOrders("0x1")
+	if len(hosts) != 2 || hosts[0] != strings.TrimPrefix(TESTNET_API_URL, "https://") {
+		t.Errorf("requests = %v, want 2 requests to the testnet through the transport", hosts)
+	}
+}
+
+func TestClient_RequestErrors(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		switch r.URL.Path {
+		case "/ratelimit":
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		}
+		w.Write([]byte("error"))
+	}))
+	defer server.Close()
+	client := NewClient(false)
+	client.baseURL = server.URL
+
+	_, err := client.Request("/ratelimit", map[string]any{})
+	var rateLimitErr RateLimitError
+	if !errors.As(err, &rateLimitErr) || rateLimitErr.RetryAfter != 0 || rateLimitErr.Code != http.StatusTooManyRequests || attempts != 1 {
+		t.Errorf("Request() error = %v after %d attempts, want a single RateLimitError", err, attempts)
+	}
+	client.SetRetryPolicy(&RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond})
+	attempts = 0
+	if _, err := client.Request("/ratelimit", map[string]any{}); !errors.As(err, &rateLimitErr) || attempts != 3 {
+		t.Errorf("Request() error = %v after %d attempts, want RateLimitError after 3 attempts", err, attempts)
+	}
+	// Client errors are not retried
+	attempts = 0
+	_, err = client.Request("/info", map[string]any{})
+	var apiErr APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusUnprocessableEntity || attempts != 1 {
+		t.Errorf("Request() error = %v after %d attempts, want APIError with code 422", err, attempts)
+	}
+	if got := parseRetryAfter("2"); got != 2*time.Second {
+		t.Errorf("parseRetryAfter() = %v, want 2s", got)
+	}
+}
@@ -0,0 +1,195 @@
+package hyperliquid
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// IcebergOrder is a limit order of which only a visible size rests on the book.
+// The hidden size is placed as a new slice of the visible size every time
+// the resting slice is completely filled, as reported by the orderUpdates stream.
+//
+//	iceberg, err := api.PlaceIceberg(ws, OrderRequest{Coin: "ETH", IsBuy: true, Sz: 10, LimitPx: 2000, ...}, 1)
+//	<-iceberg.Done()
+//	filledSz, err := iceberg.Result()
+type IcebergOrder struct {
+	api       *ExchangeAPI
+	sub       *WsSubscriber
+	request   OrderRequest
+	visibleSz float64
+	factor    float64
+	oid       int
+	sliceSz   float64
+	filledSz  float64
+	err       error
+	done      chan struct{}
+	stop      chan struct{}
+	once      sync.Once
+	mu        sync.Mutex
+}
+
+// PlaceIceberg places the first slice of visibleSz of the limit order request and
+// keeps replenishing it until request.Sz is filled.
+// The updates are received from the orderUpdates stream of ws, which must be connected.
+// Slices are placed without a cloid, request.Cloid is ignored.
+func (api *ExchangeAPI) PlaceIceberg(ws *WebSocketAPI, request OrderRequest, visibleSz float64) (*IcebergOrder, error) {
+	if request.OrderType.Limit == nil {
+		return nil, APIError{Message: "Iceberg order must be a limit order"}
+	}
+	if visibleSz <= 0 || visibleSz > request.Sz {
+		return nil, APIError{Message: fmt.Sprintf("Invalid visible size %v for a size of %v", visibleSz, request.Sz)}
+	}
+	user := api.VaultAddress()
+	if user == "" {
+		user = api.AccountAddress()
+	}
+	// Subscribe before placing the first slice so no update is missed
+	sub, err := ws.Subscribe(Subscription{Type: "orderUpdates", User: user})
+	if err != nil {
+		return nil, err
+	}
+	request.Cloid = ""
+	iceberg := &IcebergOrder{
+		api:       api,
+		sub:       sub,
+		request:   request,
+		visibleSz: visibleSz,
+		factor:    pow10(api.GetMeta(request).SzDecimals),
+		done:      make(chan struct{}),
+		stop:      make(chan struct{}),
+	}
+	if err := iceberg.place(); err != nil {
+		sub.Unsubscribe()
+		return nil, err
+	}
+	go iceberg.run()
+	return iceberg, nil
+}
+
+// Oid returns the id of the resting slice, 0 once the order is completely filled.
+func (iceberg *IcebergOrder) Oid() int {
+	iceberg.mu.Lock()
+	defer iceberg.mu.Unlock()
+	return iceberg.oid
+}
+
+// Done is closed once the whole size was filled, a slice was canceled or rejected,
+// or after Cancel or Close.
+func (iceberg *IcebergOrder) Done() <-chan struct{} {
+	return iceberg.done
+}
+
+// Result returns the filled size and the error that stopped the order if any.
+func (iceberg *IcebergOrder) Result() (float64, error) {
+	iceberg.mu.Lock()
+	defer iceberg.mu.Unlock()
+	return iceberg.filledSz, iceberg.err
+}
+
+// Cancel stops replenishing the order and cancels the resting slice.
+func (iceberg *IcebergOrder) Cancel() error {
+	iceberg.Close()
+	if oid := iceberg.Oid(); oid != 0 {
+		_, err := iceberg.api.CancelOrderByOID(iceberg.request.Coin, oid)
+		return err
+	}
+	return nil
+}
+
+// Close stops replenishing the order without canceling the resting slice.
+func (iceberg *IcebergOrder) Close() error {
+	iceberg.once.Do(func() { close(iceberg.stop) })
+	<-iceberg.done
+	return nil
+}
+
+// run replenishes the order on every fill of the resting slice.
+func (iceberg *IcebergOrder) run() {
+	defer close(iceberg.done)
+	defer iceberg.sub.Unsubscribe()
+	for {
+		select {
+		case msg, ok := <-iceberg.sub.C():
+			if !ok {
+				return
+			}
+			events, err := parseAccountEvents(&msg)
+			if err != nil {
+				iceberg.api.debug("Error parsing order updates: %s", err)
+				continue
+			}
+			for _, event := range events {
+				if event.Order == nil || event.Order.Order.Oid != int64(iceberg.Oid()) || !event.Order.IsFinal() {
+					continue
+				}
+				if event.Order.Status != "filled" {
+					iceberg.fail(APIError{Message: fmt.Sprintf("Iceberg slice %d %s", event.Order.Order.Oid, event.Order.Status)})
+					return
+				}
+				iceberg.mu.Lock()
+				iceberg.filledSz = iceberg.round(iceberg.filledSz + iceberg.sliceSz)
+				iceberg.oid = 0
+				iceberg.mu.Unlock()
+				if err := iceberg.place(); err != nil {
+					iceberg.fail(err)
+					return
+				}
+				if iceberg.Oid() == 0 {
+					return
+				}
+			}
+		case <-iceberg.stop:
+			return
+		}
+	}
+}
+
+// place places the next slice, repeating while the slices are filled immediately.
+// It does nothing once the whole size is filled.
+func (iceberg *IcebergOrder) place() error {
+	for {
+		iceberg.mu.Lock()
+		remaining := iceberg.round(iceberg.request.Sz - iceberg.filledSz)
+		iceberg.mu.Unlock()
+		if remaining <= 0 {
+			return nil
+		}
+		slice := iceberg.request
+		slice.Sz = math.Min(iceberg.visibleSz, remaining)
+		res, err := iceberg.api.Order(slice, GroupingNa)
+		if err != nil {
+			return err
+		}
+		statuses := res.Response.Data.Statuses
+		if len(statuses) != 1 {
+			return APIError{Message: fmt.Sprintf("Unexpected iceberg order statuses: %+v", statuses)}
+		}
+		if err := statuses[0].Err(); err != nil {
+			return err
+		}
+		iceberg.mu.Lock()
+		if statuses[0].Filled.OrderID != 0 {
+			iceberg.filledSz = iceberg.round(iceberg.filledSz + slice.Sz)
+			iceberg.mu.Unlock()
+			continue
+		}
+		iceberg.oid = statuses[0].Resting.OrderID
+		iceberg.sliceSz = slice.Sz
+		iceberg.mu.Unlock()
+		return nil
+	}
+}
+
+// fail records the error that stopped the order.
+func (iceberg *IcebergOrder) fail(err error) {
+	iceberg.mu.Lock()
+	defer iceberg.mu.Unlock()
+	iceberg.oid = 0
+	iceberg.err = err
+}
+
+// round rounds a size to the size decimals of the coin.
+func (iceberg *IcebergOrder) round(sz float64) float64 {
+	return math.Round(sz*iceberg.factor) / iceberg.factor
+}
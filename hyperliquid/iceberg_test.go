@@ -0,0 +1,69 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestIcebergOrder_Replenish(t *testing.T) {
+	subscribed := make(chan *websocket.Conn, 1)
+	server := newTestWsServer(t, func(conn *websocket.Conn, req WsRequest) any {
+		if req.Method == "subscribe" {
+			subscribed <- conn
+		}
+		return nil
+	})
+	ws := GetTestWebSocketAPI(t, server)
+	placed := make(chan OrderWire, 3)
+	oid := 0
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action PlaceOrderAction
+		json.Unmarshal(req.Action, &action)
+		oid++
+		placed <- action.Orders[0]
+		statuses := []any{map[string]any{"resting": map[string]any{"oid": oid}}}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "order", "data": map[string]any{"statuses": statuses}}}
+	})
+	request := OrderRequest{Coin: "ETH", IsBuy: true, Sz: 2.5, LimitPx: 2000, OrderType: OrderType{Limit: &LimitOrderType{Tif: TifGtc}}}
+	if _, err := exchangeAPI.PlaceIceberg(ws, request, 3); err == nil {
+		t.Errorf("PlaceIceberg() expected invalid visible size error")
+	}
+	iceberg, err := exchangeAPI.PlaceIceberg(ws, request, 1)
+	if err != nil {
+		t.Fatalf("PlaceIceberg() error = %v", err)
+	}
+	defer iceberg.Close()
+	conn := <-subscribed
+
+	for i, want := range []string{"1", "1", "0.5"} {
+		select {
+		case order := <-placed:
+			if order.SizePx != want || order.LimitPx != "2000" {
+				t.Errorf("slice %d = %+v, want %s at 2000", i, order, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for slice %d", i)
+		}
+		// Wait for the slice to be recorded as resting before filling it
+		for iceberg.Oid() != i+1 {
+			time.Sleep(time.Millisecond)
+		}
+		conn.WriteJSON(map[string]any{"channel": "orderUpdates", "data": []any{
+			map[string]any{"order": map[string]any{"coin": "ETH", "oid": i + 1, "side": "B"}, "status": "filled", "statusTimestamp": i},
+		}})
+	}
+	select {
+	case <-iceberg.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the iceberg to be filled")
+	}
+	if filledSz, err := iceberg.Result(); filledSz != 2.5 || err != nil {
+		t.Errorf("Result() = %v, %v, want 2.5, nil", filledSz, err)
+	}
+	if len(placed) != 0 {
+		t.Errorf("placed %d more slices after the order was filled", len(placed))
+	}
+}
@@ -1,10 +1,10 @@
 package hyperliquid
 
 import (
-	"encoding/json"
 	"fmt"
 	"log"
 	"strconv"
+	"strings"
 )
 
 // IInfoAPI is an interface for the /info service.
@@ -12,19 +12,19 @@ type IInfoAPI interface {
 	IClient // Base client interface
 
 	// INFO API ENDPOINTS
-	GetAllMids() (*map[string]string, error)
+	GetAllMids(dex ...string) (*map[string]string, error)
 	GetOpenOrders(address string) (*[]Order, error)
 	GetAccountOpenOrders() (*[]Order, error)
 	GetUserFills(address string) (*[]OrderFill, error)
 	GetAccountFills() (*[]OrderFill, error)
 	GetUserRateLimits(address string) (*float64, error)
-	GetL2BookSnapshot(coin string) (*L2BookSnapshot, error)
+	GetL2BookSnapshot(coin string, options ...L2BookOptions) (*L2BookSnapshot, error)
 	GetCandleSnapshot(coin string, interval string, startTime int64, endTime int64) (*CandleSnapshot, error)
 
 	// PERPETUALS INFO API ENDPOINTS
-	GetMeta() (*Meta, error)
-	GetUserState(address string) (*UserState, error)
-	GetAccountState() (*UserState, error)
+	GetMeta(dex ...string) (*Meta, error)
+	GetUserState(address string, dex ...string) (*UserState, error)
+	GetAccountState(dex ...string) (*UserState, error)
 	GetFundingUpdates(address string, startTime int64, endTime int64) (*[]FundingUpdate, error)
 	GetAccountFundingUpdates(startTime int64, endTime int64) (*[]FundingUpdate, error)
 	GetNonFundingUpdates(address string, startTime int64, endTime int64) (*[]NonFundingUpdate, error)
@@ -49,9 +49,14 @@ type InfoAPI struct {
 // It sets the base endpoint to "/info" and the client to the NewClient function.
 // The isMainnet parameter is used to set the network type.
 func NewInfoAPI(isMainnet bool) *InfoAPI {
+	return newInfoAPI(*NewClient(isMainnet))
+}
+
+// newInfoAPI creates an InfoAPI using client and loads the spot meta with it.
+func newInfoAPI(client Client) *InfoAPI {
 	api := InfoAPI{
 		baseEndpoint: "/info",
-		Client:       *NewClient(isMainnet),
+		Client:       client,
 	}
 	spotMeta, err := api.BuildSpotMetaMap()
 	if err != nil {
@@ -68,46 +73,51 @@ func (api *InfoAPI) Endpoint() string {
 }
 
 // Retrieve mids for all actively traded coins
+// The dex is optional, the first perp dex is used by default
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#retrieve-mids-for-all-actively-traded-coins
-func (api *InfoAPI) GetAllMids() (*map[string]string, error) {
+func (api *InfoAPI) GetAllMids(dex ...string) (*map[string]string, error) {
 	request := InfoRequest{
 		Type: "allMids",
+		Dex:  optionalDex(dex),
 	}
 	return MakeUniversalRequest[map[string]string](api, request)
 }
 
 // Retrieve spot meta and asset contexts
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint/spot#retrieve-spot-asset-contexts
-func (api *InfoAPI) GetAllSpotPrices() (*map[string]string, error) {
+func (api *InfoAPI) GetSpotMetaAndAssetCtxs() (*SpotMetaAndAssetCtxs, error) {
 	request := InfoRequest{
 		Type: "spotMetaAndAssetCtxs",
 	}
-	response, err := MakeUniversalRequest[SpotMetaAndAssetCtxsResponse](api, request)
+	return MakeUniversalRequest[SpotMetaAndAssetCtxs](api, request)
+}
+
+// GetSpotPair resolves a spot pair by its human name ("HYPE/USDC") or by its name in the API ("@107").
+func (api *InfoAPI) GetSpotPair(name string) (*SpotPair, error) {
+	spotMeta, err := api.GetSpotMeta()
 	if err != nil {
 		return nil, err
 	}
-
-	marketsData, ok := response[1].([]interface{})
+	pair, ok := spotMeta.Pair(name)
 	if !ok {
-		return nil, fmt.Errorf("invalid markets data format")
+		return nil, APIError{Message: fmt.Sprintf("Unknown spot pair: %s", name)}
 	}
+	return &pair, nil
+}
 
-	result := make(map[string]string)
-
-	marketBytes, err := json.Marshal(marketsData)
+// Retrieve the mid prices of all spot pairs
+// The keys are the names of the pairs (e.g. "PURR/USDC" or "@107"), pairs without a mid price are omitted
+func (api *InfoAPI) GetAllSpotPrices() (*map[string]string, error) {
+	response, err := api.GetSpotMetaAndAssetCtxs()
 	if err != nil {
 		return nil, err
 	}
-
-	var markets []Market
-	if err := json.Unmarshal(marketBytes, &markets); err != nil {
-		return nil, err
-	}
-
-	for _, market := range markets {
-		result[market.Coin] = market.MidPx
+	result := make(map[string]string, len(response.AssetCtxs))
+	for _, ctx := range response.AssetCtxs {
+		if ctx.MidPx != nil {
+			result[ctx.Coin] = strconv.FormatFloat(*ctx.MidPx, 'f', -1, 64)
+		}
 	}
-
 	return &result, nil
 }
 
@@ -145,6 +155,62 @@ func (api *InfoAPI) GetAccountFills() (*[]OrderFill, error) {
 	return api.GetUserFills(api.AccountAddress())
 }
 
+// Retrieve a user's fills by time (at most 2000 fills per response)
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#retrieve-a-users-fills-by-time
+func (api *InfoAPI) GetUserFillsByTime(address string, startTime int64, endTime int64) (*[]OrderFill, error) {
+	request := InfoRequest{
+		User:      address,
+		Type:      "userFillsByTime",
+		StartTime: startTime,
+		EndTime:   endTime,
+	}
+	return MakeUniversalRequest[[]OrderFill](api, request)
+}
+
+// Retrieve all of a user's fills between startTime and endTime
+// Follows the page limit of userFillsByTime transparently, see IterateUserFillsByTime.
+func (api *InfoAPI) GetAllUserFillsByTime(address string, startTime int64, endTime int64) (*[]OrderFill, error) {
+	fills := []OrderFill{}
+	iterator := api.IterateUserFillsByTime(address, startTime, endTime)
+	for iterator.Next() {
+		fills = append(fills, iterator.Fills()...)
+	}
+	if err := iterator.Err(); err != nil {
+		return nil, err
+	}
+	return &fills, nil
+}
+
+// IterateUserFillsByTime returns an iterator over the pages of a user's fills between startTime and endTime.
+// Each page is fetched with userFillsByTime starting at the time of the last fill of the previous page.
+//
+// Example:
+//
+//	iterator := api.IterateUserFillsByTime(address, startTime, endTime)
+//	for iterator.Next() {
+//		for _, fill := range iterator.Fills() { ... }
+//	}
+//	if err := iterator.Err(); err != nil { ... }
+func (api *InfoAPI) IterateUserFillsByTime(address string, startTime int64, endTime int64) *UserFillsIterator {
+	return &UserFillsIterator{
+		api:       api,
+		address:   address,
+		startTime: startTime,
+		endTime:   endTime,
+		seen:      make(map[int64]bool),
+	}
+}
+
+// Retrieve a user's historical orders (at most 2000 most recent orders)
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#retrieve-a-users-historical-orders
+func (api *InfoAPI) GetHistoricalOrders(address string) (*[]HistoricalOrder, error) {
+	request := InfoRequest{
+		User: address,
+		Type: "historicalOrders",
+	}
+	return MakeUniversalRequest[[]HistoricalOrder](api, request)
+}
+
 // Query user rate limits
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#query-user-rate-limits
 func (api *InfoAPI) GetUserRateLimits(address string) (*RatesLimits, error) {
@@ -163,12 +229,34 @@ func (api *InfoAPI) GetAccountRateLimits() (*RatesLimits, error) {
 }
 
 // L2 Book snapshot
+// The options are optional and aggregate the levels of the book
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#l2-book-snapshot
-func (api *InfoAPI) GetL2BookSnapshot(coin string) (*L2BookSnapshot, error) {
+//
+// Example:
+//
+//	nSigFigs := 3
+//	api.GetL2BookSnapshot("BTC", L2BookOptions{NSigFigs: &nSigFigs})
+func (api *InfoAPI) GetL2BookSnapshot(coin string, options ...L2BookOptions) (*L2BookSnapshot, error) {
 	request := InfoRequest{
 		Type: "l2Book",
 		Coin: coin,
 	}
+	if len(options) > 0 {
+		nSigFigs, mantissa := options[0].NSigFigs, options[0].Mantissa
+		if nSigFigs != nil && (*nSigFigs < 2 || *nSigFigs > 5) {
+			return nil, APIError{Message: fmt.Sprintf("Invalid nSigFigs: %d", *nSigFigs)}
+		}
+		if mantissa != nil {
+			if nSigFigs == nil || *nSigFigs != 5 {
+				return nil, APIError{Message: "Mantissa is only allowed with nSigFigs 5"}
+			}
+			if *mantissa != 1 && *mantissa != 2 && *mantissa != 5 {
+				return nil, APIError{Message: fmt.Sprintf("Invalid mantissa: %d", *mantissa)}
+			}
+		}
+		request.NSigFigs = nSigFigs
+		request.Mantissa = mantissa
+	}
 	return MakeUniversalRequest[L2BookSnapshot](api, request)
 }
 
@@ -188,10 +276,12 @@ func (api *InfoAPI) GetCandleSnapshot(coin string, interval string, startTime in
 }
 
 // Retrieve perpetuals metadata
+// The dex is optional, the first perp dex is used by default
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint/perpetuals#retrieve-perpetuals-metadata
-func (api *InfoAPI) GetMeta() (*Meta, error) {
+func (api *InfoAPI) GetMeta(dex ...string) (*Meta, error) {
 	request := InfoRequest{
 		Type: "meta",
+		Dex:  optionalDex(dex),
 	}
 	return MakeUniversalRequest[Meta](api, request)
 }
@@ -205,11 +295,13 @@ func (api *InfoAPI) GetSpotMeta() (*SpotMeta, error) {
 }
 
 // Retrieve user's perpetuals account summary
+// The dex is optional, the first perp dex is used by default
 // https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint/perpetuals#retrieve-users-perpetuals-account-summary
-func (api *InfoAPI) GetUserState(address string) (*UserState, error) {
+func (api *InfoAPI) GetUserState(address string, dex ...string) (*UserState, error) {
 	request := UserStateRequest{
 		User: address,
 		Type: "clearinghouseState",
+		Dex:  optionalDex(dex),
 	}
 	return MakeUniversalRequest[UserState](api, request)
 }
@@ -217,8 +309,8 @@ func (api *InfoAPI) GetUserState(address string) (*UserState, error) {
 // GetAccountState retrieve account's perpetuals account summary
 // The same as GetUserState but user is set to the account address
 // Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
-func (api *InfoAPI) GetAccountState() (*UserState, error) {
-	return api.GetUserState(api.AccountAddress())
+func (api *InfoAPI) GetAccountState(dex ...string) (*UserState, error) {
+	return api.GetUserState(api.AccountAddress(), dex...)
 }
 
 // GetUserStateSpot retrieve's a user's spot account summary
@@ -300,14 +392,437 @@ func (api *InfoAPI) GetHistoricalFundingRates(coin string, startTime int64, endT
 	return MakeUniversalRequest[[]HistoricalFundingRate](api, request)
 }
 
+// Query an order's status by oid (int) or cloid (hex string)
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#query-order-status-by-oid-or-cloid
+//
+// Example:
+//
+//	api.GetOrderStatus(address, 123456)
+//	api.GetOrderStatus(address, "0x1234567890abcdef1234567890abcdef")
+func (api *InfoAPI) GetOrderStatus(address string, oidOrCloid any) (*OrderStatusResponse, error) {
+	switch oid := oidOrCloid.(type) {
+	case int, int64, uint64:
+	case string:
+		if _, err := HexToInt(oid); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, APIError{Message: fmt.Sprintf("Invalid oid or cloid: %v", oidOrCloid)}
+	}
+	request := OrderStatusRequest{
+		User: address,
+		Type: "orderStatus",
+		Oid:  oidOrCloid,
+	}
+	return MakeUniversalRequest[OrderStatusResponse](api, request)
+}
+
+// Query an account's order status by oid or cloid
+// The same as GetOrderStatus but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountOrderStatus(oidOrCloid any) (*OrderStatusResponse, error) {
+	return api.GetOrderStatus(api.AccountAddress(), oidOrCloid)
+}
+
+// Retrieve a user's TWAP slice fills (at most 2000 most recent fills)
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#retrieve-a-users-twap-slice-fills
+func (api *InfoAPI) GetUserTwapSliceFills(address string) (*[]TwapSliceFill, error) {
+	request := InfoRequest{
+		User: address,
+		Type: "userTwapSliceFills",
+	}
+	return MakeUniversalRequest[[]TwapSliceFill](api, request)
+}
+
+// Retrieve account's TWAP slice fills
+// The same as GetUserTwapSliceFills but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountTwapSliceFills() (*[]TwapSliceFill, error) {
+	return api.GetUserTwapSliceFills(api.AccountAddress())
+}
+
+// Retrieve details for a vault
+// The user is optional, if set the follower state of the user is returned as well
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#retrieve-details-for-a-vault
+func (api *InfoAPI) GetVaultDetails(vaultAddress string, user *string) (*VaultDetails, error) {
+	request := InfoRequest{
+		Type:         "vaultDetails",
+		VaultAddress: vaultAddress,
+	}
+	if user != nil {
+		request.User = *user
+	}
+	return MakeUniversalRequest[VaultDetails](api, request)
+}
+
+// Retrieve a user's vault deposits
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#retrieve-a-users-vault-deposits
+func (api *InfoAPI) GetUserVaultEquities(address string) (*[]VaultEquity, error) {
+	request := InfoRequest{
+		User: address,
+		Type: "userVaultEquities",
+	}
+	return MakeUniversalRequest[[]VaultEquity](api, request)
+}
+
+// Retrieve account's vault deposits
+// The same as GetUserVaultEquities but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountVaultEquities() (*[]VaultEquity, error) {
+	return api.GetUserVaultEquities(api.AccountAddress())
+}
+
+// Retrieve the summaries of all vaults
+// The summaries contain the TVL of each vault, use GetVaultDetails for the APR and history.
+func (api *InfoAPI) GetVaultSummaries() (*[]VaultSummary, error) {
+	request := InfoRequest{
+		Type: "vaultSummaries",
+	}
+	return MakeUniversalRequest[[]VaultSummary](api, request)
+}
+
+// Retrieve the vaults led by a user
+func (api *InfoAPI) GetLeadingVaults(address string) (*[]LeadingVault, error) {
+	request := InfoRequest{
+		User: address,
+		Type: "leadingVaults",
+	}
+	return MakeUniversalRequest[[]LeadingVault](api, request)
+}
+
+// Retrieve the vaults led by the account
+// The same as GetLeadingVaults but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountLeadingVaults() (*[]LeadingVault, error) {
+	return api.GetLeadingVaults(api.AccountAddress())
+}
+
+// Retrieve a user's referral state
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#query-a-users-referral-information
+func (api *InfoAPI) GetReferralState(address string) (*ReferralState, error) {
+	request := InfoRequest{
+		User: address,
+		Type: "referral",
+	}
+	return MakeUniversalRequest[ReferralState](api, request)
+}
+
+// Retrieve account's referral state
+// The same as GetReferralState but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountReferralState() (*ReferralState, error) {
+	return api.GetReferralState(api.AccountAddress())
+}
+
+// Retrieve a user's fees
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#query-a-users-fees
+func (api *InfoAPI) GetUserFees(address string) (*UserFees, error) {
+	request := InfoRequest{
+		User: address,
+		Type: "userFees",
+	}
+	return MakeUniversalRequest[UserFees](api, request)
+}
+
+// Retrieve account's fees
+// The same as GetUserFees but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountFees() (*UserFees, error) {
+	return api.GetUserFees(api.AccountAddress())
+}
+
+// Retrieve a user's staking delegations
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#query-a-users-staking-delegations
+func (api *InfoAPI) GetDelegations(address string) (*[]Delegation, error) {
+	request := InfoRequest{
+		User: address,
+		Type: "delegations",
+	}
+	return MakeUniversalRequest[[]Delegation](api, request)
+}
+
+// Retrieve account's staking delegations
+// The same as GetDelegations but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountDelegations() (*[]Delegation, error) {
+	return api.GetDelegations(api.AccountAddress())
+}
+
+// Retrieve a user's staking summary
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#query-a-users-staking-summary
+func (api *InfoAPI) GetDelegatorSummary(address string) (*DelegatorSummary, error) {
+	request := InfoRequest{
+		User: address,
+		Type: "delegatorSummary",
+	}
+	return MakeUniversalRequest[DelegatorSummary](api, request)
+}
+
+// Retrieve account's staking summary
+// The same as GetDelegatorSummary but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountDelegatorSummary() (*DelegatorSummary, error) {
+	return api.GetDelegatorSummary(api.AccountAddress())
+}
+
+// Retrieve a user's staking history
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#query-a-users-staking-history
+func (api *InfoAPI) GetDelegatorHistory(address string) (*[]DelegatorEvent, error) {
+	request := InfoRequest{
+		User: address,
+		Type: "delegatorHistory",
+	}
+	return MakeUniversalRequest[[]DelegatorEvent](api, request)
+}
+
+// Retrieve account's staking history
+// The same as GetDelegatorHistory but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountDelegatorHistory() (*[]DelegatorEvent, error) {
+	return api.GetDelegatorHistory(api.AccountAddress())
+}
+
+// Retrieve a user's staking rewards
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#query-a-users-staking-rewards
+func (api *InfoAPI) GetDelegatorRewards(address string) (*[]DelegatorReward, error) {
+	request := InfoRequest{
+		User: address,
+		Type: "delegatorRewards",
+	}
+	return MakeUniversalRequest[[]DelegatorReward](api, request)
+}
+
+// Retrieve account's staking rewards
+// The same as GetDelegatorRewards but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountDelegatorRewards() (*[]DelegatorReward, error) {
+	return api.GetDelegatorRewards(api.AccountAddress())
+}
+
+// Retrieve the summaries of all validators
+// Use it to choose the validator to delegate to with the tokenDelegate action
+func (api *InfoAPI) GetValidatorSummaries() (*[]ValidatorSummary, error) {
+	request := InfoRequest{
+		Type: "validatorSummaries",
+	}
+	return MakeUniversalRequest[[]ValidatorSummary](api, request)
+}
+
+// Retrieve the perps at their open interest cap
+// Orders increasing the open interest of these perps are rejected
+func (api *InfoAPI) GetPerpsAtOpenInterestCap() (*[]string, error) {
+	request := InfoRequest{
+		Type: "perpsAtOpenInterestCap",
+	}
+	return MakeUniversalRequest[[]string](api, request)
+}
+
+// Helper function to check if a perp is at its open interest cap
+func (api *InfoAPI) IsAtOpenInterestCap(coin string) (bool, error) {
+	coins, err := api.GetPerpsAtOpenInterestCap()
+	if err != nil {
+		return false, err
+	}
+	for _, c := range *coins {
+		if c == coin {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Retrieve the predicted funding rates of Hyperliquid and other venues
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint/perpetuals#retrieve-predicted-funding-rates-for-different-venues
+// Example:
+//
+//	fundings, _ := api.GetPredictedFundings()
+//	binance := (*fundings)["BTC"]["BinPerp"]
+func (api *InfoAPI) GetPredictedFundings() (*PredictedFundings, error) {
+	request := InfoRequest{
+		Type: "predictedFundings",
+	}
+	return MakeUniversalRequest[PredictedFundings](api, request)
+}
+
+// Retrieve the spot deploy state of a user
+// It contains the tokens being deployed by the user and the current spot deploy gas auction
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint/spot#retrieve-information-about-the-spot-deploy-auction
+func (api *InfoAPI) GetSpotDeployState(address string) (*SpotDeployState, error) {
+	request := InfoRequest{
+		User: address,
+		Type: "spotDeployState",
+	}
+	return MakeUniversalRequest[SpotDeployState](api, request)
+}
+
+// Retrieve account's spot deploy state
+// The same as GetSpotDeployState but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountSpotDeployState() (*SpotDeployState, error) {
+	return api.GetSpotDeployState(api.AccountAddress())
+}
+
+// Retrieve the details of a spot token
+// The tokenId is the hex token id found in the spot meta (e.g. 0x6d1e7cde53ba9467b783cb7c530ce054)
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint/spot#retrieve-information-about-a-token
+func (api *InfoAPI) GetTokenDetails(tokenId string) (*TokenDetails, error) {
+	request := InfoRequest{
+		Type:    "tokenDetails",
+		TokenID: tokenId,
+	}
+	return MakeUniversalRequest[TokenDetails](api, request)
+}
+
+// Retrieve the maximum builder fee approved by a user for a builder
+// The fee is in tenths of a basis point, e.g. 10 means 1 basis point (0.01%)
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#check-builder-fee-approval
+func (api *InfoAPI) GetMaxBuilderFee(user string, builder string) (int, error) {
+	request := InfoRequest{
+		User:    user,
+		Type:    "maxBuilderFee",
+		Builder: builder,
+	}
+	response, err := MakeUniversalRequest[int](api, request)
+	if err != nil {
+		return 0, err
+	}
+	return *response, nil
+}
+
+// Retrieve the maximum builder fee approved by the account for a builder
+// The same as GetMaxBuilderFee but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountMaxBuilderFee(builder string) (int, error) {
+	return api.GetMaxBuilderFee(api.AccountAddress(), builder)
+}
+
+// Retrieve a user's portfolio
+// It contains the account value and PnL history for each period ("day", "week", "month", "allTime", "perpDay", ...)
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#query-a-users-portfolio
+func (api *InfoAPI) GetPortfolio(address string) (*Portfolio, error) {
+	request := InfoRequest{
+		User: address,
+		Type: "portfolio",
+	}
+	return MakeUniversalRequest[Portfolio](api, request)
+}
+
+// Retrieve account's portfolio
+// The same as GetPortfolio but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountPortfolio() (*Portfolio, error) {
+	return api.GetPortfolio(api.AccountAddress())
+}
+
+// Retrieve a user's open orders with additional frontend info
+// Unlike GetOpenOrders the orders include the trigger conditions and the TP/SL children
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint#retrieve-a-users-open-orders-with-additional-frontend-info
+func (api *InfoAPI) GetFrontendOpenOrders(address string) (*[]Order, error) {
+	request := InfoRequest{
+		User: address,
+		Type: "frontendOpenOrders",
+	}
+	return MakeUniversalRequest[[]Order](api, request)
+}
+
+// Retrieve account's open orders with additional frontend info
+// The same as GetFrontendOpenOrders but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountFrontendOpenOrders() (*[]Order, error) {
+	return api.GetFrontendOpenOrders(api.AccountAddress())
+}
+
+// Retrieve perpetuals meta and asset contexts
+// The asset contexts are in the same order as the meta universe, use AssetCtx to get the context of a coin
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint/perpetuals#retrieve-perpetuals-asset-contexts-includes-mark-price-current-funding-open-interest-etc
+func (api *InfoAPI) GetMetaAndAssetCtxs() (*MetaAndAssetCtxs, error) {
+	request := InfoRequest{
+		Type: "metaAndAssetCtxs",
+	}
+	return MakeUniversalRequest[MetaAndAssetCtxs](api, request)
+}
+
+// Retrieve the agents approved by a user
+// The agent approved without a name is not included, see GetUserRole
+func (api *InfoAPI) GetExtraAgents(address string) (*[]ExtraAgent, error) {
+	request := InfoRequest{
+		User: address,
+		Type: "extraAgents",
+	}
+	return MakeUniversalRequest[[]ExtraAgent](api, request)
+}
+
+// Retrieve the agents approved by the account
+// The same as GetExtraAgents but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountExtraAgents() (*[]ExtraAgent, error) {
+	return api.GetExtraAgents(api.AccountAddress())
+}
+
+// Retrieve the signers of a multi-sig user
+// Returns nil if the user is not a multi-sig user
+func (api *InfoAPI) GetMultiSigSigners(address string) (*MultiSigSigners, error) {
+	request := InfoRequest{
+		User: address,
+		Type: "userToMultiSigSigners",
+	}
+	response, err := MakeUniversalRequest[*MultiSigSigners](api, request)
+	if err != nil {
+		return nil, err
+	}
+	return *response, nil
+}
+
+// Retrieve the signers of the account
+// The same as GetMultiSigSigners but user is set to the account address
+// Check AccountAddress() or SetAccountAddress() if there is a need to set the account address
+func (api *InfoAPI) GetAccountMultiSigSigners() (*MultiSigSigners, error) {
+	return api.GetMultiSigSigners(api.AccountAddress())
+}
+
+// Retrieve all perp dexs
+// The first element is nil and stands for the default perp dex,
+// the index of a dex in the list is used to compute its asset ids
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint/perpetuals#retrieve-all-perpetual-dexs
+func (api *InfoAPI) GetPerpDexs() (*[]*PerpDex, error) {
+	request := InfoRequest{
+		Type: "perpDexs",
+	}
+	return MakeUniversalRequest[[]*PerpDex](api, request)
+}
+
+// Retrieve the status of the perp deploy gas auction
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/info-endpoint/perpetuals#retrieve-information-about-the-perp-deploy-auction
+func (api *InfoAPI) GetPerpDeployAuctionStatus() (*GasAuction, error) {
+	request := InfoRequest{
+		Type: "perpDeployAuctionStatus",
+	}
+	return MakeUniversalRequest[GasAuction](api, request)
+}
+
+// Retrieve the status of the exchange
+// Requests fail with ErrExchangeMaintenance while the exchange is down for maintenance
+func (api *InfoAPI) GetExchangeStatus() (*ExchangeStatus, error) {
+	request := InfoRequest{
+		Type: "exchangeStatus",
+	}
+	return MakeUniversalRequest[ExchangeStatus](api, request)
+}
+
 // Helper function to get the market price of a given coin
-// The coin parameter is the name of the coin
+// The coin parameter is the name of the coin,
+// coins of builder-deployed perp dexs are prefixed with the dex name
 //
 // Example:
 //
 //	api.GetMartketPx("BTC")
+//	api.GetMartketPx("xyz:XYZ100")
 func (api *InfoAPI) GetMartketPx(coin string) (float64, error) {
-	allMids, err := api.GetAllMids()
+	var dex []string
+	if name, _, found := strings.Cut(coin, ":"); found {
+		dex = append(dex, name)
+	}
+	allMids, err := api.GetAllMids(dex...)
 	if err != nil {
 		return 0, err
 	}
@@ -389,18 +904,64 @@ func (api *InfoAPI) BuildMetaMap() (map[string]AssetInfo, error) {
 	for index, asset := range result.Universe {
 		if asset.Name == "BTC" {
 			metaMap["BTC"] = AssetInfo{
-				SzDecimals: asset.SzDecimals,
-				AssetID:    index,
+				SzDecimals:   asset.SzDecimals,
+				AssetID:      index,
+				MaxLeverage:  asset.MaxLeverage,
+				OnlyIsolated: asset.OnlyIsolated,
 			}
 		}
 		metaMap[asset.Name] = AssetInfo{
-			SzDecimals: asset.SzDecimals,
-			AssetID:    index,
+			SzDecimals:   asset.SzDecimals,
+			AssetID:      index,
+			MaxLeverage:  asset.MaxLeverage,
+			OnlyIsolated: asset.OnlyIsolated,
+		}
+	}
+	return metaMap, nil
+}
+
+// Helper function to build a map of asset names to asset info for a builder-deployed perp dex
+// Asset ids of builder-deployed perps are 100000 + dexIndex*10000 + indexInMeta
+func (api *InfoAPI) BuildPerpDexMetaMap(dex string) (map[string]AssetInfo, error) {
+	dexs, err := api.GetPerpDexs()
+	if err != nil {
+		return nil, err
+	}
+	dexIndex := -1
+	for index, perpDex := range *dexs {
+		if perpDex != nil && perpDex.Name == dex {
+			dexIndex = index
+			break
+		}
+	}
+	if dexIndex < 0 {
+		return nil, APIError{Message: fmt.Sprintf("Perp dex %s not found", dex)}
+	}
+	result, err := api.GetMeta(dex)
+	if err != nil {
+		return nil, err
+	}
+	metaMap := make(map[string]AssetInfo)
+	for index, asset := range result.Universe {
+		metaMap[asset.Name] = AssetInfo{
+			SzDecimals:   asset.SzDecimals,
+			AssetID:      PERP_DEX_ASSET_OFFSET + dexIndex*PERP_DEX_ASSET_STRIDE + index,
+			MaxLeverage:  asset.MaxLeverage,
+			OnlyIsolated: asset.OnlyIsolated,
 		}
 	}
 	return metaMap, nil
 }
 
+// optionalDex returns the perp dex of an optional dex argument.
+// An empty string selects the default perp dex.
+func optionalDex(dex []string) string {
+	if len(dex) > 0 {
+		return dex[0]
+	}
+	return ""
+}
+
 // Helper function to build a map of asset names to asset info
 // It is used to get the assetId for a given asset name
 func (api *InfoAPI) BuildSpotMetaMap() (map[string]AssetInfo, error) {
@@ -413,6 +974,7 @@ func (api *InfoAPI) BuildSpotMetaMap() (map[string]AssetInfo, error) {
 		name        string
 		szDecimals  int
 		weiDecimals int
+		tokenID     string
 	}, len(spotMeta.Tokens))
 
 	for _, token := range spotMeta.Tokens {
@@ -420,7 +982,8 @@ func (api *InfoAPI) BuildSpotMetaMap() (map[string]AssetInfo, error) {
 			name        string
 			szDecimals  int
 			weiDecimals int
-		}{token.Name, token.SzDecimals, token.WeiDecimals}
+			tokenID     string
+		}{token.Name, token.SzDecimals, token.WeiDecimals, token.TokenID}
 	}
 
 	metaMap := make(map[string]AssetInfo)
@@ -435,9 +998,74 @@ func (api *InfoAPI) BuildSpotMetaMap() (map[string]AssetInfo, error) {
 					WeiDecimals: token.weiDecimals,
 					AssetID:     universe.Index,
 					SpotName:    universe.Name,
+					TokenID:     token.tokenID,
 				}
 			}
 		}
 	}
 	return metaMap, nil
 }
+
+// UserFillsIterator pages through the fills returned by userFillsByTime.
+type UserFillsIterator struct {
+	api       *InfoAPI
+	address   string
+	startTime int64
+	endTime   int64
+	seen      map[int64]bool // tids of the fills at startTime already returned
+	fills     []OrderFill
+	done      bool
+	err       error
+}
+
+// Next fetches the next page of fills.
+// Returns false when there are no more fills or an error occurred, check Err().
+func (it *UserFillsIterator) Next() bool {
+	if it.done {
+		return false
+	}
+	page, err := it.api.GetUserFillsByTime(it.address, it.startTime, it.endTime)
+	if err != nil {
+		it.err = err
+		it.done = true
+		return false
+	}
+	// Several fills can share the last timestamp, so the next page starts at that
+	// timestamp and the fills already returned are skipped.
+	lastTime := it.startTime
+	for _, fill := range *page {
+		lastTime = max(lastTime, fill.Time)
+	}
+	it.fills = it.fills[:0]
+	seen := make(map[int64]bool)
+	for _, fill := range *page {
+		if fill.Time == lastTime {
+			seen[fill.Tid] = true
+		}
+		if fill.Time == it.startTime && it.seen[fill.Tid] {
+			continue
+		}
+		it.fills = append(it.fills, fill)
+	}
+	if lastTime == it.startTime {
+		for tid := range it.seen {
+			seen[tid] = true
+		}
+	}
+	it.seen = seen
+	it.startTime = lastTime
+	if len(*page) < USER_FILLS_PAGE_SIZE || len(it.fills) == 0 {
+		it.done = true
+	}
+	return len(it.fills) > 0
+}
+
+// Fills returns a copy of the fills of the current page.
+func (it *UserFillsIterator) Fills() []OrderFill {
+	return append([]OrderFill(nil), it.fills...)
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *UserFillsIterator) Err() error {
+	return it.err
+}
@@ -1,6 +1,11 @@
 package hyperliquid
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 )
@@ -20,6 +25,32 @@ func GetInfoAPI() *InfoAPI {
 	return api
 }
 
+// GetTestInfoAPI returns an InfoAPI sending its requests to a local server
+// that answers every request with the result of handle.
+func GetTestInfoAPI(t *testing.T, handle func(req InfoRequest) any) *InfoAPI {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The oid of orderStatus is a number or a cloid, it is passed to handle as a string
+		var req struct {
+			InfoRequest
+			Oid any `json:"oid"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Decode() error = %v", err)
+		}
+		if req.Oid != nil {
+			req.InfoRequest.Oid = fmt.Sprint(req.Oid)
+		}
+		json.NewEncoder(w).Encode(handle(req.InfoRequest))
+	}))
+	t.Cleanup(server.Close)
+	api := &InfoAPI{Client: *NewClient(false), baseEndpoint: "/info"}
+	api.baseURL = server.URL
+	if GLOBAL_DEBUG {
+		api.SetDebugActive()
+	}
+	return api
+}
+
 func TestInfoAPI_AccountAddress(t *testing.T) {
 	api := GetInfoAPI()
 	address := api.AccountAddress()
@@ -80,6 +111,25 @@ func TestInfoAPI_GetAccountFills(t *testing.T) {
 	t.Logf("GetAccountFills() = %v", res)
 }
 
+func TestInfoAPI_GetHistoricalOrders(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetHistoricalOrders(api.AccountAddress())
+	if err != nil {
+		t.Errorf("GetHistoricalOrders() error = %v", err)
+	}
+	if len(*res) == 0 {
+		t.Errorf("GetHistoricalOrders() len = %v, want > %v", res, 0)
+	}
+	res0 := (*res)[0]
+	if res0.Status == "" {
+		t.Errorf("res0.Status = %v, want not empty", res0.Status)
+	}
+	if res0.Order.Oid == 0 {
+		t.Errorf("res0.Order.Oid = %v, want > %v", res0.Order.Oid, 0)
+	}
+	t.Logf("GetHistoricalOrders() = %v", res)
+}
+
 func TestInfoAPI_GetAccountRateLimits(t *testing.T) {
 	api := GetInfoAPI()
 	res, err := api.GetAccountRateLimits()
@@ -364,3 +414,561 @@ func TestInfoAPI_GetUserStateSpot(t *testing.T) {
 	}
 	t.Logf("GetUserStateSpot() = %+v", res)
 }
+
+func TestInfoAPI_GetOrderStatus(t *testing.T) {
+	api := GetInfoAPI()
+	orders, err := api.GetAccountOpenOrders()
+	if err != nil {
+		t.Errorf("GetAccountOpenOrders() error = %v", err)
+	}
+	if len(*orders) == 0 {
+		t.Skip("No open orders to query")
+	}
+	oid := (*orders)[0].Oid
+	res, err := api.GetAccountOrderStatus(oid)
+	if err != nil {
+		t.Errorf("GetOrderStatus() error = %v", err)
+	}
+	if !res.IsFound() {
+		t.Errorf("GetOrderStatus() = %+v, want order", res)
+	}
+	if res.Order.Order.Oid != oid {
+		t.Errorf("GetOrderStatus().Order.Oid = %v, want %v", res.Order.Order.Oid, oid)
+	}
+	res, err = api.GetAccountOrderStatus(1)
+	if err != nil {
+		t.Errorf("GetOrderStatus() error = %v", err)
+	}
+	if res.Status != "unknownOid" {
+		t.Errorf("GetOrderStatus().Status = %v, want unknownOid", res.Status)
+	}
+	_, err = api.GetAccountOrderStatus(1.5)
+	if err == nil {
+		t.Errorf("GetOrderStatus(1.5) expected error")
+	}
+	t.Logf("GetOrderStatus() = %+v", res)
+}
+
+func TestInfoAPI_GetUserFillsByTime(t *testing.T) {
+	api := GetInfoAPI()
+	startTime, endTime := GetDefaultTimeRange()
+	res, err := api.GetUserFillsByTime(api.AccountAddress(), startTime, endTime)
+	if err != nil {
+		t.Errorf("GetUserFillsByTime() error = %v", err)
+	}
+	for _, fill := range *res {
+		if fill.Time < startTime || fill.Time > endTime {
+			t.Errorf("fill.Time = %v, want between %v and %v", fill.Time, startTime, endTime)
+		}
+	}
+	t.Logf("GetUserFillsByTime() = %v", res)
+}
+
+func TestInfoAPI_IterateUserFillsByTime(t *testing.T) {
+	// 4500 fills, two per millisecond starting at 1000
+	var allFills []OrderFill
+	for i := 0; i < 4500; i++ {
+		allFills = append(allFills, OrderFill{Tid: int64(i), Time: int64(1000 + i/2)})
+	}
+	requests := 0
+	api := GetTestInfoAPI(t, func(req InfoRequest) any {
+		requests++
+		if req.Type != "userFillsByTime" {
+			t.Errorf("req.Type = %v, want userFillsByTime", req.Type)
+		}
+		page := []OrderFill{}
+		for _, fill := range allFills {
+			if fill.Time >= req.StartTime && fill.Time <= req.EndTime && len(page) < USER_FILLS_PAGE_SIZE {
+				page = append(page, fill)
+			}
+		}
+		return page
+	})
+	res, err := api.GetAllUserFillsByTime("0x1", 1000, 10000)
+	if err != nil {
+		t.Fatalf("GetAllUserFillsByTime() error = %v", err)
+	}
+	if len(*res) != len(allFills) {
+		t.Errorf("GetAllUserFillsByTime() len = %v, want %v", len(*res), len(allFills))
+	}
+	for i, fill := range *res {
+		if fill.Tid != int64(i) {
+			t.Fatalf("fill[%d].Tid = %v, want %v", i, fill.Tid, i)
+		}
+	}
+	if requests != 3 {
+		t.Errorf("requests = %v, want 3", requests)
+	}
+}
+
+func TestInfoAPI_GetAccountTwapSliceFills(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetAccountTwapSliceFills()
+	if err != nil {
+		t.Errorf("GetAccountTwapSliceFills() error = %v", err)
+	}
+	for _, sliceFill := range *res {
+		if sliceFill.TwapID == 0 {
+			t.Errorf("sliceFill.TwapID = %v, want > %v", sliceFill.TwapID, 0)
+		}
+	}
+	t.Logf("GetAccountTwapSliceFills() = %v", res)
+}
+
+func TestInfoAPI_GetVaultDetails(t *testing.T) {
+	api := GetInfoAPI()
+	// HLP vault on testnet
+	vaultAddress := "0xa15099a30bbf2e68942d6f4c43d70d04faeab0a0"
+	user := api.AccountAddress()
+	res, err := api.GetVaultDetails(vaultAddress, &user)
+	if err != nil {
+		t.Errorf("GetVaultDetails() error = %v", err)
+	}
+	if res.Leader == "" {
+		t.Errorf("GetVaultDetails().Leader = %v, want not empty", res.Leader)
+	}
+	if _, ok := res.Portfolio["allTime"]; !ok {
+		t.Errorf("GetVaultDetails().Portfolio = %v, want allTime history", res.Portfolio)
+	}
+	t.Logf("GetVaultDetails() = %+v", res)
+}
+
+func TestInfoAPI_UnmarshalPortfolio(t *testing.T) {
+	data := `[["day",{"accountValueHistory":[[1700000000000,"100.5"],[1700000060000,"101.0"]],"pnlHistory":[[1700000000000,"0.0"]],"vlm":"1500.25"}],["allTime",{"accountValueHistory":[],"pnlHistory":[],"vlm":"0.0"}]]`
+	var portfolio Portfolio
+	if err := json.Unmarshal([]byte(data), &portfolio); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	day, ok := portfolio["day"]
+	if !ok {
+		t.Fatalf("Portfolio = %v, want day history", portfolio)
+	}
+	if len(day.AccountValueHistory) != 2 || day.AccountValueHistory[1].Time != 1700000060000 || day.AccountValueHistory[1].Value != 101 {
+		t.Errorf("day.AccountValueHistory = %+v", day.AccountValueHistory)
+	}
+	if day.Vlm != 1500.25 {
+		t.Errorf("day.Vlm = %v, want 1500.25", day.Vlm)
+	}
+	if _, ok := portfolio["allTime"]; !ok {
+		t.Errorf("Portfolio = %v, want allTime history", portfolio)
+	}
+}
+
+func TestInfoAPI_GetAccountVaultEquities(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetAccountVaultEquities()
+	if err != nil {
+		t.Errorf("GetAccountVaultEquities() error = %v", err)
+	}
+	for _, equity := range *res {
+		if equity.VaultAddress == "" {
+			t.Errorf("equity.VaultAddress = %v, want not empty", equity.VaultAddress)
+		}
+	}
+	t.Logf("GetAccountVaultEquities() = %+v", res)
+}
+
+func TestInfoAPI_GetVaultSummaries(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetVaultSummaries()
+	if err != nil {
+		t.Errorf("GetVaultSummaries() error = %v", err)
+	}
+	for _, summary := range *res {
+		if summary.VaultAddress == "" {
+			t.Errorf("summary.VaultAddress = %v, want not empty", summary.VaultAddress)
+		}
+	}
+	t.Logf("GetVaultSummaries() = %+v", res)
+}
+
+func TestInfoAPI_GetAccountLeadingVaults(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetAccountLeadingVaults()
+	if err != nil {
+		t.Errorf("GetAccountLeadingVaults() error = %v", err)
+	}
+	t.Logf("GetAccountLeadingVaults() = %+v", res)
+}
+
+func TestInfoAPI_GetAccountReferralState(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetAccountReferralState()
+	if err != nil {
+		t.Errorf("GetAccountReferralState() error = %v", err)
+	}
+	t.Logf("GetAccountReferralState() = %+v", res)
+}
+
+func TestInfoAPI_GetAccountFees(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetAccountFees()
+	if err != nil {
+		t.Errorf("GetAccountFees() error = %v", err)
+	}
+	if res.UserCrossRate <= 0 {
+		t.Errorf("UserCrossRate = %v, want > 0", res.UserCrossRate)
+	}
+	t.Logf("GetAccountFees() = %+v", res)
+}
+
+func TestInfoAPI_GetAccountDelegations(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetAccountDelegations()
+	if err != nil {
+		t.Errorf("GetAccountDelegations() error = %v", err)
+	}
+	t.Logf("GetAccountDelegations() = %+v", res)
+}
+
+func TestInfoAPI_GetAccountDelegatorSummary(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetAccountDelegatorSummary()
+	if err != nil {
+		t.Errorf("GetAccountDelegatorSummary() error = %v", err)
+	}
+	t.Logf("GetAccountDelegatorSummary() = %+v", res)
+}
+
+func TestInfoAPI_GetAccountDelegatorHistory(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetAccountDelegatorHistory()
+	if err != nil {
+		t.Errorf("GetAccountDelegatorHistory() error = %v", err)
+	}
+	t.Logf("GetAccountDelegatorHistory() = %+v", res)
+}
+
+func TestInfoAPI_GetAccountDelegatorRewards(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetAccountDelegatorRewards()
+	if err != nil {
+		t.Errorf("GetAccountDelegatorRewards() error = %v", err)
+	}
+	t.Logf("GetAccountDelegatorRewards() = %+v", res)
+}
+
+func TestInfoAPI_GetValidatorSummaries(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetValidatorSummaries()
+	if err != nil {
+		t.Errorf("GetValidatorSummaries() error = %v", err)
+	}
+	for _, summary := range *res {
+		if summary.Validator == "" {
+			t.Errorf("summary.Validator = %v, want not empty", summary.Validator)
+		}
+	}
+	t.Logf("GetValidatorSummaries() = %+v", res)
+}
+
+func TestInfoAPI_GetPerpsAtOpenInterestCap(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetPerpsAtOpenInterestCap()
+	if err != nil {
+		t.Errorf("GetPerpsAtOpenInterestCap() error = %v", err)
+	}
+	t.Logf("GetPerpsAtOpenInterestCap() = %+v", res)
+}
+
+func TestInfoAPI_GetPredictedFundings(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetPredictedFundings()
+	if err != nil {
+		t.Errorf("GetPredictedFundings() error = %v", err)
+	}
+	if _, ok := (*res)["BTC"]["HlPerp"]; !ok {
+		t.Errorf("GetPredictedFundings() = %v, want BTC HlPerp funding", res)
+	}
+	t.Logf("GetPredictedFundings() = %+v", res)
+}
+
+func TestInfoAPI_UnmarshalPredictedFundings(t *testing.T) {
+	data := `[["AVAX",[["BinPerp",{"fundingRate":"0.0001","nextFundingTime":1733961600000}],["HlPerp",{"fundingRate":"0.0000125","nextFundingTime":1733958000000,"fundingIntervalHours":1}],["BybitPerp",null]]]]`
+	var fundings PredictedFundings
+	if err := json.Unmarshal([]byte(data), &fundings); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	venues, ok := fundings["AVAX"]
+	if !ok {
+		t.Fatalf("PredictedFundings = %v, want AVAX fundings", fundings)
+	}
+	if hl := venues["HlPerp"]; hl.FundingRate != 0.0000125 || hl.FundingIntervalHours != 1 {
+		t.Errorf("HlPerp = %+v", hl)
+	}
+	if bin := venues["BinPerp"]; bin.NextFundingTime != 1733961600000 {
+		t.Errorf("BinPerp = %+v", bin)
+	}
+	if _, ok := venues["BybitPerp"]; ok {
+		t.Errorf("PredictedFundings = %v, want no BybitPerp funding", venues)
+	}
+}
+
+func TestInfoAPI_GetAccountSpotDeployState(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetAccountSpotDeployState()
+	if err != nil {
+		t.Errorf("GetAccountSpotDeployState() error = %v", err)
+	}
+	if res.GasAuction.StartTimeSeconds == 0 {
+		t.Errorf("GasAuction.StartTimeSeconds = 0, want auction start time")
+	}
+	t.Logf("GetAccountSpotDeployState() = %+v", res)
+}
+
+func TestInfoAPI_GetTokenDetails(t *testing.T) {
+	api := GetInfoAPI()
+	spotMeta, err := api.GetSpotMeta()
+	if err != nil {
+		t.Fatalf("GetSpotMeta() error = %v", err)
+	}
+	token := spotMeta.Tokens[0]
+	res, err := api.GetTokenDetails(token.TokenID)
+	if err != nil {
+		t.Errorf("GetTokenDetails() error = %v", err)
+	}
+	if res.Name != token.Name {
+		t.Errorf("res.Name = %v, want %v", res.Name, token.Name)
+	}
+	t.Logf("GetTokenDetails() = %+v", res)
+}
+
+func TestInfoAPI_GetAccountMaxBuilderFee(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetAccountMaxBuilderFee("0x0000000000000000000000000000000000000001")
+	if err != nil {
+		t.Errorf("GetAccountMaxBuilderFee() error = %v", err)
+	}
+	t.Logf("GetAccountMaxBuilderFee() = %v", res)
+}
+
+func TestInfoAPI_GetAccountPortfolio(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetAccountPortfolio()
+	if err != nil {
+		t.Errorf("GetAccountPortfolio() error = %v", err)
+	}
+	if _, ok := (*res)["allTime"]; !ok {
+		t.Errorf("GetAccountPortfolio() = %v, want allTime history", res)
+	}
+	t.Logf("GetAccountPortfolio() = %+v", res)
+}
+
+func TestInfoAPI_GetAccountFrontendOpenOrders(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetAccountFrontendOpenOrders()
+	if err != nil {
+		t.Errorf("GetAccountFrontendOpenOrders() error = %v", err)
+	}
+	t.Logf("GetAccountFrontendOpenOrders() = %+v", res)
+}
+
+func TestInfoAPI_GetMetaAndAssetCtxs(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetMetaAndAssetCtxs()
+	if err != nil {
+		t.Fatalf("GetMetaAndAssetCtxs() error = %v", err)
+	}
+	ctx, ok := res.AssetCtx("BTC")
+	if !ok {
+		t.Fatalf("AssetCtx(BTC) not found")
+	}
+	if ctx.MarkPx <= 0 {
+		t.Errorf("ctx.MarkPx = %v, want > 0", ctx.MarkPx)
+	}
+	t.Logf("AssetCtx(BTC) = %+v", ctx)
+}
+
+func TestInfoAPI_UnmarshalMetaAndAssetCtxs(t *testing.T) {
+	data := `[{"universe":[{"name":"BTC","szDecimals":5,"maxLeverage":50},{"name":"ETH","szDecimals":4,"maxLeverage":50}]},[
+		{"dayNtlVlm":"1169046.29","funding":"0.0000125","impactPxs":["99990.0","100010.0"],"markPx":"100000.0","midPx":"100001.0","openInterest":"688.11","oraclePx":"100005.0","premium":"0.00031774","prevDayPx":"98000.0","dayBaseVlm":"11.7"},
+		{"dayNtlVlm":"0.0","funding":"-0.00001","impactPxs":null,"markPx":"3000.0","midPx":null,"openInterest":"10.0","oraclePx":"3001.0","premium":null,"prevDayPx":"3000.0","dayBaseVlm":"0.0"}
+	]]`
+	var res MetaAndAssetCtxs
+	if err := json.Unmarshal([]byte(data), &res); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	btc, ok := res.AssetCtx("BTC")
+	if !ok || btc.Funding != 0.0000125 || btc.OpenInterest != 688.11 || btc.MidPx == nil || *btc.MidPx != 100001 {
+		t.Errorf("AssetCtx(BTC) = %+v", btc)
+	}
+	eth, ok := res.AssetCtx("ETH")
+	if !ok || eth.MidPx != nil || eth.Premium != nil || eth.Funding != -0.00001 {
+		t.Errorf("AssetCtx(ETH) = %+v", eth)
+	}
+	if _, ok := res.AssetCtx("SOL"); ok {
+		t.Errorf("AssetCtx(SOL) found, want not found")
+	}
+}
+
+func TestInfoAPI_GetAccountExtraAgents(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetAccountExtraAgents()
+	if err != nil {
+		t.Errorf("GetAccountExtraAgents() error = %v", err)
+	}
+	t.Logf("GetAccountExtraAgents() = %+v", res)
+}
+
+func TestInfoAPI_GetAccountMultiSigSigners(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetAccountMultiSigSigners()
+	if err != nil {
+		t.Errorf("GetAccountMultiSigSigners() error = %v", err)
+	}
+	if res != nil && res.Threshold > len(res.AuthorizedUsers) {
+		t.Errorf("Threshold = %v, want <= %v", res.Threshold, len(res.AuthorizedUsers))
+	}
+	t.Logf("GetAccountMultiSigSigners() = %+v", res)
+}
+
+func TestInfoAPI_GetPerpDexs(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetPerpDexs()
+	if err != nil {
+		t.Fatalf("GetPerpDexs() error = %v", err)
+	}
+	if len(*res) == 0 || (*res)[0] != nil {
+		t.Errorf("GetPerpDexs() = %v, want default dex first", res)
+	}
+	t.Logf("GetPerpDexs() = %+v", res)
+}
+
+func TestInfoAPI_BuildPerpDexMetaMap(t *testing.T) {
+	api := GetTestInfoAPI(t, func(req InfoRequest) any {
+		switch req.Type {
+		case "perpDexs":
+			return []any{nil, map[string]any{"name": "abc"}, map[string]any{"name": "xyz"}}
+		case "meta":
+			if req.Dex != "xyz" {
+				t.Errorf("meta dex = %v, want xyz", req.Dex)
+			}
+			return map[string]any{"universe": []any{
+				map[string]any{"name": "xyz:AAA", "szDecimals": 2},
+				map[string]any{"name": "xyz:BBB", "szDecimals": 3},
+			}}
+		}
+		t.Errorf("unexpected request %+v", req)
+		return nil
+	})
+	meta, err := api.BuildPerpDexMetaMap("xyz")
+	if err != nil {
+		t.Fatalf("BuildPerpDexMetaMap() error = %v", err)
+	}
+	if info := meta["xyz:BBB"]; info.AssetID != 120001 || info.SzDecimals != 3 {
+		t.Errorf("meta[xyz:BBB] = %+v, want asset 120001", info)
+	}
+	if _, err := api.BuildPerpDexMetaMap("unknown"); err == nil {
+		t.Errorf("BuildPerpDexMetaMap(unknown) expected error")
+	}
+}
+
+func TestInfoAPI_GetPerpDeployAuctionStatus(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetPerpDeployAuctionStatus()
+	if err != nil {
+		t.Errorf("GetPerpDeployAuctionStatus() error = %v", err)
+	}
+	t.Logf("GetPerpDeployAuctionStatus() = %+v", res)
+}
+
+func TestInfoAPI_GetExchangeStatus(t *testing.T) {
+	api := GetInfoAPI()
+	res, err := api.GetExchangeStatus()
+	if err != nil {
+		t.Fatalf("GetExchangeStatus() error = %v", err)
+	}
+	if !res.IsNormal() {
+		t.Logf("exchange special statuses: %s", res.SpecialStatuses)
+	}
+	t.Logf("GetExchangeStatus() = %+v", res)
+}
+
+func TestInfoAPI_ExchangeMaintenance(t *testing.T) {
+	testCases := []struct {
+		name        string
+		status      int
+		body        string
+		maintenance bool
+	}{
+		{name: "ServiceUnavailable", status: http.StatusServiceUnavailable, body: "", maintenance: true},
+		{name: "MaintenanceBody", status: http.StatusBadGateway, body: "Exchange under scheduled maintenance", maintenance: true},
+		{name: "BadRequest", status: http.StatusBadRequest, body: "Invalid request", maintenance: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+			api := &InfoAPI{Client: *NewClient(false), baseEndpoint: "/info"}
+			api.baseURL = server.URL
+			_, err := api.GetExchangeStatus()
+			if err == nil {
+				t.Fatalf("GetExchangeStatus() expected error")
+			}
+			if errors.Is(err, ErrExchangeMaintenance) != tc.maintenance {
+				t.Errorf("errors.Is(%v, ErrExchangeMaintenance) = %v, want %v", err, !tc.maintenance, tc.maintenance)
+			}
+		})
+	}
+}
+
+func TestInfoAPI_GetMartketPxPerpDex(t *testing.T) {
+	api := GetTestInfoAPI(t, func(req InfoRequest) any {
+		if req.Type != "allMids" || req.Dex != "xyz" {
+			t.Errorf("unexpected request %+v", req)
+		}
+		return map[string]string{"xyz:AAA": "12.5"}
+	})
+	px, err := api.GetMartketPx("xyz:AAA")
+	if err != nil {
+		t.Fatalf("GetMartketPx() error = %v", err)
+	}
+	if px != 12.5 {
+		t.Errorf("GetMartketPx() = %v, want 12.5", px)
+	}
+}
+
+func TestInfoAPI_GetL2BookSnapshotAggregated(t *testing.T) {
+	api := GetTestInfoAPI(t, func(req InfoRequest) any {
+		if req.NSigFigs == nil || *req.NSigFigs != 5 || req.Mantissa == nil || *req.Mantissa != 2 {
+			t.Errorf("unexpected request %+v", req)
+		}
+		return map[string]any{"coin": req.Coin, "time": 1, "levels": [][]any{{}, {}}}
+	})
+	nSigFigs, mantissa := 5, 2
+	if _, err := api.GetL2BookSnapshot("BTC", L2BookOptions{NSigFigs: &nSigFigs, Mantissa: &mantissa}); err != nil {
+		t.Errorf("GetL2BookSnapshot() error = %v", err)
+	}
+	nSigFigs = 3
+	if _, err := api.GetL2BookSnapshot("BTC", L2BookOptions{NSigFigs: &nSigFigs, Mantissa: &mantissa}); err == nil {
+		t.Errorf("GetL2BookSnapshot() expected mantissa error")
+	}
+	nSigFigs = 6
+	if _, err := api.GetL2BookSnapshot("BTC", L2BookOptions{NSigFigs: &nSigFigs}); err == nil {
+		t.Errorf("GetL2BookSnapshot() expected nSigFigs error")
+	}
+}
+
+func TestInfoAPI_UnmarshalSpotMetaAndAssetCtxs(t *testing.T) {
+	data := `[{"universe":[{"tokens":[1,0],"name":"PURR/USDC","index":0,"isCanonical":true},{"tokens":[2,0],"name":"@1","index":1,"isCanonical":false}],"tokens":[{"name":"USDC","szDecimals":8,"weiDecimals":8,"index":0},{"name":"PURR","szDecimals":0,"weiDecimals":5,"index":1},{"name":"HFUN","szDecimals":2,"weiDecimals":8,"index":2}]},[
+		{"prevDayPx":"0.2","dayNtlVlm":"1000.5","markPx":"0.21","midPx":"0.2105","circulatingSupply":"600000000.0","coin":"PURR/USDC","totalSupply":"600000000.0","dayBaseVlm":"5000.0"},
+		{"prevDayPx":"10.0","dayNtlVlm":"0.0","markPx":"10.0","midPx":null,"circulatingSupply":"1000.0","coin":"@1","totalSupply":"1000.0","dayBaseVlm":"0.0"}
+	]]`
+	var res SpotMetaAndAssetCtxs
+	if err := json.Unmarshal([]byte(data), &res); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(res.SpotMeta.Tokens) != 3 || len(res.SpotMeta.Universe) != 2 {
+		t.Errorf("SpotMeta = %+v", res.SpotMeta)
+	}
+	purr, ok := res.AssetCtx("PURR/USDC")
+	if !ok || purr.MarkPx != 0.21 || purr.MidPx == nil || *purr.MidPx != 0.2105 || purr.DayNtlVlm != 1000.5 {
+		t.Errorf("AssetCtx(PURR/USDC) = %+v", purr)
+	}
+	if ctx, ok := res.AssetCtx("@1"); !ok || ctx.MidPx != nil {
+		t.Errorf("AssetCtx(@1) = %+v", ctx)
+	}
+}
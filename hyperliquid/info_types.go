@@ -1,18 +1,32 @@
 package hyperliquid
 
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 // Base request for /info
 type InfoRequest struct {
-	User      string `json:"user,omitempty"`
-	Type      string `json:"type"`
-	Oid       string `json:"oid,omitempty"`
-	Coin      string `json:"coin,omitempty"`
-	StartTime int64  `json:"startTime,omitempty"`
-	EndTime   int64  `json:"endTime,omitempty"`
+	User         string `json:"user,omitempty"`
+	Type         string `json:"type"`
+	Oid          string `json:"oid,omitempty"`
+	Coin         string `json:"coin,omitempty"`
+	StartTime    int64  `json:"startTime,omitempty"`
+	EndTime      int64  `json:"endTime,omitempty"`
+	VaultAddress string `json:"vaultAddress,omitempty"`
+	TokenID      string `json:"tokenId,omitempty"`
+	Builder      string `json:"builder,omitempty"`
+	Dex          string `json:"dex,omitempty"`
+	NSigFigs     *int   `json:"nSigFigs,omitempty"`
+	Mantissa     *int   `json:"mantissa,omitempty"`
 }
 
 type UserStateRequest struct {
 	User string `json:"user"`
 	Type string `json:"type"`
+	Dex  string `json:"dex,omitempty"`
 }
 
 type Role string
@@ -94,7 +108,7 @@ type SpotAssetPosition struct {
 }
 
 type Order struct {
-	Children         []any   `json:"children,omitempty"`
+	Children         []Order `json:"children,omitempty"` // TP/SL orders attached to the order
 	Cloid            string  `json:"cloid,omitempty"`
 	Coin             string  `json:"coin"`
 	IsPositionTpsl   bool    `json:"isPositionTpsl,omitempty"`
@@ -112,6 +126,21 @@ type Order struct {
 	TriggerPx        float64 `json:"triggerPx,string,omitempty"`
 }
 
+// HistoricalOrder is an order with its latest status.
+// The same format is used by the orderUpdates websocket stream.
+type HistoricalOrder struct {
+	Order           Order  `json:"order"`
+	Status          string `json:"status"`
+	StatusTimestamp int64  `json:"statusTimestamp"`
+}
+
+// IsFinal returns true if the order can no longer change,
+// i.e. it was filled, canceled or rejected.
+// "open" and "triggered" orders may still be filled.
+func (o *HistoricalOrder) IsFinal() bool {
+	return o.Status != "" && o.Status != "open" && o.Status != "triggered"
+}
+
 type Leverage struct {
 	Type  string `json:"type"`
 	Value int    `json:"value"`
@@ -143,6 +172,55 @@ type SpotMeta struct {
 	} `json:"tokens"`
 }
 
+// SpotPair is a spot trading pair resolved from the spot meta.
+type SpotPair struct {
+	Name        string // Human name of the pair, e.g. "HYPE/USDC"
+	Coin        string // Name of the pair in the API, e.g. "@107" or "PURR/USDC"
+	Index       int
+	Base        string
+	Quote       string
+	SzDecimals  int // Size decimals of the base token
+	WeiDecimals int // Wei decimals of the base token
+}
+
+// AssetID returns the asset id of the pair in orders, the pair index with the 10000 spot offset.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/asset-ids
+func (pair SpotPair) AssetID() int {
+	return pair.Index + 10000
+}
+
+// Pair resolves a spot pair by its human name ("HYPE/USDC", case insensitive)
+// or by its name in the API ("@107", "PURR/USDC").
+func (m *SpotMeta) Pair(name string) (SpotPair, bool) {
+	tokens := make(map[int]int, len(m.Tokens))
+	for i, token := range m.Tokens {
+		tokens[token.Index] = i
+	}
+	for _, universe := range m.Universe {
+		if len(universe.Tokens) != 2 {
+			continue
+		}
+		base, okBase := tokens[universe.Tokens[0]]
+		quote, okQuote := tokens[universe.Tokens[1]]
+		if !okBase || !okQuote {
+			continue
+		}
+		pair := SpotPair{
+			Name:        m.Tokens[base].Name + "/" + m.Tokens[quote].Name,
+			Coin:        universe.Name,
+			Index:       universe.Index,
+			Base:        m.Tokens[base].Name,
+			Quote:       m.Tokens[quote].Name,
+			SzDecimals:  m.Tokens[base].SzDecimals,
+			WeiDecimals: m.Tokens[base].WeiDecimals,
+		}
+		if universe.Name == name || strings.EqualFold(pair.Name, name) {
+			return pair, true
+		}
+	}
+	return SpotPair{}, false
+}
+
 type Meta struct {
 	Universe []Asset `json:"universe"`
 }
@@ -185,14 +263,24 @@ type HistoricalFundingRate struct {
 	Time        int64  `json:"time"`
 }
 
+// L2BookOptions aggregates the levels of an L2 book snapshot.
+// NSigFigs rounds the prices to 2-5 significant figures, nil for full precision.
+// Mantissa (1, 2 or 5) is only allowed with NSigFigs 5.
+type L2BookOptions struct {
+	NSigFigs *int
+	Mantissa *int
+}
+
 type L2BookSnapshot struct {
-	Coin   string `json:"coin"`
-	Time   int64  `json:"time"`
-	Levels [][]struct {
-		Px float64 `json:"px,string"`
-		Sz float64 `json:"sz,string"`
-		N  int     `json:"n"`
-	} `json:"levels"`
+	Coin   string      `json:"coin"`
+	Time   int64       `json:"time"`
+	Levels [][]L2Level `json:"levels"`
+}
+
+type L2Level struct {
+	Px float64 `json:"px,string"`
+	Sz float64 `json:"sz,string"`
+	N  int     `json:"n"`
 }
 
 type CandleSnapshotSubRequest struct {
@@ -238,8 +326,14 @@ type RatesLimits struct {
 	NRequestsCap  int     `json:"nRequestsCap"`
 }
 
+// SpotMetaAndAssetCtxsResponse is the raw response of the spotMetaAndAssetCtxs info type.
+//
+// Deprecated: use SpotMetaAndAssetCtxs
 type SpotMetaAndAssetCtxsResponse [2]interface{} // Array of exactly 2 elements
 
+// Market is the market context of a spot pair with the values as strings.
+//
+// Deprecated: use SpotMetaAndAssetCtxs
 type Market struct {
 	PrevDayPx         string `json:"prevDayPx,omitempty"`
 	DayNtlVlm         string `json:"dayNtlVlm,omitempty"`
@@ -250,3 +344,545 @@ type Market struct {
 	TotalSupply       string `json:"totalSupply,omitempty"`
 	DayBaseVlm        string `json:"dayBaseVlm,omitempty"`
 }
+
+// SpotAssetCtx is the market context of a spot pair.
+// Coin is the name of the pair (e.g. "PURR/USDC" or "@107").
+type SpotAssetCtx struct {
+	Coin              string   `json:"coin"`
+	PrevDayPx         float64  `json:"prevDayPx,string"`
+	DayNtlVlm         float64  `json:"dayNtlVlm,string"`
+	DayBaseVlm        float64  `json:"dayBaseVlm,string"`
+	MarkPx            float64  `json:"markPx,string"`
+	MidPx             *float64 `json:"midPx,string"` // nil if one side of the book is empty
+	CirculatingSupply float64  `json:"circulatingSupply,string"`
+	TotalSupply       float64  `json:"totalSupply,string"`
+}
+
+// SpotMetaAndAssetCtxs is the response of the spotMetaAndAssetCtxs info type.
+type SpotMetaAndAssetCtxs struct {
+	SpotMeta  SpotMeta
+	AssetCtxs []SpotAssetCtx
+}
+
+// UnmarshalJSON implements custom unmarshaling for SpotMetaAndAssetCtxs
+// from its [spotMeta, assetCtxs] array representation.
+func (m *SpotMetaAndAssetCtxs) UnmarshalJSON(data []byte) error {
+	var raw [2]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("SpotMetaAndAssetCtxs: %w", err)
+	}
+	if err := json.Unmarshal(raw[0], &m.SpotMeta); err != nil {
+		return fmt.Errorf("SpotMetaAndAssetCtxs: invalid spot meta: %w", err)
+	}
+	if err := json.Unmarshal(raw[1], &m.AssetCtxs); err != nil {
+		return fmt.Errorf("SpotMetaAndAssetCtxs: invalid asset contexts: %w", err)
+	}
+	return nil
+}
+
+// AssetCtx returns the context of the given spot pair (e.g. "@107").
+// Returns false if there is no context for the pair.
+func (m *SpotMetaAndAssetCtxs) AssetCtx(coin string) (SpotAssetCtx, bool) {
+	for _, ctx := range m.AssetCtxs {
+		if ctx.Coin == coin {
+			return ctx, true
+		}
+	}
+	return SpotAssetCtx{}, false
+}
+
+// OrderStatusRequest is the request of the orderStatus info type.
+// Oid is either the order id (int) or the client order id (hex string).
+type OrderStatusRequest struct {
+	User string `json:"user"`
+	Type string `json:"type"`
+	Oid  any    `json:"oid"`
+}
+
+// OrderStatusResponse is the response of the orderStatus info type.
+// Status is "order" if the order was found and "unknownOid" otherwise.
+// Order.Status is one of "open", "filled", "canceled", "triggered", "rejected", "marginCanceled", ...
+type OrderStatusResponse struct {
+	Status string           `json:"status"`
+	Order  *HistoricalOrder `json:"order,omitempty"`
+}
+
+// IsFound returns true if the order exists.
+func (r *OrderStatusResponse) IsFound() bool {
+	return r.Status == "order" && r.Order != nil
+}
+
+// TwapSliceFill is a fill of a single slice of a TWAP order.
+type TwapSliceFill struct {
+	Fill   OrderFill `json:"fill"`
+	TwapID int64     `json:"twapId"`
+}
+
+// HistoryPoint is a single [time, value] point of a time series.
+//
+//	[1700000000000, "1234.5"]
+type HistoryPoint struct {
+	Time  int64
+	Value float64
+}
+
+// UnmarshalJSON implements custom unmarshaling for HistoryPoint
+// from its [time, "value"] array representation.
+func (p *HistoryPoint) UnmarshalJSON(data []byte) error {
+	var raw [2]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("HistoryPoint: %w", err)
+	}
+	if err := json.Unmarshal(raw[0], &p.Time); err != nil {
+		return fmt.Errorf("HistoryPoint: invalid time: %w", err)
+	}
+	var value string
+	if err := json.Unmarshal(raw[1], &value); err != nil {
+		return fmt.Errorf("HistoryPoint: invalid value: %w", err)
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("HistoryPoint: invalid value: %w", err)
+	}
+	p.Value = parsed
+	return nil
+}
+
+// PortfolioPeriod is the account value and PnL history over a period.
+type PortfolioPeriod struct {
+	AccountValueHistory []HistoryPoint `json:"accountValueHistory"`
+	PnlHistory          []HistoryPoint `json:"pnlHistory"`
+	Vlm                 float64        `json:"vlm,string"`
+}
+
+// Portfolio maps a period ("day", "week", "month", "allTime", "perpDay", ...) to its history.
+type Portfolio map[string]PortfolioPeriod
+
+// UnmarshalJSON implements custom unmarshaling for Portfolio
+// from its [["day", {...}], ["week", {...}], ...] array representation.
+func (p *Portfolio) UnmarshalJSON(data []byte) error {
+	portfolio, err := unmarshalTuples[PortfolioPeriod](data)
+	if err != nil {
+		return fmt.Errorf("Portfolio: %w", err)
+	}
+	*p = portfolio
+	return nil
+}
+
+// unmarshalTuples decodes the [[key, value], ...] array representation of a map.
+// Entries with a null value are left out of the map.
+func unmarshalTuples[V any](data []byte) (map[string]V, error) {
+	var raw [][2]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	values := make(map[string]V, len(raw))
+	for _, item := range raw {
+		var key string
+		if err := json.Unmarshal(item[0], &key); err != nil {
+			return nil, fmt.Errorf("invalid key: %w", err)
+		}
+		var value *V
+		if err := json.Unmarshal(item[1], &value); err != nil {
+			return nil, fmt.Errorf("invalid %s value: %w", key, err)
+		}
+		if value != nil {
+			values[key] = *value
+		}
+	}
+	return values, nil
+}
+
+// VaultFollower is a depositor of a vault.
+type VaultFollower struct {
+	User           string  `json:"user"`
+	VaultEquity    float64 `json:"vaultEquity,string"`
+	Pnl            float64 `json:"pnl,string"`
+	AllTimePnl     float64 `json:"allTimePnl,string"`
+	DaysFollowing  int     `json:"daysFollowing"`
+	VaultEntryTime int64   `json:"vaultEntryTime"`
+	LockupUntil    int64   `json:"lockupUntil"`
+}
+
+// VaultRelationship describes how a vault is related to other vaults.
+// Type is "normal", "parent" or "child".
+type VaultRelationship struct {
+	Type string `json:"type"`
+	Data struct {
+		ChildAddresses []string `json:"childAddresses,omitempty"`
+		ParentAddress  string   `json:"parentAddress,omitempty"`
+	} `json:"data,omitempty"`
+}
+
+type VaultDetails struct {
+	Name                  string            `json:"name"`
+	VaultAddress          string            `json:"vaultAddress"`
+	Leader                string            `json:"leader"`
+	Description           string            `json:"description"`
+	Portfolio             Portfolio         `json:"portfolio"`
+	Apr                   float64           `json:"apr"`
+	FollowerState         *VaultFollower    `json:"followerState"` // Set if the user is a follower of the vault
+	LeaderFraction        float64           `json:"leaderFraction"`
+	LeaderCommission      float64           `json:"leaderCommission"`
+	Followers             []VaultFollower   `json:"followers"`
+	MaxDistributable      float64           `json:"maxDistributable"`
+	MaxWithdrawable       float64           `json:"maxWithdrawable"`
+	IsClosed              bool              `json:"isClosed"`
+	Relationship          VaultRelationship `json:"relationship"`
+	AllowDeposits         bool              `json:"allowDeposits"`
+	AlwaysCloseOnWithdraw bool              `json:"alwaysCloseOnWithdraw"`
+}
+
+// VaultEquity is a user's deposit in a vault.
+type VaultEquity struct {
+	VaultAddress         string  `json:"vaultAddress"`
+	Equity               float64 `json:"equity,string"`
+	LockedUntilTimestamp int64   `json:"lockedUntilTimestamp"`
+}
+
+// IsLocked returns true if the deposit can not be withdrawn yet at the given time in milliseconds.
+func (e VaultEquity) IsLocked(now int64) bool {
+	return e.LockedUntilTimestamp > now
+}
+
+type VaultSummary struct {
+	Name             string            `json:"name"`
+	VaultAddress     string            `json:"vaultAddress"`
+	Leader           string            `json:"leader"`
+	Tvl              float64           `json:"tvl,string"`
+	IsClosed         bool              `json:"isClosed"`
+	Relationship     VaultRelationship `json:"relationship"`
+	CreateTimeMillis int64             `json:"createTimeMillis"`
+}
+
+type LeadingVault struct {
+	Address string `json:"address"`
+	Name    string `json:"name"`
+}
+
+type ReferralState struct {
+	ReferredBy *struct {
+		Referrer string `json:"referrer"`
+		Code     string `json:"code"`
+	} `json:"referredBy"`
+	CumVlm           float64         `json:"cumVlm,string"`
+	UnclaimedRewards float64         `json:"unclaimedRewards,string"`
+	ClaimedRewards   float64         `json:"claimedRewards,string"`
+	BuilderRewards   float64         `json:"builderRewards,string"`
+	ReferrerState    ReferrerState   `json:"referrerState"`
+	RewardHistory    json.RawMessage `json:"rewardHistory"`
+}
+
+// ReferrerState is the state of the user as a referrer.
+// Stage is one of "ready", "needToCreateCode" or "needToTrade",
+// Code and ReferralStates are only set once the stage is "ready".
+type ReferrerState struct {
+	Stage string `json:"stage"`
+	Data  struct {
+		Code           string          `json:"code"`
+		ReferralStates []ReferredState `json:"referralStates"`
+	} `json:"data"`
+}
+
+type ReferredState struct {
+	User                         string  `json:"user"`
+	CumVlm                       float64 `json:"cumVlm,string"`
+	CumRewardedFeesSinceReferred float64 `json:"cumRewardedFeesSinceReferred,string"`
+	CumFeesRewardedToReferrer    float64 `json:"cumFeesRewardedToReferrer,string"`
+	TimeJoined                   int64   `json:"timeJoined"`
+}
+
+// UserFees holds the fee schedule of the exchange and the rates applied to the user.
+// The User*Rate fields already include the volume tier and the active discounts.
+type UserFees struct {
+	DailyUserVlm           []DailyUserVlm  `json:"dailyUserVlm"`
+	FeeSchedule            FeeSchedule     `json:"feeSchedule"`
+	UserCrossRate          float64         `json:"userCrossRate,string"`
+	UserAddRate            float64         `json:"userAddRate,string"`
+	UserSpotCrossRate      float64         `json:"userSpotCrossRate,string"`
+	UserSpotAddRate        float64         `json:"userSpotAddRate,string"`
+	ActiveReferralDiscount float64         `json:"activeReferralDiscount,string"`
+	ActiveStakingDiscount  StakingDiscount `json:"activeStakingDiscount"`
+}
+
+type DailyUserVlm struct {
+	Date      string  `json:"date"`
+	UserCross float64 `json:"userCross,string"`
+	UserAdd   float64 `json:"userAdd,string"`
+	Exchange  float64 `json:"exchange,string"`
+}
+
+type FeeSchedule struct {
+	Cross     float64 `json:"cross,string"`
+	Add       float64 `json:"add,string"`
+	SpotCross float64 `json:"spotCross,string"`
+	SpotAdd   float64 `json:"spotAdd,string"`
+	Tiers     struct {
+		Vip []VipFeeTier `json:"vip"`
+		Mm  []MmFeeTier  `json:"mm"`
+	} `json:"tiers"`
+	ReferralDiscount     float64           `json:"referralDiscount,string"`
+	StakingDiscountTiers []StakingDiscount `json:"stakingDiscountTiers"`
+}
+
+type VipFeeTier struct {
+	NtlCutoff float64 `json:"ntlCutoff,string"`
+	Cross     float64 `json:"cross,string"`
+	Add       float64 `json:"add,string"`
+	SpotCross float64 `json:"spotCross,string"`
+	SpotAdd   float64 `json:"spotAdd,string"`
+}
+
+type MmFeeTier struct {
+	MakerFractionCutoff float64 `json:"makerFractionCutoff,string"`
+	Add                 float64 `json:"add,string"`
+}
+
+type StakingDiscount struct {
+	BpsOfMaxSupply float64 `json:"bpsOfMaxSupply,string"`
+	Discount       float64 `json:"discount,string"`
+}
+
+type Delegation struct {
+	Validator            string  `json:"validator"`
+	Amount               float64 `json:"amount,string"`
+	LockedUntilTimestamp int64   `json:"lockedUntilTimestamp"`
+}
+
+type DelegatorSummary struct {
+	Delegated              float64 `json:"delegated,string"`
+	Undelegated            float64 `json:"undelegated,string"`
+	TotalPendingWithdrawal float64 `json:"totalPendingWithdrawal,string"`
+	NPendingWithdrawals    int     `json:"nPendingWithdrawals"`
+}
+
+// DelegatorEvent is a staking event of a user.
+// Exactly one of the Delta fields is set.
+type DelegatorEvent struct {
+	Time  int64  `json:"time"`
+	Hash  string `json:"hash"`
+	Delta struct {
+		Delegate *struct {
+			Validator    string  `json:"validator"`
+			Amount       float64 `json:"amount,string"`
+			IsUndelegate bool    `json:"isUndelegate"`
+		} `json:"delegate,omitempty"`
+		CDeposit *struct {
+			Amount float64 `json:"amount,string"`
+		} `json:"cDeposit,omitempty"`
+		Withdrawal *struct {
+			Amount float64 `json:"amount,string"`
+			Phase  string  `json:"phase"`
+		} `json:"withdrawal,omitempty"`
+	} `json:"delta"`
+}
+
+type DelegatorReward struct {
+	Time        int64   `json:"time"`
+	Source      string  `json:"source"` // "delegation" or "commission"
+	TotalAmount float64 `json:"totalAmount,string"`
+}
+
+type ValidatorSummary struct {
+	Validator       string         `json:"validator"`
+	Signer          string         `json:"signer"`
+	Name            string         `json:"name"`
+	Description     string         `json:"description"`
+	NRecentBlocks   int            `json:"nRecentBlocks"`
+	Stake           int64          `json:"stake"`
+	IsJailed        bool           `json:"isJailed"`
+	UnjailableAfter *int64         `json:"unjailableAfter"`
+	IsActive        bool           `json:"isActive"`
+	Commission      float64        `json:"commission,string"`
+	Stats           ValidatorStats `json:"stats"`
+}
+
+type ValidatorPeriodStats struct {
+	UptimeFraction float64 `json:"uptimeFraction,string"`
+	PredictedApr   float64 `json:"predictedApr,string"`
+	NSamples       int     `json:"nSamples"`
+}
+
+// ValidatorStats maps a period ("day", "week", "month") to the validator stats.
+type ValidatorStats map[string]ValidatorPeriodStats
+
+// UnmarshalJSON implements custom unmarshaling for ValidatorStats
+// from its [["day", {...}], ["week", {...}], ...] array representation.
+func (s *ValidatorStats) UnmarshalJSON(data []byte) error {
+	stats, err := unmarshalTuples[ValidatorPeriodStats](data)
+	if err != nil {
+		return fmt.Errorf("ValidatorStats: %w", err)
+	}
+	*s = stats
+	return nil
+}
+
+type PredictedFunding struct {
+	FundingRate          float64 `json:"fundingRate,string"`
+	NextFundingTime      int64   `json:"nextFundingTime"`
+	FundingIntervalHours int     `json:"fundingIntervalHours,omitempty"`
+}
+
+// PredictedFundings maps a coin to the predicted funding of each venue
+// ("HlPerp", "BinPerp", "BybitPerp"). Venues not listing the coin are omitted.
+type PredictedFundings map[string]map[string]PredictedFunding
+
+// UnmarshalJSON implements custom unmarshaling for PredictedFundings
+// from its [["BTC", [["BinPerp", {...}], ["HlPerp", {...}]]], ...] array representation.
+func (p *PredictedFundings) UnmarshalJSON(data []byte) error {
+	coins, err := unmarshalTuples[json.RawMessage](data)
+	if err != nil {
+		return fmt.Errorf("PredictedFundings: %w", err)
+	}
+	fundings := make(PredictedFundings, len(coins))
+	for coin, venues := range coins {
+		if fundings[coin], err = unmarshalTuples[PredictedFunding](venues); err != nil {
+			return fmt.Errorf("PredictedFundings: invalid %s venues: %w", coin, err)
+		}
+	}
+	*p = fundings
+	return nil
+}
+
+// GasAuction is the state of a deploy gas auction.
+// The gas price decreases from StartGas to EndGas over the duration of the auction.
+type GasAuction struct {
+	StartTimeSeconds int64    `json:"startTimeSeconds"`
+	DurationSeconds  int64    `json:"durationSeconds"`
+	StartGas         float64  `json:"startGas,string"`
+	CurrentGas       *float64 `json:"currentGas,string"` // nil once the auction has ended
+	EndGas           *float64 `json:"endGas,string"`     // nil until the auction has ended
+}
+
+type SpotDeployTokenState struct {
+	Token int `json:"token"`
+	Spec  struct {
+		Name        string `json:"name"`
+		SzDecimals  int    `json:"szDecimals"`
+		WeiDecimals int    `json:"weiDecimals"`
+	} `json:"spec"`
+	FullName                     string      `json:"fullName"`
+	Spots                        []int       `json:"spots"`
+	MaxSupply                    *int64      `json:"maxSupply"`
+	HyperliquidityGenesisBalance float64     `json:"hyperliquidityGenesisBalance,string"`
+	TotalGenesisBalanceWei       string      `json:"totalGenesisBalanceWei"`
+	UserGenesisBalances          [][2]string `json:"userGenesisBalances"`          // [user, balance]
+	ExistingTokenGenesisBalances [][2]any    `json:"existingTokenGenesisBalances"` // [token, balance]
+}
+
+type SpotDeployState struct {
+	States     []SpotDeployTokenState `json:"states"`
+	GasAuction GasAuction             `json:"gasAuction"`
+}
+
+type TokenDetails struct {
+	Name              string  `json:"name"`
+	MaxSupply         float64 `json:"maxSupply,string"`
+	TotalSupply       float64 `json:"totalSupply,string"`
+	CirculatingSupply float64 `json:"circulatingSupply,string"`
+	SzDecimals        int     `json:"szDecimals"`
+	WeiDecimals       int     `json:"weiDecimals"`
+	MidPx             float64 `json:"midPx,string"`
+	MarkPx            float64 `json:"markPx,string"`
+	PrevDayPx         float64 `json:"prevDayPx,string"`
+	Genesis           *struct {
+		UserBalances          [][2]string `json:"userBalances"`          // [user, balance]
+		ExistingTokenBalances [][2]any    `json:"existingTokenBalances"` // [token, balance]
+		BlacklistUsers        []string    `json:"blacklistUsers"`
+	} `json:"genesis"`
+	Deployer                   string      `json:"deployer"`
+	DeployGas                  float64     `json:"deployGas,string"`
+	DeployTime                 string      `json:"deployTime"`
+	SeededUsdc                 float64     `json:"seededUsdc,string"`
+	NonCirculatingUserBalances [][2]string `json:"nonCirculatingUserBalances"` // [user, balance]
+	FutureEmissions            float64     `json:"futureEmissions,string"`
+	EvmContract                *struct {
+		Address             string `json:"address"`
+		EvmExtraWeiDecimals int    `json:"evm_extra_wei_decimals"`
+	} `json:"evmContract"` // nil if the token is not linked to a HyperEVM contract
+}
+
+// PerpAssetCtx is the market context of a perpetual asset.
+type PerpAssetCtx struct {
+	Funding      float64  `json:"funding,string"`
+	OpenInterest float64  `json:"openInterest,string"`
+	PrevDayPx    float64  `json:"prevDayPx,string"`
+	DayNtlVlm    float64  `json:"dayNtlVlm,string"`
+	DayBaseVlm   float64  `json:"dayBaseVlm,string"`
+	Premium      *float64 `json:"premium,string"` // nil if there is no premium (e.g. empty book)
+	OraclePx     float64  `json:"oraclePx,string"`
+	MarkPx       float64  `json:"markPx,string"`
+	MidPx        *float64 `json:"midPx,string"` // nil if one side of the book is empty
+	ImpactPxs    []string `json:"impactPxs"`
+}
+
+// MetaAndAssetCtxs is the response of the metaAndAssetCtxs info type.
+// AssetCtxs[i] is the context of Meta.Universe[i].
+type MetaAndAssetCtxs struct {
+	Meta      Meta
+	AssetCtxs []PerpAssetCtx
+}
+
+// UnmarshalJSON implements custom unmarshaling for MetaAndAssetCtxs
+// from its [meta, assetCtxs] array representation.
+func (m *MetaAndAssetCtxs) UnmarshalJSON(data []byte) error {
+	var raw [2]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("MetaAndAssetCtxs: %w", err)
+	}
+	if err := json.Unmarshal(raw[0], &m.Meta); err != nil {
+		return fmt.Errorf("MetaAndAssetCtxs: invalid meta: %w", err)
+	}
+	if err := json.Unmarshal(raw[1], &m.AssetCtxs); err != nil {
+		return fmt.Errorf("MetaAndAssetCtxs: invalid asset contexts: %w", err)
+	}
+	if len(m.AssetCtxs) != len(m.Meta.Universe) {
+		return fmt.Errorf("MetaAndAssetCtxs: %d asset contexts for %d assets", len(m.AssetCtxs), len(m.Meta.Universe))
+	}
+	return nil
+}
+
+// AssetCtx returns the context of the given coin.
+// Returns false if the coin is not in the meta universe.
+func (m *MetaAndAssetCtxs) AssetCtx(coin string) (PerpAssetCtx, bool) {
+	for i, asset := range m.Meta.Universe {
+		if asset.Name == coin {
+			return m.AssetCtxs[i], true
+		}
+	}
+	return PerpAssetCtx{}, false
+}
+
+// ExtraAgent is a named agent (API wallet) approved by a user.
+type ExtraAgent struct {
+	Name       string `json:"name"`
+	Address    string `json:"address"`
+	ValidUntil int64  `json:"validUntil"` // Expiry in milliseconds
+}
+
+// MultiSigSigners are the users authorized to sign for a multi-sig user.
+// Threshold is the number of signatures required for an action.
+type MultiSigSigners struct {
+	AuthorizedUsers []string `json:"authorizedUsers"`
+	Threshold       int      `json:"threshold"`
+}
+
+// PerpDex is a perp dex deployed by a builder.
+type PerpDex struct {
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	Deployer      string `json:"deployer"`
+	OracleUpdater string `json:"oracle_updater"`
+}
+
+// ExchangeStatus is the status of the exchange.
+// SpecialStatuses is null during normal operation.
+type ExchangeStatus struct {
+	Time            int64           `json:"time"`
+	SpecialStatuses json.RawMessage `json:"specialStatuses"`
+}
+
+// IsNormal reports whether the exchange operates normally.
+func (s *ExchangeStatus) IsNormal() bool {
+	return len(s.SpecialStatuses) == 0 || string(s.SpecialStatuses) == "null"
+}
@@ -0,0 +1,152 @@
+package hyperliquid
+
+import (
+	"fmt"
+	"sync"
+)
+
+// OCOOrder is a pair of orders where the execution of one cancels the other (one-cancels-other).
+// The exchange has no native OCO outside of position TP/SL, so the fills of the account
+// are watched over the websocket and the sibling order is canceled on the first fill.
+//
+//	oco, err := api.PlaceOCO(ws, takeProfit, stopLoss)
+//	<-oco.Done()
+//	filledOid, err := oco.Result()
+type OCOOrder struct {
+	api       *ExchangeAPI
+	events    *AccountEvents
+	orders    [2]OrderRequest
+	oids      [2]int
+	filledOid int
+	err       error
+	done      chan struct{}
+	stop      chan struct{}
+	once      sync.Once
+	mu        sync.Mutex
+}
+
+// PlaceOCO places two orders and cancels the remaining one as soon as the other one is (partially) filled.
+// The fills are received from the account events of ws, which must be connected.
+// The orders are watched for the vault address if one is set, otherwise for the account address.
+func (api *ExchangeAPI) PlaceOCO(ws *WebSocketAPI, first OrderRequest, second OrderRequest) (*OCOOrder, error) {
+	user := api.VaultAddress()
+	if user == "" {
+		user = api.AccountAddress()
+	}
+	// Subscribe before placing the orders so no fill is missed
+	events, err := ws.SubscribeAccountEvents(user)
+	if err != nil {
+		return nil, err
+	}
+	oco := &OCOOrder{
+		api:    api,
+		events: events,
+		orders: [2]OrderRequest{first, second},
+		done:   make(chan struct{}),
+		stop:   make(chan struct{}),
+	}
+	res, err := api.BulkOrders(oco.orders[:], GroupingNa)
+	if err != nil {
+		events.Close()
+		return nil, err
+	}
+	statuses := res.Response.Data.Statuses
+	if len(statuses) != 2 {
+		events.Close()
+		return nil, APIError{Message: fmt.Sprintf("Unexpected OCO order statuses: %+v", statuses)}
+	}
+	for i, status := range statuses {
+		oco.oids[i] = status.OrderID()
+	}
+	for i, status := range statuses {
+		if status.Error != "" {
+			// Don't leave a single order of the pair resting
+			if sibling := oco.oids[1-i]; sibling != 0 && statuses[1-i].Filled.OrderID == 0 {
+				api.CancelOrderByOID(oco.orders[1-i].Coin, sibling)
+			}
+			events.Close()
+			return nil, status.Err()
+		}
+	}
+	go oco.run(statuses)
+	return oco, nil
+}
+
+// Oids returns the ids of the two orders.
+func (oco *OCOOrder) Oids() (int, int) {
+	return oco.oids[0], oco.oids[1]
+}
+
+// Done is closed once one of the orders was filled and the other one canceled,
+// or after Cancel or Close.
+func (oco *OCOOrder) Done() <-chan struct{} {
+	return oco.done
+}
+
+// Result returns the id of the filled order, 0 if no order was filled,
+// and the error of the cancel of the sibling order if any.
+func (oco *OCOOrder) Result() (int, error) {
+	oco.mu.Lock()
+	defer oco.mu.Unlock()
+	return oco.filledOid, oco.err
+}
+
+// Cancel cancels both orders and stops watching them.
+func (oco *OCOOrder) Cancel() error {
+	var err error
+	for i, oid := range oco.oids {
+		if _, cerr := oco.api.CancelOrderByOID(oco.orders[i].Coin, oid); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	oco.Close()
+	return err
+}
+
+// Close stops watching the orders without canceling them.
+func (oco *OCOOrder) Close() error {
+	oco.once.Do(func() { close(oco.stop) })
+	<-oco.done
+	return nil
+}
+
+// run waits for the first fill of one of the orders and cancels the other one.
+func (oco *OCOOrder) run(statuses []StatusResponse) {
+	defer close(oco.done)
+	defer oco.events.Close()
+	for i, status := range statuses {
+		if status.Filled.OrderID != 0 {
+			oco.fill(i)
+			return
+		}
+	}
+	for {
+		select {
+		case event, ok := <-oco.events.C():
+			if !ok {
+				return
+			}
+			if event.Type != AccountEventFill || event.IsSnapshot {
+				continue
+			}
+			for i, oid := range oco.oids {
+				if event.Fill.Oid == oid {
+					oco.fill(i)
+					return
+				}
+			}
+		case <-oco.stop:
+			return
+		}
+	}
+}
+
+// fill records the fill of the order i and cancels its sibling.
+func (oco *OCOOrder) fill(i int) {
+	sibling := 1 - i
+	_, err := oco.api.CancelOrderByOID(oco.orders[sibling].Coin, oco.oids[sibling])
+	oco.mu.Lock()
+	defer oco.mu.Unlock()
+	oco.filledOid = oco.oids[i]
+	oco.err = err
+}
@@ -0,0 +1,72 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestOCOOrder_CancelSiblingOnFill(t *testing.T) {
+	subscribed := make(chan *websocket.Conn, 4)
+	server := newTestWsServer(t, func(conn *websocket.Conn, req WsRequest) any {
+		if req.Method == "subscribe" {
+			subscribed <- conn
+		}
+		return nil
+	})
+	ws := GetTestWebSocketAPI(t, server)
+	canceled := make(chan CancelOidWire, 1)
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action struct {
+			Type    string          `json:"type"`
+			Cancels []CancelOidWire `json:"cancels"`
+		}
+		json.Unmarshal(req.Action, &action)
+		if action.Type == "cancel" {
+			canceled <- action.Cancels[0]
+			return map[string]any{"status": "ok", "response": map[string]any{"type": "cancel", "data": map[string]any{"statuses": []any{"success"}}}}
+		}
+		statuses := []any{map[string]any{"resting": map[string]any{"oid": 1}}, map[string]any{"resting": map[string]any{"oid": 2}}}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "order", "data": map[string]any{"statuses": statuses}}}
+	})
+	takeProfit := OrderRequest{Coin: "ETH", IsBuy: false, Sz: 0.1, LimitPx: 2200, OrderType: OrderType{Limit: &LimitOrderType{Tif: TifGtc}}, ReduceOnly: true}
+	stopLoss := OrderRequest{Coin: "ETH", IsBuy: false, Sz: 0.1, LimitPx: 1900, ReduceOnly: true,
+		OrderType: OrderType{Trigger: &TriggerOrderType{IsMarket: true, TriggerPx: "1900", TpSl: TriggerSl}}}
+	oco, err := exchangeAPI.PlaceOCO(ws, takeProfit, stopLoss)
+	if err != nil {
+		t.Fatalf("PlaceOCO() error = %v", err)
+	}
+	defer oco.Close()
+	var conn *websocket.Conn
+	for i := 0; i < 4; i++ {
+		conn = <-subscribed
+	}
+	fills := []map[string]any{
+		{"channel": "userFills", "data": map[string]any{"user": exchangeAPI.AccountAddress(), "isSnapshot": true, "fills": []any{
+			map[string]any{"coin": "ETH", "px": "1900", "sz": "0.1", "oid": 1, "tid": 1, "time": 10},
+		}}},
+		{"channel": "userFills", "data": map[string]any{"user": exchangeAPI.AccountAddress(), "fills": []any{
+			map[string]any{"coin": "ETH", "px": "1900", "sz": "0.1", "oid": 3, "tid": 2, "time": 20},
+			map[string]any{"coin": "ETH", "px": "1900", "sz": "0.1", "oid": 2, "tid": 3, "time": 30},
+		}}},
+	}
+	for _, msg := range fills {
+		if err := conn.WriteJSON(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	select {
+	case cancel := <-canceled:
+		if cancel.Oid != 1 || cancel.Asset != 1 {
+			t.Errorf("cancel = %+v, want cancel of oid 1 on asset 1", cancel)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the sibling cancel")
+	}
+	<-oco.Done()
+	if filledOid, err := oco.Result(); filledOid != 2 || err != nil {
+		t.Errorf("Result() = %v, %v, want 2, nil", filledOid, err)
+	}
+}
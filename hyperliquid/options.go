@@ -0,0 +1,141 @@
+package hyperliquid
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Option configures the client created by NewHyperliquid.
+type Option interface {
+	apply(options *clientOptions)
+}
+
+// clientOptions are the settings collected from the options of NewHyperliquid.
+type clientOptions struct {
+	isMainnet      bool
+	privateKey     string
+	accountAddress string
+	baseURL        string
+	timeout        time.Duration
+	httpClient     *http.Client
+	transport      http.RoundTripper
+	logger         *log.Logger
+	retryPolicy    *RetryPolicy
+	rateLimiter    *RateLimiter
+	debug          bool
+}
+
+// optionFunc is an Option applied by a function.
+type optionFunc func(options *clientOptions)
+
+func (f optionFunc) apply(options *clientOptions) {
+	f(options)
+}
+
+// WithMainnet selects the mainnet (true, the default) or the testnet (false).
+func WithMainnet(isMainnet bool) Option {
+	return optionFunc(func(options *clientOptions) {
+		options.isMainnet = isMainnet
+	})
+}
+
+// WithPrivateKey sets the private key signing the exchange requests.
+func WithPrivateKey(privateKey string) Option {
+	return optionFunc(func(options *clientOptions) {
+		options.privateKey = privateKey
+	})
+}
+
+// WithAccountAddress sets the default account address of the API.
+func WithAccountAddress(address string) Option {
+	return optionFunc(func(options *clientOptions) {
+		options.accountAddress = address
+	})
+}
+
+// WithBaseURL overrides the URL of the API of the network, e.g. to use a proxy or a local node.
+func WithBaseURL(baseURL string) Option {
+	return optionFunc(func(options *clientOptions) {
+		options.baseURL = strings.TrimSuffix(baseURL, "/")
+	})
+}
+
+// WithTimeout sets the timeout of the HTTP requests.
+// It applies to a copy of the client set by WithHTTPClient, which is not modified.
+func WithTimeout(timeout time.Duration) Option {
+	return optionFunc(func(options *clientOptions) {
+		options.timeout = timeout
+	})
+}
+
+// WithHTTPClient sets the HTTP client sending the requests, http.DefaultClient by default.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return optionFunc(func(options *clientOptions) {
+		options.httpClient = httpClient
+	})
+}
+
+// WithTransport sets the RoundTripper of the HTTP client, e.g. for a proxy, mTLS or custom DNS resolution.
+// It applies to a copy of the client set by WithHTTPClient, which is not modified.
+func WithTransport(transport http.RoundTripper) Option {
+	return optionFunc(func(options *clientOptions) {
+		options.transport = transport
+	})
+}
+
+// WithLogger sets the logger of the debug messages.
+func WithLogger(logger *log.Logger) Option {
+	return optionFunc(func(options *clientOptions) {
+		options.logger = logger
+	})
+}
+
+// WithRetryPolicy retries the failed requests according to policy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return optionFunc(func(options *clientOptions) {
+		options.retryPolicy = &policy
+	})
+}
+
+// WithRateLimiter limits all the requests with limiter, see NewRateLimiter.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return optionFunc(func(options *clientOptions) {
+		options.rateLimiter = limiter
+	})
+}
+
+// WithDebug enables the debug messages.
+func WithDebug() Option {
+	return optionFunc(func(options *clientOptions) {
+		options.debug = true
+	})
+}
+
+// client creates the Client shared by the APIs of NewHyperliquid.
+func (options *clientOptions) client() Client {
+	client := *NewClient(options.isMainnet)
+	if options.baseURL != "" {
+		client.baseURL = options.baseURL
+	}
+	if options.httpClient != nil {
+		client.httpClient = options.httpClient
+	}
+	if options.transport != nil {
+		client.SetTransport(options.transport)
+	}
+	if options.timeout > 0 {
+		httpClient := *client.httpClient
+		httpClient.Timeout = options.timeout
+		client.httpClient = &httpClient
+	}
+	if options.logger != nil {
+		client.Logger = options.logger
+	}
+	client.retryPolicy = options.retryPolicy
+	client.rateLimiter = options.rateLimiter
+	client.Debug = options.debug
+	return client
+}
@@ -0,0 +1,234 @@
+package hyperliquid
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ExpiringOrder is an order canceled by an OrderExpiryScheduler once its deadline passes.
+type ExpiringOrder struct {
+	Coin     string
+	Oid      int
+	Deadline time.Time
+}
+
+// OrderExpiryScheduler emulates good-till-date orders: the tracked orders are canceled
+// by a background goroutine once their deadline passes.
+// Orders filled or canceled before their deadline should be untracked, otherwise the cancel fails
+// and the error is reported to the OnExpire callbacks.
+//
+// With scheduleCancel, the scheduleCancel of the account (dead man's switch) is also armed at the latest deadline,
+// so the orders are canceled by the exchange even if the process stops. It cancels all the open orders
+// of the account, including the orders that are not tracked, and replaces any scheduleCancel set by the caller.
+//
+//	scheduler := api.NewOrderExpiryScheduler(false)
+//	defer scheduler.Close()
+//	res, err := scheduler.PlaceOrder(request, time.Now().Add(time.Hour))
+type OrderExpiryScheduler struct {
+	api            *ExchangeAPI
+	scheduleCancel bool
+	scheduledTime  int64 // Time of the armed scheduleCancel, 0 if none
+	orders         map[int]ExpiringOrder
+	callbacks      []func(order ExpiringOrder, err error)
+	wake           chan struct{}
+	done           chan struct{}
+	stop           chan struct{}
+	once           sync.Once
+	mu             sync.Mutex
+}
+
+// NewOrderExpiryScheduler starts a scheduler canceling the orders of the account once their deadline passes.
+// If scheduleCancel is true, the scheduleCancel of the account is kept armed at the latest deadline.
+func (api *ExchangeAPI) NewOrderExpiryScheduler(scheduleCancel bool) *OrderExpiryScheduler {
+	scheduler := &OrderExpiryScheduler{
+		api:            api,
+		scheduleCancel: scheduleCancel,
+		orders:         make(map[int]ExpiringOrder),
+		wake:           make(chan struct{}, 1),
+		done:           make(chan struct{}),
+		stop:           make(chan struct{}),
+	}
+	go scheduler.run()
+	return scheduler
+}
+
+// PlaceOrder places the order and tracks it until deadline if it rests on the book.
+func (scheduler *OrderExpiryScheduler) PlaceOrder(request OrderRequest, deadline time.Time) (*OrderResponse, error) {
+	if !deadline.After(time.Now()) {
+		return nil, APIError{Message: fmt.Sprintf("Order deadline %s is in the past", deadline)}
+	}
+	res, err := scheduler.api.Order(request, GroupingNa)
+	if err != nil {
+		return nil, err
+	}
+	if statuses := res.Response.Data.Statuses; len(statuses) > 0 && statuses[0].Resting.OrderID != 0 {
+		scheduler.Track(request.Coin, statuses[0].Resting.OrderID, deadline)
+	}
+	return res, nil
+}
+
+// Track cancels the resting order oid of coin once deadline passes.
+// Tracking an order again replaces its deadline.
+func (scheduler *OrderExpiryScheduler) Track(coin string, oid int, deadline time.Time) {
+	scheduler.mu.Lock()
+	scheduler.orders[oid] = ExpiringOrder{Coin: coin, Oid: oid, Deadline: deadline}
+	scheduler.mu.Unlock()
+	scheduler.notify()
+}
+
+// Untrack stops tracking the order oid, it is not canceled.
+func (scheduler *OrderExpiryScheduler) Untrack(oid int) {
+	scheduler.mu.Lock()
+	delete(scheduler.orders, oid)
+	scheduler.mu.Unlock()
+	scheduler.notify()
+}
+
+// Orders returns the tracked orders sorted by deadline.
+func (scheduler *OrderExpiryScheduler) Orders() []ExpiringOrder {
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+	return scheduler.sortedOrders()
+}
+
+// OnExpire registers a callback called with each order canceled by the scheduler and the error of the cancel.
+// Callbacks are called from the scheduler goroutine and must not block.
+func (scheduler *OrderExpiryScheduler) OnExpire(callback func(order ExpiringOrder, err error)) {
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+	scheduler.callbacks = append(scheduler.callbacks, callback)
+}
+
+// Done is closed once the scheduler stopped after Close.
+func (scheduler *OrderExpiryScheduler) Done() <-chan struct{} {
+	return scheduler.done
+}
+
+// Close stops the scheduler without canceling the tracked orders.
+// The scheduleCancel of the account, if armed, is left in place.
+func (scheduler *OrderExpiryScheduler) Close() error {
+	scheduler.once.Do(func() { close(scheduler.stop) })
+	<-scheduler.done
+	return nil
+}
+
+// notify wakes up the scheduler goroutine to recompute the next deadline.
+func (scheduler *OrderExpiryScheduler) notify() {
+	select {
+	case scheduler.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run cancels the orders as their deadline passes.
+func (scheduler *OrderExpiryScheduler) run() {
+	defer close(scheduler.done)
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+	for {
+		expired, next := scheduler.expired(time.Now())
+		for _, order := range expired {
+			scheduler.cancel(order)
+		}
+		if scheduler.scheduleCancel {
+			scheduler.armScheduleCancel()
+		}
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		if !next.IsZero() {
+			timer.Reset(time.Until(next))
+		}
+		select {
+		case <-timer.C:
+		case <-scheduler.wake:
+		case <-scheduler.stop:
+			return
+		}
+	}
+}
+
+// expired removes and returns the orders past their deadline and the next deadline, zero if there is none.
+func (scheduler *OrderExpiryScheduler) expired(now time.Time) ([]ExpiringOrder, time.Time) {
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+	var expired []ExpiringOrder
+	var next time.Time
+	for _, order := range scheduler.sortedOrders() {
+		if order.Deadline.After(now) {
+			next = order.Deadline
+			break
+		}
+		expired = append(expired, order)
+		delete(scheduler.orders, order.Oid)
+	}
+	return expired, next
+}
+
+// cancel cancels an expired order and reports it to the callbacks.
+func (scheduler *OrderExpiryScheduler) cancel(order ExpiringOrder) {
+	res, err := scheduler.api.CancelOrderByOID(order.Coin, order.Oid)
+	if err == nil {
+		if statuses := res.Response.Data.Statuses; len(statuses) > 0 && statuses[0].Error != "" {
+			err = APIError{Message: statuses[0].Error}
+		} else if res.Status != "ok" {
+			err = APIError{Message: fmt.Sprintf("cancel failed with status %s", res.Status)}
+		}
+	}
+	if err != nil {
+		scheduler.api.debug("Error canceling expired order %d: %s", order.Oid, err)
+	}
+	scheduler.mu.Lock()
+	callbacks := scheduler.callbacks
+	scheduler.mu.Unlock()
+	for _, callback := range callbacks {
+		callback(order, err)
+	}
+}
+
+// armScheduleCancel keeps the scheduleCancel of the account at the latest deadline, or removes it without orders.
+func (scheduler *OrderExpiryScheduler) armScheduleCancel() {
+	scheduler.mu.Lock()
+	var cancelTime int64
+	for _, order := range scheduler.orders {
+		cancelTime = max(cancelTime, order.Deadline.UnixMilli())
+	}
+	scheduled := scheduler.scheduledTime
+	scheduler.mu.Unlock()
+	if cancelTime != 0 {
+		// The exchange requires the time to be at least 5 seconds in the future when it receives the request
+		cancelTime = max(cancelTime, time.Now().Add(SCHEDULE_CANCEL_MIN_DELAY).UnixMilli())
+	}
+	if cancelTime == scheduled || (cancelTime != 0 && cancelTime < scheduled) {
+		// A later scheduleCancel still covers the orders
+		return
+	}
+	var err error
+	if cancelTime == 0 {
+		_, err = scheduler.api.ScheduleCancel(nil)
+	} else {
+		_, err = scheduler.api.ScheduleCancel(&cancelTime)
+	}
+	if err != nil {
+		scheduler.api.debug("Error setting scheduleCancel: %s", err)
+		return
+	}
+	scheduler.mu.Lock()
+	scheduler.scheduledTime = cancelTime
+	scheduler.mu.Unlock()
+}
+
+// sortedOrders returns the tracked orders sorted by deadline, the lock must be held.
+func (scheduler *OrderExpiryScheduler) sortedOrders() []ExpiringOrder {
+	orders := make([]ExpiringOrder, 0, len(scheduler.orders))
+	for _, order := range scheduler.orders {
+		orders = append(orders, order)
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].Deadline.Before(orders[j].Deadline) })
+	return orders
+}
@@ -0,0 +1,103 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOrderExpiryScheduler(t *testing.T) {
+	var mu sync.Mutex
+	var actions []map[string]any
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action map[string]any
+		json.Unmarshal(req.Action, &action)
+		mu.Lock()
+		actions = append(actions, action)
+		mu.Unlock()
+		switch action["type"] {
+		case "order":
+			statuses := []any{map[string]any{"resting": map[string]any{"oid": 7}}}
+			return map[string]any{"status": "ok", "response": map[string]any{"type": "order", "data": map[string]any{"statuses": statuses}}}
+		case "cancel":
+			statuses := []any{"success"}
+			return map[string]any{"status": "ok", "response": map[string]any{"type": "cancel", "data": map[string]any{"statuses": statuses}}}
+		}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "default"}}
+	})
+	scheduler := exchangeAPI.NewOrderExpiryScheduler(true)
+	defer scheduler.Close()
+	expired := make(chan ExpiringOrder, 2)
+	scheduler.OnExpire(func(order ExpiringOrder, err error) {
+		if err != nil {
+			t.Errorf("OnExpire() error = %v", err)
+		}
+		expired <- order
+	})
+
+	request := OrderRequest{Coin: "ETH", IsBuy: true, Sz: 0.01, LimitPx: 2000, OrderType: OrderType{Limit: &LimitOrderType{Tif: TifGtc}}}
+	if _, err := scheduler.PlaceOrder(request, time.Now().Add(-time.Second)); err == nil {
+		t.Errorf("PlaceOrder() expected error for a past deadline")
+	}
+	deadline := time.Now().Add(100 * time.Millisecond)
+	if _, err := scheduler.PlaceOrder(request, deadline); err != nil {
+		t.Fatalf("PlaceOrder() error = %v", err)
+	}
+	if orders := scheduler.Orders(); len(orders) != 1 || orders[0].Oid != 7 || !orders[0].Deadline.Equal(deadline) {
+		t.Errorf("Orders() = %+v, want oid 7", orders)
+	}
+
+	select {
+	case order := <-expired:
+		if order.Oid != 7 || time.Now().Before(deadline) {
+			t.Errorf("OnExpire() order = %+v, want oid 7 after its deadline", order)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("OnExpire() timed out")
+	}
+	if orders := scheduler.Orders(); len(orders) != 0 {
+		t.Errorf("Orders() = %+v, want none after expiry", orders)
+	}
+	scheduler.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	var types []string
+	for _, action := range actions {
+		types = append(types, action["type"].(string))
+	}
+	// The scheduleCancel is armed for the order and removed once it expired
+	want := []string{"order", "scheduleCancel", "cancel", "scheduleCancel"}
+	if len(types) != len(want) {
+		t.Fatalf("actions = %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("actions = %v, want %v", types, want)
+			break
+		}
+	}
+	if cancels := actions[2]["cancels"].([]any); cancels[0].(map[string]any)["o"] != 7.0 || cancels[0].(map[string]any)["a"] != 1.0 {
+		t.Errorf("cancel action = %v, want oid 7 of ETH", actions[2])
+	}
+	if cancelTime, ok := actions[1]["time"].(float64); !ok || int64(cancelTime) < deadline.Add(SCHEDULE_CANCEL_MIN_DELAY).UnixMilli()-100 {
+		t.Errorf("scheduleCancel action = %v, want a time at least %v ahead", actions[1], SCHEDULE_CANCEL_MIN_DELAY)
+	}
+	if _, ok := actions[3]["time"]; ok {
+		t.Errorf("scheduleCancel action = %v, want no time", actions[3])
+	}
+	actions = nil
+	mu.Unlock()
+
+	// Untracked orders are not canceled
+	scheduler = exchangeAPI.NewOrderExpiryScheduler(false)
+	scheduler.Track("ETH", 8, time.Now().Add(10*time.Millisecond))
+	scheduler.Untrack(8)
+	time.Sleep(50 * time.Millisecond)
+	scheduler.Close()
+	mu.Lock()
+	if len(actions) != 0 || len(scheduler.Orders()) != 0 {
+		t.Errorf("actions = %v, want none for an untracked order", actions)
+	}
+}
@@ -0,0 +1,167 @@
+package hyperliquid
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// OrderManager mirrors the open orders of a user in memory.
+// It is seeded from the frontend open orders, then kept up to date with the orderUpdates stream.
+// Orders leave the manager once they are filled, canceled or rejected and the OnFinal callbacks are called.
+// The size of an order is its remaining size as of its latest status update,
+// partial fills do not change the status of an order.
+//
+//	manager, err := hyperliquid.NewOrderManager(info, ws, address)
+//	defer manager.Close()
+//	manager.OnFinal(func(order hyperliquid.HistoricalOrder) { ... })
+//	orders := manager.Orders("ETH")
+type OrderManager struct {
+	sub       *WsSubscriber
+	info      *InfoAPI
+	orders    map[int64]HistoricalOrder
+	cloids    map[string]int64
+	callbacks []func(order HistoricalOrder)
+	done      chan struct{}
+	stop      chan struct{}
+	once      sync.Once
+	mu        sync.RWMutex
+}
+
+// NewOrderManager subscribes to the order updates of user on ws, which must be connected,
+// and seeds the open orders with GetFrontendOpenOrders.
+func NewOrderManager(info *InfoAPI, ws *WebSocketAPI, user string) (*OrderManager, error) {
+	// Subscribe before seeding so no update is missed, the buffered updates are applied after the seed
+	sub, err := ws.Subscribe(Subscription{Type: "orderUpdates", User: user})
+	if err != nil {
+		return nil, err
+	}
+	orders, err := info.GetFrontendOpenOrders(user)
+	if err != nil {
+		sub.Unsubscribe()
+		return nil, err
+	}
+	manager := &OrderManager{
+		sub:    sub,
+		info:   info,
+		orders: make(map[int64]HistoricalOrder, len(*orders)),
+		cloids: make(map[string]int64),
+		done:   make(chan struct{}),
+		stop:   make(chan struct{}),
+	}
+	for _, order := range *orders {
+		manager.update(HistoricalOrder{Order: order, Status: "open", StatusTimestamp: order.Timestamp})
+	}
+	go manager.run()
+	return manager, nil
+}
+
+// OnFinal registers a callback called with the order once it is filled, canceled or rejected.
+// Callbacks are called from the update goroutine in the order they were registered and must not block.
+func (manager *OrderManager) OnFinal(callback func(order HistoricalOrder)) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	manager.callbacks = append(manager.callbacks, callback)
+}
+
+// Order returns the open order with the given oid.
+func (manager *OrderManager) Order(oid int64) (HistoricalOrder, bool) {
+	manager.mu.RLock()
+	defer manager.mu.RUnlock()
+	order, ok := manager.orders[oid]
+	return order, ok
+}
+
+// OrderByCloid returns the open order with the given client order id.
+func (manager *OrderManager) OrderByCloid(cloid string) (HistoricalOrder, bool) {
+	manager.mu.RLock()
+	defer manager.mu.RUnlock()
+	oid, ok := manager.cloids[strings.ToLower(cloid)]
+	if !ok {
+		return HistoricalOrder{}, false
+	}
+	order, ok := manager.orders[oid]
+	return order, ok
+}
+
+// Orders returns the open orders of coin sorted by oid, all the open orders if coin is empty.
+func (manager *OrderManager) Orders(coin string) []HistoricalOrder {
+	manager.mu.RLock()
+	defer manager.mu.RUnlock()
+	orders := make([]HistoricalOrder, 0, len(manager.orders))
+	for _, order := range manager.orders {
+		if coin == "" || order.Order.Coin == coin {
+			orders = append(orders, order)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].Order.Oid < orders[j].Order.Oid })
+	return orders
+}
+
+// Done is closed once the manager stopped, after Close or if the websocket subscription is closed.
+func (manager *OrderManager) Done() <-chan struct{} {
+	return manager.done
+}
+
+// Close stops updating the orders.
+func (manager *OrderManager) Close() error {
+	manager.once.Do(func() { close(manager.stop) })
+	<-manager.done
+	return nil
+}
+
+// run applies the order updates until the manager is closed.
+func (manager *OrderManager) run() {
+	defer close(manager.done)
+	defer manager.sub.Unsubscribe()
+	for {
+		select {
+		case msg, ok := <-manager.sub.C():
+			if !ok {
+				return
+			}
+			events, err := parseAccountEvents(&msg)
+			if err != nil {
+				manager.info.debug("Error parsing order updates: %s", err)
+				continue
+			}
+			for _, event := range events {
+				if event.Order == nil {
+					continue
+				}
+				for _, callback := range manager.update(*event.Order) {
+					callback(*event.Order)
+				}
+			}
+		case <-manager.stop:
+			return
+		}
+	}
+}
+
+// update stores an open order or removes a final one.
+// It returns the callbacks to call if the order is final.
+func (manager *OrderManager) update(order HistoricalOrder) []func(order HistoricalOrder) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	oid := order.Order.Oid
+	if previous, ok := manager.orders[oid]; ok && previous.StatusTimestamp > order.StatusTimestamp {
+		// Update older than the seeded state
+		return nil
+	}
+	if !order.IsFinal() {
+		manager.orders[oid] = order
+		if order.Order.Cloid != "" {
+			manager.cloids[strings.ToLower(order.Order.Cloid)] = oid
+		}
+		return nil
+	}
+	// Orders that were never open, e.g. IOC orders, are reported as well
+	if previous, ok := manager.orders[oid]; ok {
+		delete(manager.orders, oid)
+		if previous.Order.Cloid != "" {
+			delete(manager.cloids, strings.ToLower(previous.Order.Cloid))
+		}
+	}
+	return manager.callbacks
+}
@@ -0,0 +1,78 @@
+package hyperliquid
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestOrderManager(t *testing.T) {
+	cloid := "0x00000000000000000000000000000001"
+	subscribed := make(chan *websocket.Conn, 1)
+	server := newTestWsServer(t, func(conn *websocket.Conn, req WsRequest) any {
+		if req.Method == "subscribe" {
+			subscribed <- conn
+		}
+		return nil
+	})
+	ws := GetTestWebSocketAPI(t, server)
+	info := GetTestInfoAPI(t, func(req InfoRequest) any {
+		if req.Type != "frontendOpenOrders" || req.User != "0x1" {
+			t.Errorf("unexpected info request %+v", req)
+		}
+		return []any{
+			map[string]any{"coin": "ETH", "oid": 1, "side": "B", "limitPx": "2000", "sz": "1", "timestamp": 100},
+			map[string]any{"coin": "BTC", "oid": 2, "cloid": cloid, "side": "A", "limitPx": "90000", "sz": "0.1", "timestamp": 200},
+		}
+	})
+	manager, err := NewOrderManager(info, ws, "0x1")
+	if err != nil {
+		t.Fatalf("NewOrderManager() error = %v", err)
+	}
+	defer manager.Close()
+	final := make(chan HistoricalOrder, 4)
+	manager.OnFinal(func(order HistoricalOrder) { final <- order })
+
+	if orders := manager.Orders(""); len(orders) != 2 || orders[0].Order.Oid != 1 || orders[1].Status != "open" {
+		t.Errorf("Orders() = %+v, want the 2 seeded orders", orders)
+	}
+	if order, ok := manager.OrderByCloid("0x00000000000000000000000000000001"); !ok || order.Order.Oid != 2 {
+		t.Errorf("OrderByCloid() = %+v, %v, want oid 2", order, ok)
+	}
+
+	conn := <-subscribed
+	conn.WriteJSON(map[string]any{"channel": "orderUpdates", "data": []any{
+		map[string]any{"order": map[string]any{"coin": "ETH", "oid": 3, "side": "B", "limitPx": "1900", "sz": "2"}, "status": "open", "statusTimestamp": 300},
+		map[string]any{"order": map[string]any{"coin": "BTC", "oid": 2, "cloid": cloid, "side": "A"}, "status": "filled", "statusTimestamp": 400},
+	}})
+	select {
+	case order := <-final:
+		if order.Order.Oid != 2 || order.Status != "filled" {
+			t.Errorf("OnFinal() order = %+v, want oid 2 filled", order)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("OnFinal() timed out")
+	}
+	if _, ok := manager.Order(2); ok {
+		t.Errorf("Order(2) expected removed once filled")
+	}
+	if _, ok := manager.OrderByCloid(cloid); ok {
+		t.Errorf("OrderByCloid() expected removed once filled")
+	}
+	if orders := manager.Orders("ETH"); len(orders) != 2 || orders[1].Order.Oid != 3 || orders[1].Order.LimitPx != 1900 {
+		t.Errorf("Orders(ETH) = %+v, want oids 1 and 3", orders)
+	}
+	if orders := manager.Orders("BTC"); len(orders) != 0 {
+		t.Errorf("Orders(BTC) = %+v, want none", orders)
+	}
+
+	if err := manager.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	select {
+	case <-manager.Done():
+	default:
+		t.Errorf("Done() expected closed after Close")
+	}
+}
@@ -0,0 +1,95 @@
+package hyperliquid
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WaitForOrder blocks until the order identified by oidOrCloid (int oid or hex cloid string)
+// is filled, canceled or rejected, and returns its final state.
+// The orderUpdates stream of the connected websocket is used when available,
+// otherwise the orderStatus is polled every ORDER_STATUS_POLL_INTERVAL.
+// The order is looked up for the vault address if one is set, otherwise for the account address.
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+//	defer cancel()
+//	order, err := api.WaitForOrder(ctx, oid)
+func (api *ExchangeAPI) WaitForOrder(ctx context.Context, oidOrCloid any) (*HistoricalOrder, error) {
+	match, err := orderMatcher(oidOrCloid)
+	if err != nil {
+		return nil, err
+	}
+	user := api.VaultAddress()
+	if user == "" {
+		user = api.AccountAddress()
+	}
+	var updates <-chan WsMessage
+	if api.ws != nil && api.ws.IsConnected() {
+		// Subscribe before checking the status so no update is missed
+		sub, err := api.ws.Subscribe(Subscription{Type: "orderUpdates", User: user})
+		if err != nil {
+			api.debug("Error subscribing to order updates: %s", err)
+		} else {
+			defer sub.Unsubscribe()
+			updates = sub.C()
+		}
+	}
+	ticker := time.NewTicker(ORDER_STATUS_POLL_INTERVAL)
+	defer ticker.Stop()
+	poll := true
+	for {
+		if poll {
+			res, err := api.infoAPI.GetOrderStatus(user, oidOrCloid)
+			if err != nil {
+				return nil, err
+			}
+			if res.IsFound() && res.Order.IsFinal() {
+				return res.Order, nil
+			}
+		}
+		select {
+		case msg, ok := <-updates:
+			if !ok {
+				// Fall back to polling if the subscription is closed
+				updates, poll = nil, true
+				continue
+			}
+			poll = false
+			events, err := parseAccountEvents(&msg)
+			if err != nil {
+				api.debug("Error parsing order updates: %s", err)
+				continue
+			}
+			for _, event := range events {
+				if event.Order != nil && match(&event.Order.Order) && event.Order.IsFinal() {
+					return event.Order, nil
+				}
+			}
+		case <-ticker.C:
+			// Only poll without an order update stream
+			poll = updates == nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// orderMatcher returns a function reporting whether an order has the given oid or cloid.
+func orderMatcher(oidOrCloid any) (func(order *Order) bool, error) {
+	switch oid := oidOrCloid.(type) {
+	case int:
+		return func(order *Order) bool { return order.Oid == int64(oid) }, nil
+	case int64:
+		return func(order *Order) bool { return order.Oid == oid }, nil
+	case uint64:
+		return func(order *Order) bool { return order.Oid == int64(oid) }, nil
+	case string:
+		if _, err := HexToInt(oid); err != nil {
+			return nil, err
+		}
+		return func(order *Order) bool { return strings.EqualFold(order.Cloid, oid) }, nil
+	}
+	return nil, APIError{Message: fmt.Sprintf("Invalid oid or cloid: %v", oidOrCloid)}
+}
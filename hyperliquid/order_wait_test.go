@@ -0,0 +1,86 @@
+package hyperliquid
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestExchangeAPI_WaitForOrderWebSocket(t *testing.T) {
+	cloid := "0x00000000000000000000000000000001"
+	subscribed := make(chan *websocket.Conn, 1)
+	server := newTestWsServer(t, func(conn *websocket.Conn, req WsRequest) any {
+		if req.Method == "subscribe" {
+			subscribed <- conn
+		}
+		return nil
+	})
+	ws := GetTestWebSocketAPI(t, server)
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any { return nil })
+	exchangeAPI.SetWebSocketAPI(ws)
+	polls := 0
+	exchangeAPI.infoAPI = GetTestInfoAPI(t, func(req InfoRequest) any {
+		polls++
+		return map[string]any{"status": "order", "order": map[string]any{
+			"order": map[string]any{"coin": "ETH", "oid": 7, "cloid": cloid, "side": "B"}, "status": "open",
+		}}
+	})
+	go func() {
+		conn := <-subscribed
+		conn.WriteJSON(map[string]any{"channel": "orderUpdates", "data": []any{
+			map[string]any{"order": map[string]any{"coin": "ETH", "oid": 8, "side": "B"}, "status": "filled", "statusTimestamp": 1},
+			map[string]any{"order": map[string]any{"coin": "ETH", "oid": 7, "cloid": cloid, "side": "B"}, "status": "open", "statusTimestamp": 2},
+			map[string]any{"order": map[string]any{"coin": "ETH", "oid": 7, "cloid": cloid, "side": "B"}, "status": "canceled", "statusTimestamp": 3},
+		}})
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	order, err := exchangeAPI.WaitForOrder(ctx, cloid)
+	if err != nil {
+		t.Fatalf("WaitForOrder() error = %v", err)
+	}
+	if order.Order.Oid != 7 || order.Status != "canceled" {
+		t.Errorf("WaitForOrder() = %+v, want oid 7 canceled", order)
+	}
+	if polls != 1 {
+		t.Errorf("polled %d times, want a single status check", polls)
+	}
+}
+
+func TestExchangeAPI_WaitForOrderPolling(t *testing.T) {
+	cloid := "0x00000000000000000000000000000002"
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any { return nil })
+	var requests []InfoRequest
+	exchangeAPI.infoAPI = GetTestInfoAPI(t, func(req InfoRequest) any {
+		requests = append(requests, req)
+		if len(requests) == 1 {
+			return map[string]any{"status": "unknownOid"}
+		}
+		return map[string]any{"status": "order", "order": map[string]any{
+			"order": map[string]any{"coin": "ETH", "oid": 7, "cloid": cloid, "side": "A"}, "status": "filled",
+		}}
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	order, err := exchangeAPI.WaitForOrder(ctx, cloid)
+	if err != nil {
+		t.Fatalf("WaitForOrder() error = %v", err)
+	}
+	if order.Status != "filled" || len(requests) != 2 || requests[0].Type != "orderStatus" || requests[0].Oid != cloid {
+		t.Errorf("WaitForOrder() = %+v after %d requests, want filled after 2 orderStatus requests", order, len(requests))
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	exchangeAPI.infoAPI = GetTestInfoAPI(t, func(req InfoRequest) any {
+		return map[string]any{"status": "order", "order": map[string]any{"order": map[string]any{"oid": 7}, "status": "open"}}
+	})
+	if _, err := exchangeAPI.WaitForOrder(ctx, cloid); err != context.DeadlineExceeded {
+		t.Errorf("WaitForOrder() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if _, err := exchangeAPI.WaitForOrder(ctx, 7.5); err == nil {
+		t.Errorf("WaitForOrder() expected invalid oid error")
+	}
+}
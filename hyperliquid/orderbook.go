@@ -0,0 +1,203 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// BookLevel is a single price level of an order book.
+type BookLevel struct {
+	Px float64 // Price of the level
+	Sz float64 // Total size resting at the level
+	N  int     // Number of orders at the level
+}
+
+// OrderBook is an in-memory L2 order book of a single coin.
+//
+// It is safe for concurrent use: the book is updated from the l2Book stream
+// while strategies read it from other goroutines.
+type OrderBook struct {
+	mu   sync.RWMutex
+	coin string
+	time int64
+	bids []BookLevel // sorted by price descending
+	asks []BookLevel // sorted by price ascending
+	sub  *WsSubscriber
+	done chan struct{}
+}
+
+// NewOrderBook returns an empty order book for the given coin.
+// Feed it with ApplySnapshot or use WebSocketAPI.SubscribeOrderBook.
+func NewOrderBook(coin string) *OrderBook {
+	return &OrderBook{coin: coin}
+}
+
+// SubscribeOrderBook subscribes to the l2Book stream of the coin and returns
+// a book that is kept up to date until Close is called.
+func (ws *WebSocketAPI) SubscribeOrderBook(coin string) (*OrderBook, error) {
+	// Every l2Book message is a full snapshot so only the latest one matters.
+	sub, err := ws.SubscribeWithOptions(
+		Subscription{Type: "l2Book", Coin: coin},
+		SubscriberOptions{BufferSize: 1, Policy: WsPolicyLatest},
+	)
+	if err != nil {
+		return nil, err
+	}
+	book := NewOrderBook(coin)
+	book.sub = sub
+	book.done = make(chan struct{})
+	go func() {
+		defer close(book.done)
+		for msg := range sub.C() {
+			var snapshot L2BookSnapshot
+			if err := json.Unmarshal(msg.Data, &snapshot); err != nil {
+				ws.debug("Error json.Unmarshal l2Book: %s", err)
+				continue
+			}
+			book.ApplySnapshot(&snapshot)
+		}
+	}()
+	return book, nil
+}
+
+// Close stops the updates of a book created with SubscribeOrderBook.
+func (book *OrderBook) Close() error {
+	if book.sub == nil {
+		return nil
+	}
+	err := book.sub.Unsubscribe()
+	<-book.done
+	return err
+}
+
+// Coin returns the coin of the book.
+func (book *OrderBook) Coin() string {
+	return book.coin
+}
+
+// Time returns the timestamp in milliseconds of the last applied snapshot.
+func (book *OrderBook) Time() int64 {
+	book.mu.RLock()
+	defer book.mu.RUnlock()
+	return book.time
+}
+
+// ApplySnapshot replaces the content of the book with the snapshot.
+// Snapshots of another coin or older than the current book are rejected.
+func (book *OrderBook) ApplySnapshot(snapshot *L2BookSnapshot) error {
+	if snapshot.Coin != book.coin {
+		return APIError{Message: fmt.Sprintf("Snapshot for %s applied to %s book", snapshot.Coin, book.coin)}
+	}
+	if len(snapshot.Levels) != 2 {
+		return APIError{Message: fmt.Sprintf("Invalid snapshot levels: %d", len(snapshot.Levels))}
+	}
+	bids := make([]BookLevel, 0, len(snapshot.Levels[0]))
+	for _, level := range snapshot.Levels[0] {
+		bids = append(bids, BookLevel{Px: level.Px, Sz: level.Sz, N: level.N})
+	}
+	asks := make([]BookLevel, 0, len(snapshot.Levels[1]))
+	for _, level := range snapshot.Levels[1] {
+		asks = append(asks, BookLevel{Px: level.Px, Sz: level.Sz, N: level.N})
+	}
+	sort.Slice(bids, func(i, j int) bool { return bids[i].Px > bids[j].Px })
+	sort.Slice(asks, func(i, j int) bool { return asks[i].Px < asks[j].Px })
+
+	book.mu.Lock()
+	defer book.mu.Unlock()
+	if snapshot.Time < book.time {
+		return APIError{Message: fmt.Sprintf("Stale snapshot: %d < %d", snapshot.Time, book.time)}
+	}
+	book.time = snapshot.Time
+	book.bids = bids
+	book.asks = asks
+	return nil
+}
+
+// BestBid returns the highest bid level.
+// Returns false if there are no bids.
+func (book *OrderBook) BestBid() (BookLevel, bool) {
+	book.mu.RLock()
+	defer book.mu.RUnlock()
+	if len(book.bids) == 0 {
+		return BookLevel{}, false
+	}
+	return book.bids[0], true
+}
+
+// BestAsk returns the lowest ask level.
+// Returns false if there are no asks.
+func (book *OrderBook) BestAsk() (BookLevel, bool) {
+	book.mu.RLock()
+	defer book.mu.RUnlock()
+	if len(book.asks) == 0 {
+		return BookLevel{}, false
+	}
+	return book.asks[0], true
+}
+
+// Bids returns a copy of the bid levels, best first.
+func (book *OrderBook) Bids() []BookLevel {
+	book.mu.RLock()
+	defer book.mu.RUnlock()
+	return append([]BookLevel(nil), book.bids...)
+}
+
+// Asks returns a copy of the ask levels, best first.
+func (book *OrderBook) Asks() []BookLevel {
+	book.mu.RLock()
+	defer book.mu.RUnlock()
+	return append([]BookLevel(nil), book.asks...)
+}
+
+// DepthAt returns the cumulative size resting at prices at least as good as px:
+// bids priced at or above px and asks priced at or below px.
+//
+//	bidSz, askSz := book.DepthAt(100000)
+func (book *OrderBook) DepthAt(px float64) (bidSz float64, askSz float64) {
+	book.mu.RLock()
+	defer book.mu.RUnlock()
+	for _, level := range book.bids {
+		if level.Px < px {
+			break
+		}
+		bidSz += level.Sz
+	}
+	for _, level := range book.asks {
+		if level.Px > px {
+			break
+		}
+		askSz += level.Sz
+	}
+	return bidSz, askSz
+}
+
+// VWAP returns the volume weighted average price of taking sz from the book.
+// A buy walks the asks and a sell walks the bids.
+// Returns an error if the book does not hold enough size.
+func (book *OrderBook) VWAP(isBuy bool, sz float64) (float64, error) {
+	if sz <= 0 {
+		return 0, APIError{Message: fmt.Sprintf("Invalid size: %v", sz)}
+	}
+	book.mu.RLock()
+	defer book.mu.RUnlock()
+	levels := book.bids
+	if isBuy {
+		levels = book.asks
+	}
+	remaining := sz
+	notional := 0.0
+	for _, level := range levels {
+		fill := level.Sz
+		if fill > remaining {
+			fill = remaining
+		}
+		notional += fill * level.Px
+		remaining -= fill
+		if remaining <= sz*1e-12 {
+			return notional / sz, nil
+		}
+	}
+	return 0, APIError{Message: fmt.Sprintf("Not enough liquidity in %s book for size %v", book.coin, sz)}
+}
@@ -0,0 +1,86 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func GetTestOrderBook(t *testing.T) *OrderBook {
+	var snapshot L2BookSnapshot
+	data := `{"coin":"BTC","time":2,"levels":[
+		[{"px":"99","sz":"1","n":1},{"px":"100","sz":"2","n":2},{"px":"98","sz":"3","n":1}],
+		[{"px":"102","sz":"2","n":1},{"px":"101","sz":"1","n":1},{"px":"103","sz":"5","n":3}]
+	]}`
+	if err := json.Unmarshal([]byte(data), &snapshot); err != nil {
+		t.Fatal(err)
+	}
+	book := NewOrderBook("BTC")
+	if err := book.ApplySnapshot(&snapshot); err != nil {
+		t.Fatalf("ApplySnapshot() error = %v", err)
+	}
+	return book
+}
+
+func TestOrderBook_BestBidAsk(t *testing.T) {
+	book := GetTestOrderBook(t)
+	bid, ok := book.BestBid()
+	if !ok || bid.Px != 100 || bid.Sz != 2 {
+		t.Errorf("BestBid() = %+v, want 100 x 2", bid)
+	}
+	ask, ok := book.BestAsk()
+	if !ok || ask.Px != 101 || ask.Sz != 1 {
+		t.Errorf("BestAsk() = %+v, want 101 x 1", ask)
+	}
+	if _, ok := NewOrderBook("BTC").BestBid(); ok {
+		t.Errorf("BestBid() on empty book returned a level")
+	}
+}
+
+func TestOrderBook_DepthAt(t *testing.T) {
+	book := GetTestOrderBook(t)
+	bidSz, askSz := book.DepthAt(99)
+	if bidSz != 3 || askSz != 0 {
+		t.Errorf("DepthAt(99) = %v, %v, want 3, 0", bidSz, askSz)
+	}
+	bidSz, askSz = book.DepthAt(102)
+	if bidSz != 0 || askSz != 3 {
+		t.Errorf("DepthAt(102) = %v, %v, want 0, 3", bidSz, askSz)
+	}
+}
+
+func TestOrderBook_VWAP(t *testing.T) {
+	book := GetTestOrderBook(t)
+	px, err := book.VWAP(true, 2)
+	if err != nil || math.Abs(px-101.5) > 1e-9 {
+		t.Errorf("VWAP(buy, 2) = %v, %v, want 101.5", px, err)
+	}
+	px, err = book.VWAP(false, 3)
+	if err != nil || math.Abs(px-(200+99)/3.0) > 1e-9 {
+		t.Errorf("VWAP(sell, 3) = %v, %v, want %v", px, err, (200+99)/3.0)
+	}
+	if _, err := book.VWAP(true, 100); err == nil {
+		t.Errorf("VWAP(buy, 100) expected not enough liquidity error")
+	}
+	book = NewOrderBook("ETH")
+	book.ApplySnapshot(&L2BookSnapshot{Coin: "ETH", Time: 1, Levels: [][]L2Level{{}, {{Px: 100, Sz: 0.1}, {Px: 101, Sz: 0.3}}}})
+	if px, err := book.VWAP(true, 0.4); err != nil || math.Abs(px-100.75) > 1e-9 {
+		t.Errorf("VWAP(buy, 0.4) = %v, %v, want 100.75 from the whole book", px, err)
+	}
+}
+
+func TestOrderBook_RejectStaleSnapshot(t *testing.T) {
+	book := GetTestOrderBook(t)
+	stale := &L2BookSnapshot{Coin: "BTC", Time: 1, Levels: make([][]L2Level, 2)}
+	if err := book.ApplySnapshot(stale); err == nil {
+		t.Errorf("ApplySnapshot() expected stale snapshot error")
+	}
+	stale.Coin = "ETH"
+	stale.Time = 3
+	if err := book.ApplySnapshot(stale); err == nil {
+		t.Errorf("ApplySnapshot() expected coin mismatch error")
+	}
+	if bid, _ := book.BestBid(); bid.Px != 100 {
+		t.Errorf("BestBid() = %+v after rejected snapshot, want 100", bid)
+	}
+}
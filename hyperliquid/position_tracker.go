@@ -0,0 +1,334 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"sync"
+)
+
+// TrackedPosition is the live state of a perp position of a PositionTracker.
+type TrackedPosition struct {
+	Coin          string
+	Szi           float64 // Signed size, positive for a long position
+	EntryPx       float64
+	MarkPx        float64 // Latest mid price of the coin
+	UnrealizedPnl float64
+	LiquidationPx float64 // Estimated liquidation price, 0 if the position can't be liquidated
+	IsIsolated    bool
+	CumFunding    float64 // Funding received since tracking started, negative if paid
+	RealizedPnl   float64 // Closed PnL net of fees since tracking started
+}
+
+// PositionTracker keeps the perp positions of a user up to date without polling.
+// It is seeded from the clearinghouse state, then the fills and fundings of the account events
+// are applied to the positions and the allMids stream is used as mark price.
+//
+// The liquidation price is estimated with the formula of the exchange:
+//
+//	liquidationPx = markPx - side * marginAvailable / |szi| / (1 - side / (2 * maxLeverage))
+//
+// where marginAvailable is the account value (cross) or the position margin (isolated) minus
+// the maintenance margin. It is exact after seeding and approximate once the margin changes
+// from transfers that are not tracked (deposits, withdrawals, isolated margin updates).
+//
+//	tracker, err := hyperliquid.NewPositionTracker(info, ws, address)
+//	defer tracker.Close()
+//	position, ok := tracker.Position("ETH")
+type PositionTracker struct {
+	info        *InfoAPI
+	events      *AccountEvents
+	mids        *WsSubscriber
+	maxLeverage map[string]int
+	crossCash   float64 // Cross account value without the unrealized PnL of the cross positions
+	positions   map[string]*trackedPosition
+	seedTime    int64
+	done        chan struct{}
+	stop        chan struct{}
+	once        sync.Once
+	mu          sync.RWMutex
+}
+
+// trackedPosition holds the state of a position that is not derived from the mark price.
+type trackedPosition struct {
+	TrackedPosition
+	leverage       int
+	isolatedMargin float64 // Margin of an isolated position without its unrealized PnL
+}
+
+// NewPositionTracker subscribes to the account events and mid prices of user on ws,
+// which must be connected, and seeds the positions from the clearinghouse state.
+func NewPositionTracker(info *InfoAPI, ws *WebSocketAPI, user string) (*PositionTracker, error) {
+	meta, err := info.GetMeta()
+	if err != nil {
+		return nil, err
+	}
+	tracker := &PositionTracker{
+		info:        info,
+		maxLeverage: make(map[string]int, len(meta.Universe)),
+		positions:   make(map[string]*trackedPosition),
+		done:        make(chan struct{}),
+		stop:        make(chan struct{}),
+	}
+	for _, asset := range meta.Universe {
+		tracker.maxLeverage[asset.Name] = asset.MaxLeverage
+	}
+	// Subscribe before seeding so no fill is missed, older events are skipped using the state time
+	tracker.events, err = ws.SubscribeAccountEvents(user)
+	if err != nil {
+		return nil, err
+	}
+	tracker.mids, err = ws.SubscribeWithOptions(Subscription{Type: "allMids"}, SubscriberOptions{BufferSize: 1, Policy: WsPolicyLatest})
+	if err != nil {
+		tracker.events.Close()
+		return nil, err
+	}
+	state, err := info.GetUserState(user)
+	if err != nil {
+		tracker.events.Close()
+		tracker.mids.Unsubscribe()
+		return nil, err
+	}
+	tracker.seed(state)
+	go tracker.run()
+	return tracker, nil
+}
+
+// Position returns the position of coin, false if there is no open position.
+func (tracker *PositionTracker) Position(coin string) (TrackedPosition, bool) {
+	tracker.mu.RLock()
+	defer tracker.mu.RUnlock()
+	position, ok := tracker.positions[coin]
+	if !ok || position.Szi == 0 {
+		return TrackedPosition{}, false
+	}
+	return tracker.snapshot(position), true
+}
+
+// Positions returns the open positions.
+func (tracker *PositionTracker) Positions() []TrackedPosition {
+	tracker.mu.RLock()
+	defer tracker.mu.RUnlock()
+	positions := make([]TrackedPosition, 0, len(tracker.positions))
+	for _, position := range tracker.positions {
+		if position.Szi != 0 {
+			positions = append(positions, tracker.snapshot(position))
+		}
+	}
+	return positions
+}
+
+// Done is closed once the tracker stopped, after Close or if the websocket subscriptions are closed.
+func (tracker *PositionTracker) Done() <-chan struct{} {
+	return tracker.done
+}
+
+// Close stops tracking the positions.
+func (tracker *PositionTracker) Close() error {
+	tracker.once.Do(func() { close(tracker.stop) })
+	<-tracker.done
+	return nil
+}
+
+// seed sets the positions and margins from the clearinghouse state.
+func (tracker *PositionTracker) seed(state *UserState) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	tracker.seedTime = state.Time
+	tracker.crossCash = state.CrossMarginSummary.AccountValue
+	for _, assetPosition := range state.AssetPositions {
+		item := assetPosition.Position
+		position := &trackedPosition{
+			TrackedPosition: TrackedPosition{
+				Coin:       item.Coin,
+				Szi:        item.Szi,
+				EntryPx:    item.EntryPx,
+				IsIsolated: item.Leverage.Type == "isolated",
+			},
+			leverage: item.Leverage.Value,
+		}
+		if item.Szi != 0 {
+			// The position value is at the mark price of the state
+			position.MarkPx = item.PositionValue / math.Abs(item.Szi)
+		}
+		if position.IsIsolated {
+			position.isolatedMargin = item.MarginUsed - item.UnrealizedPnl
+		} else {
+			tracker.crossCash -= item.UnrealizedPnl
+		}
+		tracker.positions[item.Coin] = position
+	}
+}
+
+// run applies the events and mid prices until the tracker is closed.
+func (tracker *PositionTracker) run() {
+	defer close(tracker.done)
+	defer tracker.mids.Unsubscribe()
+	defer tracker.events.Close()
+	for {
+		select {
+		case event, ok := <-tracker.events.C():
+			if !ok {
+				return
+			}
+			if event.IsSnapshot || event.Time <= tracker.seedTime {
+				continue
+			}
+			switch event.Type {
+			case AccountEventFill:
+				tracker.applyFill(event.Fill)
+			case AccountEventFunding:
+				tracker.applyFunding(event.Funding)
+			}
+		case msg, ok := <-tracker.mids.C():
+			if !ok {
+				return
+			}
+			var data struct {
+				Mids map[string]string `json:"mids"`
+			}
+			if err := json.Unmarshal(msg.Data, &data); err != nil {
+				tracker.info.debug("Error parsing mids: %s", err)
+				continue
+			}
+			tracker.applyMids(data.Mids)
+		case <-tracker.stop:
+			return
+		}
+	}
+}
+
+// applyFill updates the size, entry price and margin of the position of the fill.
+func (tracker *PositionTracker) applyFill(fill *OrderFill) {
+	maxLeverage, ok := tracker.maxLeverage[fill.Coin]
+	if !ok {
+		// Spot fill
+		return
+	}
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	position, ok := tracker.positions[fill.Coin]
+	if !ok {
+		// New positions use the default leverage unless it was changed, which is not streamed
+		position = &trackedPosition{TrackedPosition: TrackedPosition{Coin: fill.Coin}, leverage: min(DEFAULT_LEVERAGE, maxLeverage)}
+		tracker.positions[fill.Coin] = position
+	}
+	sz := fill.Sz
+	if fill.Side != "B" {
+		sz = -sz
+	}
+	szi := position.Szi + sz
+	if math.Abs(szi) < 1e-12 {
+		// Floating point residue of a closed position
+		szi = 0
+	}
+	switch {
+	case position.Szi == 0 || position.Szi*szi < 0:
+		// Opened or flipped
+		position.EntryPx = fill.Px
+	case math.Abs(szi) > math.Abs(position.Szi):
+		// Increased
+		position.EntryPx = (position.EntryPx*math.Abs(position.Szi) + fill.Px*fill.Sz) / math.Abs(szi)
+	}
+	pnl := fill.ClosedPnl - fill.Fee
+	position.RealizedPnl += pnl
+	if position.IsIsolated {
+		// The margin of an increase comes from the cross account
+		if added := math.Abs(szi) - math.Abs(position.Szi); added > 0 && position.leverage > 0 {
+			margin := added * fill.Px / float64(position.leverage)
+			position.isolatedMargin += margin
+			tracker.crossCash -= margin
+		}
+		position.isolatedMargin += pnl
+	} else {
+		tracker.crossCash += pnl
+	}
+	position.Szi = szi
+	position.MarkPx = fill.Px
+	if szi == 0 {
+		// The remaining isolated margin is returned to the cross account
+		tracker.crossCash += position.isolatedMargin
+		position.isolatedMargin = 0
+	}
+}
+
+// applyFunding adds a funding payment to the position and its margin.
+func (tracker *PositionTracker) applyFunding(funding *WsFunding) {
+	usdc, err := strconv.ParseFloat(funding.UsdcAmount, 64)
+	if err != nil {
+		return
+	}
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	position, ok := tracker.positions[funding.Asset]
+	if !ok {
+		return
+	}
+	position.CumFunding += usdc
+	if position.IsIsolated {
+		position.isolatedMargin += usdc
+	} else {
+		tracker.crossCash += usdc
+	}
+}
+
+// applyMids sets the mark price of the positions.
+func (tracker *PositionTracker) applyMids(mids map[string]string) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	for coin, position := range tracker.positions {
+		if mid, err := strconv.ParseFloat(mids[coin], 64); err == nil && mid > 0 {
+			position.MarkPx = mid
+		}
+	}
+}
+
+// snapshot returns the position with its mark price dependent fields.
+// The read lock must be held.
+func (tracker *PositionTracker) snapshot(position *trackedPosition) TrackedPosition {
+	result := position.TrackedPosition
+	result.UnrealizedPnl = position.unrealizedPnl()
+	result.LiquidationPx = tracker.liquidationPx(position)
+	return result
+}
+
+// liquidationPx estimates the liquidation price of the position.
+// The read lock must be held.
+func (tracker *PositionTracker) liquidationPx(position *trackedPosition) float64 {
+	maxLeverage := tracker.maxLeverage[position.Coin]
+	if position.Szi == 0 || position.MarkPx == 0 || maxLeverage == 0 {
+		return 0
+	}
+	var marginAvailable float64
+	if position.IsIsolated {
+		marginAvailable = position.isolatedMargin + position.unrealizedPnl() - position.maintenanceMargin(maxLeverage)
+	} else {
+		marginAvailable = tracker.crossCash
+		for _, other := range tracker.positions {
+			if !other.IsIsolated && other.Szi != 0 {
+				marginAvailable += other.unrealizedPnl() - other.maintenanceMargin(tracker.maxLeverage[other.Coin])
+			}
+		}
+	}
+	side := 1.0
+	if position.Szi < 0 {
+		side = -1.0
+	}
+	liquidationPx := position.MarkPx - side*marginAvailable/math.Abs(position.Szi)/(1-side/(2*float64(maxLeverage)))
+	return math.Max(0, liquidationPx)
+}
+
+func (position *trackedPosition) unrealizedPnl() float64 {
+	if position.Szi == 0 {
+		return 0
+	}
+	return position.Szi * (position.MarkPx - position.EntryPx)
+}
+
+// maintenanceMargin is half of the initial margin at the max leverage of the asset.
+func (position *trackedPosition) maintenanceMargin(maxLeverage int) float64 {
+	if maxLeverage == 0 {
+		return 0
+	}
+	return math.Abs(position.Szi) * position.MarkPx / (2 * float64(maxLeverage))
+}
@@ -0,0 +1,133 @@
+package hyperliquid
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestPositionTracker(t *testing.T) {
+	subscribed := make(chan *websocket.Conn, 8)
+	server := newTestWsServer(t, func(conn *websocket.Conn, req WsRequest) any {
+		if req.Method == "subscribe" {
+			subscribed <- conn
+		}
+		return nil
+	})
+	ws := GetTestWebSocketAPI(t, server)
+	info := GetTestInfoAPI(t, func(req InfoRequest) any {
+		switch req.Type {
+		case "meta":
+			return map[string]any{"universe": []any{map[string]any{"name": "ETH", "szDecimals": 4, "maxLeverage": 50}}}
+		case "clearinghouseState":
+			return map[string]any{
+				"assetPositions": []any{map[string]any{"type": "oneWay", "position": map[string]any{
+					"coin": "ETH", "szi": "1", "entryPx": "2000", "positionValue": "2100", "unrealizedPnl": "100",
+					"marginUsed": "105", "liquidationPx": "1010", "leverage": map[string]any{"type": "cross", "value": 20},
+				}}},
+				"crossMarginSummary": map[string]any{"accountValue": "1100"},
+				"marginSummary":      map[string]any{"accountValue": "1100"},
+				"time":               1000,
+			}
+		}
+		t.Errorf("unexpected info request %s", req.Type)
+		return nil
+	})
+	tracker, err := NewPositionTracker(info, ws, "0x1")
+	if err != nil {
+		t.Fatalf("NewPositionTracker() error = %v", err)
+	}
+	defer tracker.Close()
+
+	position, ok := tracker.Position("ETH")
+	if !ok || position.Szi != 1 || position.EntryPx != 2000 || position.MarkPx != 2100 || position.UnrealizedPnl != 100 {
+		t.Fatalf("Position() = %+v, %v, want seeded long 1 ETH", position, ok)
+	}
+	// Cross margin available is 1100 - 2100/100 maintenance margin
+	if want := 2100 - 1079/0.99; math.Abs(position.LiquidationPx-want) > 1e-6 {
+		t.Errorf("LiquidationPx = %v, want %v", position.LiquidationPx, want)
+	}
+	if _, ok := tracker.Position("BTC"); ok {
+		t.Errorf("Position(BTC) expected no position")
+	}
+
+	conn := <-subscribed
+	conn.WriteJSON(map[string]any{"channel": "userFills", "data": map[string]any{"user": "0x1", "fills": []any{
+		// Already part of the seeded state
+		map[string]any{"coin": "ETH", "side": "B", "px": "1900", "sz": "5", "closedPnl": "0", "fee": "0", "time": 900},
+		map[string]any{"coin": "ETH", "side": "B", "px": "2200", "sz": "1", "closedPnl": "0", "fee": "1", "time": 2000},
+		map[string]any{"coin": "@107", "side": "B", "px": "30", "sz": "1", "closedPnl": "0", "fee": "0", "time": 2000},
+	}}})
+	conn.WriteJSON(map[string]any{"channel": "userFundings", "data": map[string]any{"user": "0x1", "fundings": []any{
+		map[string]any{"coin": "ETH", "fundingRate": "0.0001", "szi": "2", "usdc": "-2", "time": 3000},
+	}}})
+	conn.WriteJSON(map[string]any{"channel": "userFills", "data": map[string]any{"user": "0x1", "fills": []any{
+		map[string]any{"coin": "ETH", "side": "A", "px": "2300", "sz": "3", "closedPnl": "400", "fee": "2", "time": 4000},
+	}}})
+	position = waitForPosition(t, tracker, func(position TrackedPosition) bool { return position.Szi == -1 })
+	if position.EntryPx != 2300 || position.CumFunding != -2 || position.RealizedPnl != 397 {
+		t.Errorf("Position() = %+v, want short 1 ETH at 2300 after flip", position)
+	}
+	if len(tracker.Positions()) != 1 {
+		t.Errorf("Positions() = %+v, want only ETH", tracker.Positions())
+	}
+
+	conn.WriteJSON(map[string]any{"channel": "allMids", "data": map[string]any{"mids": map[string]any{"ETH": "2250"}}})
+	position = waitForPosition(t, tracker, func(position TrackedPosition) bool { return position.MarkPx == 2250 })
+	if position.UnrealizedPnl != 50 || position.LiquidationPx <= position.MarkPx {
+		t.Errorf("Position() = %+v, want unrealized PnL 50 and liquidation above the mark price", position)
+	}
+
+	if err := tracker.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	select {
+	case <-tracker.Done():
+	default:
+		t.Errorf("Done() expected closed after Close")
+	}
+}
+
+func TestPositionTracker_Isolated(t *testing.T) {
+	position := &trackedPosition{TrackedPosition: TrackedPosition{Coin: "ETH", IsIsolated: true}, leverage: 10}
+	tracker := &PositionTracker{
+		maxLeverage: map[string]int{"ETH": 50},
+		crossCash:   1000,
+		positions:   map[string]*trackedPosition{"ETH": position},
+	}
+	tracker.applyFill(&OrderFill{Coin: "ETH", Side: "B", Px: 2000, Sz: 1})
+	if position.isolatedMargin != 200 || tracker.crossCash != 800 {
+		t.Errorf("isolated margin = %v, cross = %v, want 200 moved from cross", position.isolatedMargin, tracker.crossCash)
+	}
+	// Isolated margin available is 200 - 2000/100 maintenance margin
+	if want := 2000 - 180/0.99; math.Abs(tracker.liquidationPx(position)-want) > 1e-6 {
+		t.Errorf("liquidationPx() = %v, want %v", tracker.liquidationPx(position), want)
+	}
+	tracker.applyFill(&OrderFill{Coin: "ETH", Side: "A", Px: 2100, Sz: 1, ClosedPnl: 100})
+	if position.Szi != 0 || position.isolatedMargin != 0 || tracker.crossCash != 1100 {
+		t.Errorf("isolated margin = %v, cross = %v, want margin and PnL returned to cross", position.isolatedMargin, tracker.crossCash)
+	}
+	tracker.applyFill(&OrderFill{Coin: "ETH", Side: "B", Px: 2000, Sz: 0.1})
+	tracker.applyFill(&OrderFill{Coin: "ETH", Side: "B", Px: 2000, Sz: 0.2})
+	tracker.applyFill(&OrderFill{Coin: "ETH", Side: "A", Px: 2000, Sz: 0.3})
+	if position.Szi != 0 || position.isolatedMargin != 0 || math.Abs(tracker.crossCash-1100) > 1e-9 {
+		t.Errorf("szi = %v, isolated margin = %v, cross = %v, want the position closed", position.Szi, position.isolatedMargin, tracker.crossCash)
+	}
+}
+
+func waitForPosition(t *testing.T, tracker *PositionTracker, done func(TrackedPosition) bool) TrackedPosition {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		position, _ := tracker.Position("ETH")
+		if done(position) {
+			return position
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Position() = %+v, timed out waiting for update", position)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,164 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitMode decides what a RateLimiter does with a request exceeding the available weight.
+type RateLimitMode int
+
+const (
+	// RateLimitBlock waits until the weight is available.
+	RateLimitBlock RateLimitMode = iota
+	// RateLimitFailFast returns a RateLimitError without sending the request.
+	RateLimitFailFast
+)
+
+// infoRequestWeights are the weights of the info requests that are not INFO_REQUEST_WEIGHT.
+var infoRequestWeights = map[string]int{
+	"l2Book":                 2,
+	"allMids":                2,
+	"clearinghouseState":     2,
+	"orderStatus":            2,
+	"spotClearinghouseState": 2,
+	"exchangeStatus":         2,
+	"userRole":               60,
+}
+
+// RateLimiter is a token bucket of request weights, refilled continuously up to its capacity.
+// It is preloaded with the weights documented by Hyperliquid: exchange actions weigh 1 + n/40
+// for a batch of n orders, cancels or modifies, info requests weigh 2, 20 or 60 depending on
+// their type and explorer requests weigh 40. The extra weight of the info responses that
+// grows with their number of items is not counted.
+//
+// The IP limit is shared by all the requests, so the same RateLimiter should be set
+// on every API of the process, including the websocket posts.
+//
+//	limiter := hyperliquid.NewRateLimiter(hyperliquid.RATE_LIMIT_WEIGHT_PER_MINUTE, hyperliquid.RateLimitBlock)
+//	hl.SetRateLimiter(limiter)
+//	ws.SetRateLimiter(limiter)
+type RateLimiter struct {
+	capacity float64 // Maximum weight of a burst
+	rate     float64 // Weight refilled per second
+	tokens   float64 // Available weight, negative when reserved by waiting requests
+	last     time.Time
+	mode     RateLimitMode
+	mu       sync.Mutex
+}
+
+// NewRateLimiter creates a full RateLimiter refilling weightPerMinute per minute,
+// RATE_LIMIT_WEIGHT_PER_MINUTE if weightPerMinute is 0.
+func NewRateLimiter(weightPerMinute int, mode RateLimitMode) *RateLimiter {
+	if weightPerMinute <= 0 {
+		weightPerMinute = RATE_LIMIT_WEIGHT_PER_MINUTE
+	}
+	return &RateLimiter{
+		capacity: float64(weightPerMinute),
+		rate:     float64(weightPerMinute) / 60,
+		tokens:   float64(weightPerMinute),
+		last:     time.Now(),
+		mode:     mode,
+	}
+}
+
+// Wait takes weight from the limiter. In RateLimitBlock mode it sleeps until the weight is available,
+// the waiting requests are served in order. In RateLimitFailFast mode it returns a RateLimitError
+// with the time until the weight is available.
+func (limiter *RateLimiter) Wait(weight int) error {
+	if float64(weight) > limiter.capacity {
+		return APIError{Message: fmt.Sprintf("Request weight %d exceeds the rate limit of %.0f", weight, limiter.capacity)}
+	}
+	limiter.mu.Lock()
+	limiter.refill(time.Now())
+	missing := float64(weight) - limiter.tokens
+	if missing > 0 && limiter.mode == RateLimitFailFast {
+		limiter.mu.Unlock()
+		return RateLimitError{
+			APIError:   APIError{Message: fmt.Sprintf("Request weight %d exceeds the available rate limit weight", weight)},
+			RetryAfter: limiter.duration(missing),
+		}
+	}
+	// Reserve the weight so the next requests wait after this one
+	limiter.tokens -= float64(weight)
+	limiter.mu.Unlock()
+	if missing > 0 {
+		time.Sleep(limiter.duration(missing))
+	}
+	return nil
+}
+
+// Available returns the weight that can be taken without waiting.
+func (limiter *RateLimiter) Available() int {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	limiter.refill(time.Now())
+	return max(0, int(limiter.tokens))
+}
+
+// refill adds the weight refilled since the last refill, the lock must be held.
+func (limiter *RateLimiter) refill(now time.Time) {
+	elapsed := now.Sub(limiter.last).Seconds()
+	if elapsed > 0 {
+		limiter.tokens = min(limiter.capacity, limiter.tokens+elapsed*limiter.rate)
+		limiter.last = now
+	}
+}
+
+// duration returns the time needed to refill weight.
+func (limiter *RateLimiter) duration(weight float64) time.Duration {
+	return time.Duration(weight / limiter.rate * float64(time.Second))
+}
+
+// SetRateLimiter limits the requests of the client with limiter, pass nil to disable the limit.
+func (client *Client) SetRateLimiter(limiter *RateLimiter) {
+	client.rateLimiter = limiter
+}
+
+// RateLimiter returns the rate limiter of the client, nil if there is none.
+func (client *Client) RateLimiter() *RateLimiter {
+	return client.rateLimiter
+}
+
+// waitRateLimit takes the weight of a request to endpoint from the rate limiter of the client, if any.
+func (client *Client) waitRateLimit(endpoint string, payloadBytes []byte) error {
+	if client.rateLimiter == nil {
+		return nil
+	}
+	weight := requestWeight(endpoint, payloadBytes)
+	client.debug("Request weight to %s: %d", endpoint, weight)
+	return client.rateLimiter.Wait(weight)
+}
+
+// requestWeight returns the documented weight of a request to endpoint.
+func requestWeight(endpoint string, payloadBytes []byte) int {
+	switch strings.Trim(endpoint, "/") {
+	case "exchange":
+		var request struct {
+			Action struct {
+				Orders   []json.RawMessage `json:"orders"`
+				Cancels  []json.RawMessage `json:"cancels"`
+				Modifies []json.RawMessage `json:"modifies"`
+			} `json:"action"`
+		}
+		if err := json.Unmarshal(payloadBytes, &request); err != nil {
+			return 1
+		}
+		action := request.Action
+		return 1 + (len(action.Orders)+len(action.Cancels)+len(action.Modifies))/RATE_LIMIT_BATCH_SIZE
+	case "explorer":
+		return EXPLORER_REQUEST_WEIGHT
+	default:
+		var request struct {
+			Type string `json:"type"`
+		}
+		json.Unmarshal(payloadBytes, &request)
+		if weight, ok := infoRequestWeights[request.Type]; ok {
+			return weight
+		}
+		return INFO_REQUEST_WEIGHT
+	}
+}
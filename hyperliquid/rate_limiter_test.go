@@ -0,0 +1,94 @@
+package hyperliquid
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_FailFast(t *testing.T) {
+	limiter := NewRateLimiter(60, RateLimitFailFast)
+	if err := limiter.Wait(60); err != nil {
+		t.Fatalf("Wait() error = %v, want the full capacity available", err)
+	}
+	err := limiter.Wait(1)
+	var rateLimitErr RateLimitError
+	if !errors.As(err, &rateLimitErr) || rateLimitErr.RetryAfter <= 0 || rateLimitErr.RetryAfter > time.Second {
+		t.Errorf("Wait() error = %v, want RateLimitError retrying within 1s", err)
+	}
+	if err := limiter.Wait(61); err == nil || errors.As(err, &rateLimitErr) {
+		t.Errorf("Wait() error = %v, want APIError for a weight above the capacity", err)
+	}
+	// Refilled at 1 weight per second
+	limiter.refill(limiter.last.Add(10 * time.Second))
+	if got := limiter.Available(); got < 10 || got > 11 {
+		t.Errorf("Available() = %d, want 10 after 10s", got)
+	}
+}
+
+func TestRateLimiter_Block(t *testing.T) {
+	limiter := NewRateLimiter(6000, RateLimitBlock)
+	limiter.Wait(6000)
+	start := time.Now()
+	if err := limiter.Wait(10); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	// 10 weight is refilled in 100ms
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond || elapsed > time.Second {
+		t.Errorf("Wait() returned after %s, want about 100ms", elapsed)
+	}
+}
+
+func TestRateLimiter_RequestWeight(t *testing.T) {
+	orders := make([]OrderWire, 85)
+	tests := []struct {
+		name     string
+		endpoint string
+		payload  any
+		want     int
+	}{
+		{"order", "/exchange", ExchangeRequest{Action: PlaceOrderAction{Type: "order", Orders: orders[:1]}}, 1},
+		{"batch", "/exchange", ExchangeRequest{Action: PlaceOrderAction{Type: "order", Orders: orders}}, 3},
+		{"cancel", "exchange", ExchangeRequest{Action: CancelOidOrderAction{Type: "cancel", Cancels: make([]CancelOidWire, 40)}}, 2},
+		{"l2Book", "/info", InfoRequest{Type: "l2Book", Coin: "ETH"}, 2},
+		{"userRole", "/info", InfoRequest{Type: "userRole"}, 60},
+		{"userFills", "/info", InfoRequest{Type: "userFills"}, 20},
+		{"explorer", "/explorer", map[string]any{"type": "blockDetails"}, 40},
+	}
+	for _, tt := range tests {
+		client := NewClient(false)
+		client.SetRateLimiter(NewRateLimiter(0, RateLimitFailFast))
+		requests := 0
+		client.SetTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			requests++
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+		}))
+		if _, err := client.Request(tt.endpoint, tt.payload); err != nil {
+			t.Fatalf("%s: Request() error = %v", tt.name, err)
+		}
+		if got := RATE_LIMIT_WEIGHT_PER_MINUTE - client.RateLimiter().Available(); got != tt.want || requests != 1 {
+			t.Errorf("%s: weight = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestClient_RateLimited(t *testing.T) {
+	requests := 0
+	client := NewClient(false)
+	client.SetTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+	}))
+	client.SetRateLimiter(NewRateLimiter(60, RateLimitFailFast))
+	for i := 0; i < 3; i++ {
+		client.Request("/info", InfoRequest{Type: "meta"})
+	}
+	_, err := client.Request("/info", InfoRequest{Type: "meta"})
+	var rateLimitErr RateLimitError
+	if !errors.As(err, &rateLimitErr) || requests != 3 {
+		t.Errorf("Request() error = %v after %d requests, want RateLimitError without sending the 4th request", err, requests)
+	}
+}
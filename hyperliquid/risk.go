@@ -0,0 +1,132 @@
+package hyperliquid
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// RiskRule is a custom check of a RiskGuard, it returns an error to reject the order.
+type RiskRule func(api *ExchangeAPI, req OrderRequest) error
+
+// RiskGuard rejects orders locally, before they are signed, when they break a risk limit.
+// Zero values disable a limit. Reduce only orders are only subject to the custom Rules.
+//
+//	api.SetRiskGuard(&RiskGuard{
+//		MaxOrderNotional: 10000,
+//		MaxPosition:      map[string]float64{"BTC": 0.5},
+//		MaxDailyLoss:     500,
+//		BannedCoins:      []string{"PURR/USDC"},
+//	})
+type RiskGuard struct {
+	MaxOrderNotional float64            // Maximum value of an order in USDC
+	MaxPosition      map[string]float64 // Maximum absolute position size per coin after the order
+	MaxDailyLoss     float64            // Maximum loss in USDC since 00:00 UTC (closed PnL net of fees)
+	BannedCoins      []string           // Coins that can't be traded
+	Rules            []RiskRule         // Custom rules checked after the limits
+}
+
+// RiskLimitError is returned when an order is rejected by the RiskGuard.
+// Rule is the name of the limit, e.g. "maxOrderNotional", or "custom" for the custom rules.
+type RiskLimitError struct {
+	Rule    string
+	Coin    string
+	Message string
+}
+
+func (e RiskLimitError) Error() string {
+	return fmt.Sprintf("Risk limit %s rejected %s order: %s", e.Rule, e.Coin, e.Message)
+}
+
+// SetRiskGuard sets the risk limits checked before placing or modifying orders.
+// Pass nil to disable the checks.
+func (api *ExchangeAPI) SetRiskGuard(guard *RiskGuard) {
+	api.riskGuard = guard
+}
+
+// RiskGuard returns the risk limits, nil if there are none.
+func (api *ExchangeAPI) RiskGuard() *RiskGuard {
+	return api.riskGuard
+}
+
+// checkRisk checks the orders against the RiskGuard of api if any.
+// The account state and fills are only requested if a limit needs them.
+func (api *ExchangeAPI) checkRisk(requests []OrderRequest) error {
+	guard := api.riskGuard
+	if guard == nil {
+		return nil
+	}
+	user := api.VaultAddress()
+	if user == "" {
+		user = api.AccountAddress()
+	}
+	var positions map[string]float64
+	var dailyLoss *float64
+	for _, req := range requests {
+		if !req.ReduceOnly {
+			for _, coin := range guard.BannedCoins {
+				if strings.EqualFold(coin, req.Coin) {
+					return RiskLimitError{Rule: "bannedCoins", Coin: req.Coin, Message: "coin is banned"}
+				}
+			}
+			if notional := req.Sz * req.LimitPx; guard.MaxOrderNotional > 0 && notional > guard.MaxOrderNotional {
+				return RiskLimitError{Rule: "maxOrderNotional", Coin: req.Coin, Message: fmt.Sprintf("value %v exceeds %v", notional, guard.MaxOrderNotional)}
+			}
+			if maxSz, ok := guard.MaxPosition[req.Coin]; ok {
+				if positions == nil {
+					state, err := api.infoAPI.GetUserState(user)
+					if err != nil {
+						return err
+					}
+					positions = map[string]float64{}
+					for _, position := range state.AssetPositions {
+						positions[position.Position.Coin] = position.Position.Szi
+					}
+				}
+				szi := positions[req.Coin] + req.Sz
+				if !req.IsBuy {
+					szi = positions[req.Coin] - req.Sz
+				}
+				if math.Abs(szi) > maxSz {
+					return RiskLimitError{Rule: "maxPosition", Coin: req.Coin, Message: fmt.Sprintf("position %v exceeds %v", szi, maxSz)}
+				}
+				// The next orders of the bulk add to the position of this one
+				positions[req.Coin] = szi
+			}
+			if guard.MaxDailyLoss > 0 {
+				if dailyLoss == nil {
+					loss, err := api.dailyLoss(user)
+					if err != nil {
+						return err
+					}
+					dailyLoss = &loss
+				}
+				if *dailyLoss >= guard.MaxDailyLoss {
+					return RiskLimitError{Rule: "maxDailyLoss", Coin: req.Coin, Message: fmt.Sprintf("daily loss %v reached %v", *dailyLoss, guard.MaxDailyLoss)}
+				}
+			}
+		}
+		for _, rule := range guard.Rules {
+			if err := rule(api, req); err != nil {
+				return RiskLimitError{Rule: "custom", Coin: req.Coin, Message: err.Error()}
+			}
+		}
+	}
+	return nil
+}
+
+// dailyLoss returns the loss of user since 00:00 UTC: the closed PnL net of fees, negated.
+func (api *ExchangeAPI) dailyLoss(user string) (float64, error) {
+	now := time.Now().UTC()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	fills, err := api.infoAPI.GetUserFillsByTime(user, startOfDay.UnixMilli(), now.UnixMilli())
+	if err != nil {
+		return 0, err
+	}
+	pnl := 0.0
+	for _, fill := range *fills {
+		pnl += fill.ClosedPnl - fill.Fee
+	}
+	return -pnl, nil
+}
@@ -0,0 +1,79 @@
+package hyperliquid
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRiskGuard_RejectsBeforeSigning(t *testing.T) {
+	sent := 0
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		sent++
+		statuses := []any{map[string]any{"resting": map[string]any{"oid": 1}}}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "order", "data": map[string]any{"statuses": statuses}}}
+	})
+	exchangeAPI.infoAPI = GetTestInfoAPI(t, func(req InfoRequest) any {
+		if req.Type == "userFillsByTime" {
+			return []any{
+				map[string]any{"coin": "ETH", "closedPnl": "-80", "fee": "1", "px": "2000", "sz": "1", "time": 1},
+				map[string]any{"coin": "ETH", "closedPnl": "30", "fee": "1", "px": "2000", "sz": "1", "time": 2},
+			}
+		}
+		return map[string]any{"assetPositions": []any{map[string]any{"type": "oneWay", "position": map[string]any{"coin": "ETH", "szi": "0.4"}}}}
+	})
+	errOdd := errors.New("odd size")
+	exchangeAPI.SetRiskGuard(&RiskGuard{
+		MaxOrderNotional: 1000,
+		MaxPosition:      map[string]float64{"ETH": 0.5},
+		BannedCoins:      []string{"PURR/USDC"},
+		Rules: []RiskRule{func(api *ExchangeAPI, req OrderRequest) error {
+			if req.Sz == 0.3 {
+				return errOdd
+			}
+			return nil
+		}},
+	})
+	limit := OrderType{Limit: &LimitOrderType{Tif: TifGtc}}
+	tests := []struct {
+		req  OrderRequest
+		rule string
+	}{
+		{OrderRequest{Coin: "PURR/USDC", IsBuy: true, Sz: 10, LimitPx: 0.2, OrderType: limit}, "bannedCoins"},
+		{OrderRequest{Coin: "ETH", IsBuy: true, Sz: 0.6, LimitPx: 2000, OrderType: limit}, "maxOrderNotional"},
+		{OrderRequest{Coin: "ETH", IsBuy: true, Sz: 0.2, LimitPx: 2000, OrderType: limit}, "maxPosition"},
+		{OrderRequest{Coin: "ETH", IsBuy: false, Sz: 0.3, LimitPx: 2000, OrderType: limit}, "custom"},
+		{OrderRequest{Coin: "ETH", IsBuy: false, Sz: 0.4, LimitPx: 2000, OrderType: limit}, ""},
+		// Reduce only orders are not subject to the limits
+		{OrderRequest{Coin: "ETH", IsBuy: true, Sz: 0.6, LimitPx: 2000, OrderType: limit, ReduceOnly: true}, ""},
+	}
+	for i, tt := range tests {
+		_, err := exchangeAPI.Order(tt.req, GroupingNa)
+		var riskErr RiskLimitError
+		if tt.rule == "" && err != nil || tt.rule != "" && (!errors.As(err, &riskErr) || riskErr.Rule != tt.rule) {
+			t.Errorf("Order(%d) error = %v, want rule %q", i, err, tt.rule)
+		}
+	}
+	if sent != 2 {
+		t.Errorf("sent %d orders, want 2", sent)
+	}
+	oid := 1
+	if _, err := exchangeAPI.ModifyOrder(ModifyOrderRequest{Oid: &oid, Order: tests[1].req}); err == nil {
+		t.Errorf("ModifyOrder() expected maxOrderNotional error")
+	}
+
+	exchangeAPI.SetRiskGuard(&RiskGuard{MaxDailyLoss: 50})
+	var riskErr RiskLimitError
+	if _, err := exchangeAPI.Order(tests[4].req, GroupingNa); !errors.As(err, &riskErr) || riskErr.Rule != "maxDailyLoss" {
+		t.Errorf("Order() error = %v, want maxDailyLoss with a loss of 52", err)
+	}
+	// The orders of a bulk add up against the position limit
+	exchangeAPI.SetRiskGuard(&RiskGuard{MaxPosition: map[string]float64{"ETH": 0.5}})
+	buy := OrderRequest{Coin: "ETH", IsBuy: true, Sz: 0.06, LimitPx: 2000, OrderType: limit}
+	if _, err := exchangeAPI.BulkOrders([]OrderRequest{buy, buy}, GroupingNa); !errors.As(err, &riskErr) || riskErr.Rule != "maxPosition" {
+		t.Errorf("BulkOrders() error = %v, want maxPosition for the second order", err)
+	}
+	exchangeAPI.SetRiskGuard(nil)
+	if _, err := exchangeAPI.Order(tests[1].req, GroupingNa); err != nil {
+		t.Errorf("Order() error = %v without risk guard", err)
+	}
+}
@@ -1,9 +1,11 @@
 package hyperliquid
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
@@ -14,21 +16,37 @@ import (
 
 // SignRequest is the implementation of EIP-712 typed data
 type SignRequest struct {
-	PrimaryType string
-	DType       []apitypes.Type
-	DTypeMsg    map[string]interface{}
-	IsMainNet   bool
-	DomainName  string
+	PrimaryType      string
+	DType            []apitypes.Type
+	DTypeMsg         map[string]interface{}
+	IsMainNet        bool
+	DomainName       string
+	SignatureChainID string // signatureChainId of a user signed action, defaults to the network chain
 }
 
-func (request *SignRequest) getChainId() *math.HexOrDecimal256 {
+func (request *SignRequest) getChainId() (*math.HexOrDecimal256, error) {
 	if request.DomainName == "HyperliquidSignTransaction" {
+		if request.SignatureChainID != "" {
+			return parseSignatureChainID(request.SignatureChainID)
+		}
 		if request.IsMainNet {
-			return math.NewHexOrDecimal256(int64(ARBITRUM_CHAIN_ID))
+			return math.NewHexOrDecimal256(int64(ARBITRUM_CHAIN_ID)), nil
 		}
-		return math.NewHexOrDecimal256(int64(ARBITRUM_TESTNET_CHAIN_ID))
+		return math.NewHexOrDecimal256(int64(ARBITRUM_TESTNET_CHAIN_ID)), nil
+	}
+	return math.NewHexOrDecimal256(int64(HYPERLIQUID_CHAIN_ID)), nil
+}
+
+// parseSignatureChainID parses the hex chain id of a user signed action.
+func parseSignatureChainID(signatureChainID string) (*math.HexOrDecimal256, error) {
+	chainID := new(math.HexOrDecimal256)
+	if !strings.HasPrefix(signatureChainID, "0x") {
+		return nil, APIError{Message: fmt.Sprintf("Invalid signatureChainId %q: not a hex chain id", signatureChainID)}
 	}
-	return math.NewHexOrDecimal256(int64(HYPERLIQUID_CHAIN_ID))
+	if err := chainID.UnmarshalText([]byte(signatureChainID)); err != nil {
+		return nil, APIError{Message: fmt.Sprintf("Invalid signatureChainId %q: %s", signatureChainID, err)}
+	}
+	return chainID, nil
 }
 
 func (request *SignRequest) GetTypes() apitypes.Types {
@@ -44,11 +62,14 @@ func (request *SignRequest) GetTypes() apitypes.Types {
 	return types
 }
 
+// GetDomain returns the EIP-712 domain of the request.
+// The chain id is nil if the signatureChainId is invalid, Sign returns the error.
 func (request *SignRequest) GetDomain() apitypes.TypedDataDomain {
+	chainID, _ := request.getChainId()
 	return apitypes.TypedDataDomain{
 		Name:              request.DomainName,
 		Version:           "1",
-		ChainId:           request.getChainId(),
+		ChainId:           chainID,
 		VerifyingContract: VERIFYING_CONTRACT,
 	}
 }
@@ -64,6 +85,10 @@ func NewSigner(manager *PKeyManager) Signer {
 }
 
 func (signer *Signer) Sign(request *SignRequest) (byte, [32]byte, [32]byte, error) {
+	// The action carries the signatureChainId, signing for another chain would be rejected
+	if _, err := request.getChainId(); err != nil {
+		return 0, [32]byte{}, [32]byte{}, err
+	}
 	return signer.signInternal(SignRequestToEIP712TypedData(request))
 }
 
@@ -104,10 +129,14 @@ func SignatureToVRS(sig []byte) (byte, [32]byte, [32]byte, error) {
 
 // Create a hash of an action (json object)
 func buildActionHash(action any, vaultAd string, nonce uint64) (common.Hash, error) {
-	data, err := msgpack.Marshal(action)
-	if err != nil {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	// Integers must be encoded in their most compact form to match the hash computed by the exchange
+	enc.UseCompactInts(true)
+	if err := enc.Encode(action); err != nil {
 		return common.Hash{}, fmt.Errorf("error while marshaling action: %s", err)
 	}
+	data := buf.Bytes()
 	nonceBytes := make([]byte, 8)
 	binary.BigEndian.PutUint64(nonceBytes, uint64(nonce))
 	data = ArrayAppend(data, nonceBytes)
@@ -0,0 +1,36 @@
+package hyperliquid
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Vector of test_l1_action_signing_matches in the Python SDK
+func TestSignature_L1ActionMatchesPythonSDK(t *testing.T) {
+	action := struct {
+		Type string `msgpack:"type" json:"type"`
+		Num  int64  `msgpack:"num" json:"num"`
+	}{Type: "dummy", Num: 100000000000}
+	testCases := []struct {
+		isMainnet bool
+		v         byte
+		r         string
+		s         string
+	}{
+		{true, 27, "053749d5b30552aeb2fca34b530185976545bb22d0b3ce6f62e31be961a59298", "755c40ba9bf05223521753995abb2f73ab3229be8ec921f350cb447e384d8ed8"},
+		{false, 28, "542af61ef1f429707e3c76c5293c80d01f74ef853e34b76efffcb57e574f9510", "17b8b32f086e8cdede991f1e2c529f5dd5297cbe8128500e00cbaf766204a613"},
+	}
+	for _, tc := range testCases {
+		api := &ExchangeAPI{Client: *NewClient(tc.isMainnet)}
+		if err := api.SetPrivateKey("0x0123456789012345678901234567890123456789012345678901234567890123"); err != nil {
+			t.Fatalf("SetPrivateKey() error = %v", err)
+		}
+		v, r, s, err := api.SignL1Action(action, 0)
+		if err != nil {
+			t.Fatalf("SignL1Action() error = %v", err)
+		}
+		if v != tc.v || fmt.Sprintf("%x", r) != tc.r || fmt.Sprintf("%x", s) != tc.s {
+			t.Errorf("SignL1Action(mainnet=%v) = %d %x %x, want %d %s %s", tc.isMainnet, v, r, s, tc.v, tc.r, tc.s)
+		}
+	}
+}
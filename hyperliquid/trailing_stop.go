@@ -0,0 +1,257 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+)
+
+// TrailingStopOptions configures how far the stop trails the best mark price.
+// Exactly one of Distance or Percent must be set.
+type TrailingStopOptions struct {
+	Distance float64 // Retrace from the best mark price in price units
+	Percent  float64 // Retrace from the best mark price as a fraction (0.01 = 1%)
+	Trigger  bool    // Keep a resting stop market trigger at the stop price instead of closing with a market order
+}
+
+// TrailingStop protects a position with a stop that follows the mark price.
+// The mark price is received from the activeAssetCtx stream; every time it makes a new high
+// (low for a short) the stop is moved to the best price minus the retrace.
+// With Trigger set a reduce only stop market order rests at the stop price and is modified as the stop moves,
+// otherwise the position is closed with a reduce only market order once the mark price crosses the stop.
+//
+//	stop, err := api.PlaceTrailingStop(ws, "ETH", 0.5, TrailingStopOptions{Percent: 0.02})
+//	<-stop.Done()
+type TrailingStop struct {
+	api        *ExchangeAPI
+	sub        *WsSubscriber
+	coin       string
+	szDecimals int
+	isLong     bool
+	size       float64
+	options    TrailingStopOptions
+	bestPx     float64
+	stopPx     float64
+	oid        int
+	fired      bool
+	err        error
+	done       chan struct{}
+	stop       chan struct{}
+	once       sync.Once
+	mu         sync.Mutex
+}
+
+// PlaceTrailingStop starts trailing a stop for a position of size coin.
+// The size is positive for a long position and negative for a short position.
+// ws must be connected.
+func (api *ExchangeAPI) PlaceTrailingStop(ws *WebSocketAPI, coin string, size float64, options TrailingStopOptions) (*TrailingStop, error) {
+	info, ok := api.meta[coin]
+	if !ok {
+		return nil, APIError{Message: fmt.Sprintf("Unknown coin: %s", coin)}
+	}
+	if size == 0 {
+		return nil, APIError{Message: "Invalid trailing stop size: 0"}
+	}
+	if (options.Distance > 0) == (options.Percent > 0) || options.Distance < 0 || options.Percent < 0 || options.Percent >= 1 {
+		return nil, APIError{Message: fmt.Sprintf("Invalid trailing stop options: %+v", options)}
+	}
+	// Only the latest mark price matters
+	sub, err := ws.SubscribeWithOptions(Subscription{Type: "activeAssetCtx", Coin: coin}, SubscriberOptions{BufferSize: 1, Policy: WsPolicyLatest})
+	if err != nil {
+		return nil, err
+	}
+	trailing := &TrailingStop{
+		api:        api,
+		sub:        sub,
+		coin:       coin,
+		szDecimals: info.SzDecimals,
+		isLong:     IsBuy(size),
+		size:       math.Abs(size),
+		options:    options,
+		done:       make(chan struct{}),
+		stop:       make(chan struct{}),
+	}
+	go trailing.run()
+	return trailing, nil
+}
+
+// StopPx returns the current stop price, 0 until the first mark price is received.
+func (trailing *TrailingStop) StopPx() float64 {
+	trailing.mu.Lock()
+	defer trailing.mu.Unlock()
+	return trailing.stopPx
+}
+
+// Oid returns the id of the resting trigger order, 0 without Trigger.
+func (trailing *TrailingStop) Oid() int {
+	trailing.mu.Lock()
+	defer trailing.mu.Unlock()
+	return trailing.oid
+}
+
+// Done is closed once the stop fired, on error, or after Cancel or Close.
+func (trailing *TrailingStop) Done() <-chan struct{} {
+	return trailing.done
+}
+
+// Result returns true if the stop fired and the error that stopped the trailing if any.
+func (trailing *TrailingStop) Result() (bool, error) {
+	trailing.mu.Lock()
+	defer trailing.mu.Unlock()
+	return trailing.fired, trailing.err
+}
+
+// Cancel stops trailing and cancels the resting trigger order if any.
+func (trailing *TrailingStop) Cancel() error {
+	trailing.Close()
+	trailing.mu.Lock()
+	oid, fired := trailing.oid, trailing.fired
+	trailing.mu.Unlock()
+	if oid != 0 && !fired {
+		_, err := trailing.api.CancelOrderByOID(trailing.coin, oid)
+		return err
+	}
+	return nil
+}
+
+// Close stops trailing without canceling the resting trigger order.
+func (trailing *TrailingStop) Close() error {
+	trailing.once.Do(func() { close(trailing.stop) })
+	<-trailing.done
+	return nil
+}
+
+// run updates the stop on every mark price until it fires.
+func (trailing *TrailingStop) run() {
+	defer close(trailing.done)
+	defer trailing.sub.Unsubscribe()
+	for {
+		select {
+		case msg, ok := <-trailing.sub.C():
+			if !ok {
+				return
+			}
+			var data WsActiveAssetCtx
+			if err := json.Unmarshal(msg.Data, &data); err != nil {
+				trailing.api.debug("Error parsing asset context: %s", err)
+				continue
+			}
+			if data.Ctx.MarkPx <= 0 {
+				continue
+			}
+			if done, err := trailing.update(data.Ctx.MarkPx); err != nil || done {
+				trailing.mu.Lock()
+				trailing.err = err
+				trailing.mu.Unlock()
+				return
+			}
+		case <-trailing.stop:
+			return
+		}
+	}
+}
+
+// update moves the stop for the mark price markPx and returns true once the stop fired.
+func (trailing *TrailingStop) update(markPx float64) (bool, error) {
+	trailing.mu.Lock()
+	stopPx, oid := trailing.stopPx, trailing.oid
+	trailing.mu.Unlock()
+	if stopPx != 0 && (trailing.isLong && markPx <= stopPx || !trailing.isLong && markPx >= stopPx) {
+		return true, trailing.fire(markPx)
+	}
+	if trailing.bestPx != 0 && (trailing.isLong && markPx <= trailing.bestPx || !trailing.isLong && markPx >= trailing.bestPx) {
+		return false, nil
+	}
+	trailing.bestPx = markPx
+	retrace := trailing.options.Distance
+	if retrace == 0 {
+		retrace = markPx * trailing.options.Percent
+	}
+	newStopPx := markPx - retrace
+	if !trailing.isLong {
+		newStopPx = markPx + retrace
+	}
+	// Prices are rounded to the exchange precision, skip moves below it
+	newStopPx, _ = strconv.ParseFloat(PriceToWire(newStopPx, PERP_MAX_DECIMALS, trailing.szDecimals), 64)
+	if newStopPx == stopPx {
+		return false, nil
+	}
+	if trailing.options.Trigger {
+		var err error
+		if oid, err = trailing.placeTrigger(oid, newStopPx); err != nil {
+			return false, err
+		}
+	}
+	trailing.mu.Lock()
+	trailing.stopPx, trailing.oid = newStopPx, oid
+	trailing.mu.Unlock()
+	return false, nil
+}
+
+// placeTrigger places the stop trigger order at stopPx, or modifies it if oid is set,
+// and returns the id of the resting order.
+func (trailing *TrailingStop) placeTrigger(oid int, stopPx float64) (int, error) {
+	request := OrderRequest{
+		Coin:  trailing.coin,
+		IsBuy: !trailing.isLong,
+		Sz:    trailing.size,
+		// The limit price of a market trigger order is its maximum slippage
+		LimitPx: CalculateSlippage(!trailing.isLong, stopPx, DEFAULT_SLIPPAGE),
+		OrderType: OrderType{
+			Trigger: &TriggerOrderType{
+				IsMarket:  true,
+				TriggerPx: PriceToWire(stopPx, PERP_MAX_DECIMALS, trailing.szDecimals),
+				TpSl:      TriggerSl,
+			},
+		},
+		ReduceOnly: true,
+	}
+	var res *OrderResponse
+	var err error
+	if oid == 0 {
+		res, err = trailing.api.Order(request, GroupingNa)
+	} else {
+		res, err = trailing.api.BatchModifyOrders([]ModifyOrderRequest{{Oid: &oid, Order: request}})
+	}
+	if err != nil {
+		return oid, err
+	}
+	if statuses := res.Response.Data.Statuses; len(statuses) > 0 {
+		if err := statuses[0].Err(); err != nil {
+			return oid, err
+		}
+		// A modified order gets a new oid
+		if newOid := statuses[0].OrderID(); newOid != 0 {
+			oid = newOid
+		}
+	}
+	return oid, nil
+}
+
+// fire records that the stop was crossed at markPx and, without Trigger, closes the position.
+// With Trigger the resting trigger order is executed by the exchange.
+func (trailing *TrailingStop) fire(markPx float64) error {
+	trailing.mu.Lock()
+	trailing.fired = true
+	trailing.mu.Unlock()
+	if trailing.options.Trigger {
+		return nil
+	}
+	res, err := trailing.api.Order(OrderRequest{
+		Coin:       trailing.coin,
+		IsBuy:      !trailing.isLong,
+		Sz:         trailing.size,
+		LimitPx:    CalculateSlippage(!trailing.isLong, markPx, DEFAULT_SLIPPAGE),
+		OrderType:  OrderType{Limit: &LimitOrderType{Tif: TifIoc}},
+		ReduceOnly: true,
+	}, GroupingNa)
+	if err != nil {
+		return err
+	}
+	if statuses := res.Response.Data.Statuses; len(statuses) > 0 {
+		return statuses[0].Err()
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// sendMarkPx sends an activeAssetCtx message with the mark price px.
+func sendMarkPx(t *testing.T, conn *websocket.Conn, px string) {
+	msg := map[string]any{"channel": "activeAssetCtx", "data": map[string]any{"coin": "ETH", "ctx": map[string]any{"markPx": px}}}
+	if err := conn.WriteJSON(msg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitStopPx waits until the stop price of trailing is px.
+func waitStopPx(t *testing.T, trailing *TrailingStop, px float64) {
+	deadline := time.Now().Add(5 * time.Second)
+	for trailing.StopPx() != px {
+		if time.Now().After(deadline) {
+			t.Fatalf("StopPx() = %v, want %v", trailing.StopPx(), px)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestTrailingStop_Trigger(t *testing.T) {
+	subscribed := make(chan *websocket.Conn, 1)
+	server := newTestWsServer(t, func(conn *websocket.Conn, req WsRequest) any {
+		if req.Method == "subscribe" {
+			subscribed <- conn
+		}
+		return nil
+	})
+	ws := GetTestWebSocketAPI(t, server)
+	actions := make(chan json.RawMessage, 10)
+	oid := 0
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		actions <- req.Action
+		oid++
+		statuses := []any{map[string]any{"resting": map[string]any{"oid": oid}}}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "order", "data": map[string]any{"statuses": statuses}}}
+	})
+	if _, err := exchangeAPI.PlaceTrailingStop(ws, "ETH", 0.5, TrailingStopOptions{Distance: 10, Percent: 0.01}); err == nil {
+		t.Errorf("PlaceTrailingStop() expected invalid options error")
+	}
+	trailing, err := exchangeAPI.PlaceTrailingStop(ws, "ETH", 0.5, TrailingStopOptions{Distance: 10, Trigger: true})
+	if err != nil {
+		t.Fatalf("PlaceTrailingStop() error = %v", err)
+	}
+	defer trailing.Close()
+	conn := <-subscribed
+
+	sendMarkPx(t, conn, "2000")
+	waitStopPx(t, trailing, 1990)
+	var place PlaceOrderAction
+	json.Unmarshal(<-actions, &place)
+	if order := place.Orders[0]; order.IsBuy || !order.ReduceOnly || order.OrderType.Trigger.TriggerPx != "1990" || order.OrderType.Trigger.TpSl != TriggerSl {
+		t.Errorf("order = %+v, want a reduce only sell stop at 1990", order)
+	}
+	sendMarkPx(t, conn, "2010")
+	waitStopPx(t, trailing, 2000)
+	var modify ModifyOrderAction
+	json.Unmarshal(<-actions, &modify)
+	if modify.Type != "batchModify" || modify.Modifies[0].OrderID != 1 || modify.Modifies[0].Order.OrderType.Trigger.TriggerPx != "2000" {
+		t.Errorf("action = %+v, want a modify of oid 1 to 2000", modify)
+	}
+	if trailing.Oid() != 2 {
+		t.Errorf("Oid() = %d, want the oid 2 of the modified order", trailing.Oid())
+	}
+	sendMarkPx(t, conn, "1999")
+	select {
+	case <-trailing.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the stop to fire")
+	}
+	if fired, err := trailing.Result(); !fired || err != nil {
+		t.Errorf("Result() = %v, %v, want true, nil", fired, err)
+	}
+	if len(actions) != 0 {
+		t.Errorf("sent %d more actions, want the resting trigger to close the position", len(actions))
+	}
+}
+
+func TestTrailingStop_Market(t *testing.T) {
+	subscribed := make(chan *websocket.Conn, 1)
+	server := newTestWsServer(t, func(conn *websocket.Conn, req WsRequest) any {
+		if req.Method == "subscribe" {
+			subscribed <- conn
+		}
+		return nil
+	})
+	ws := GetTestWebSocketAPI(t, server)
+	var orders []OrderWire
+	exchangeAPI := GetTestExchangeAPI(t, func(req TestExchangeRequest) any {
+		var action PlaceOrderAction
+		json.Unmarshal(req.Action, &action)
+		orders = append(orders, action.Orders...)
+		statuses := []any{map[string]any{"filled": map[string]any{"oid": 1, "avgPx": "2010", "totalSz": "0.5"}}}
+		return map[string]any{"status": "ok", "response": map[string]any{"type": "order", "data": map[string]any{"statuses": statuses}}}
+	})
+	trailing, err := exchangeAPI.PlaceTrailingStop(ws, "ETH", -0.5, TrailingStopOptions{Percent: 0.01})
+	if err != nil {
+		t.Fatalf("PlaceTrailingStop() error = %v", err)
+	}
+	defer trailing.Close()
+	conn := <-subscribed
+
+	sendMarkPx(t, conn, "2000")
+	waitStopPx(t, trailing, 2020)
+	sendMarkPx(t, conn, "1990")
+	waitStopPx(t, trailing, 2009.9)
+	sendMarkPx(t, conn, "2010")
+	select {
+	case <-trailing.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the stop to fire")
+	}
+	if fired, err := trailing.Result(); !fired || err != nil {
+		t.Errorf("Result() = %v, %v, want true, nil", fired, err)
+	}
+	if len(orders) != 1 || !orders[0].IsBuy || !orders[0].ReduceOnly || orders[0].OrderType.Limit.Tif != TifIoc || orders[0].SizePx != "0.5" {
+		t.Errorf("orders = %+v, want a single reduce only IOC buy of 0.5", orders)
+	}
+}
@@ -0,0 +1,289 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EnableBackfill makes the websocket restore the user events missed while it was
+// disconnected. After a reconnect the userFills, orderUpdates and
+// userNonFundingLedgerUpdates subscriptions are backfilled from the /info endpoint
+// and the missing events are delivered on the same channels.
+// Events already delivered (by tid, oid and status, or hash) are never delivered twice.
+// The events are backfilled from the time of the subscription, or of EnableBackfill for the existing ones.
+func (ws *WebSocketAPI) EnableBackfill(infoAPI *InfoAPI) {
+	backfill := &wsBackfill{
+		ws:       ws,
+		infoAPI:  infoAPI,
+		lastTime: make(map[string]int64),
+		seen:     make(map[string]struct{}),
+	}
+	ws.subMu.Lock()
+	defer ws.subMu.Unlock()
+	for _, sub := range ws.subscriptions {
+		backfill.watch(sub.subscription)
+	}
+	ws.backfill = backfill
+}
+
+// wsBackfill tracks the user events delivered on the websocket
+// so the ones missed during a disconnection can be restored.
+type wsBackfill struct {
+	ws        *WebSocketAPI
+	infoAPI   *InfoAPI
+	mu        sync.Mutex
+	lastTime  map[string]int64    // time of the last event by channel and user
+	orderUser string              // user of the orderUpdates subscription, its messages don't carry it
+	seen      map[string]struct{} // ids of the delivered events
+	order     []string            // ids in the order they were seen, oldest first
+}
+
+// wsRawFills is WsUserFills with the fills kept as raw json.
+type wsRawFills struct {
+	IsSnapshot bool              `json:"isSnapshot,omitempty"`
+	User       string            `json:"user"`
+	Fills      []json.RawMessage `json:"fills"`
+}
+
+// wsRawLedgerUpdates is WsUserNonFundingLedgerUpdates with the updates kept as raw json.
+type wsRawLedgerUpdates struct {
+	IsSnapshot              bool              `json:"isSnapshot,omitempty"`
+	User                    string            `json:"user"`
+	NonFundingLedgerUpdates []json.RawMessage `json:"nonFundingLedgerUpdates"`
+}
+
+func backfillKey(channel string, user string) string {
+	return channel + "|" + strings.ToLower(user)
+}
+
+// watch records the time a subscription started as the initial watermark of its backfill,
+// so the events missed before its first event are restored as well.
+// Must be called with the subMu of the websocket held.
+func (b *wsBackfill) watch(subscription Subscription) {
+	switch subscription.Type {
+	case "userFills", "orderUpdates", "userNonFundingLedgerUpdates":
+	default:
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if subscription.Type == "orderUpdates" {
+		b.orderUser = subscription.User
+	}
+	key := backfillKey(subscription.Type, subscription.User)
+	if b.lastTime[key] == 0 {
+		b.lastTime[key] = time.Now().UnixMilli()
+	}
+}
+
+// markSeen records the event id and returns false if it was already delivered.
+// Must be called with mu held.
+func (b *wsBackfill) markSeen(id string) bool {
+	if _, ok := b.seen[id]; ok {
+		return false
+	}
+	b.seen[id] = struct{}{}
+	b.order = append(b.order, id)
+	if len(b.order) > WS_BACKFILL_DEDUP_SIZE {
+		delete(b.seen, b.order[0])
+		b.order = b.order[1:]
+	}
+	return true
+}
+
+// observe records the time of an event.
+// Must be called with mu held.
+func (b *wsBackfill) observe(key string, time int64) {
+	if time > b.lastTime[key] {
+		b.lastTime[key] = time
+	}
+}
+
+// filter removes the events that were already delivered from a message.
+// Returns false if nothing is left to deliver.
+func (b *wsBackfill) filter(msg *WsMessage) (*WsMessage, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch msg.Channel {
+	case "userFills":
+		var data wsRawFills
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			return msg, true
+		}
+		key := backfillKey(msg.Channel, data.User)
+		fills := data.Fills[:0]
+		for _, raw := range data.Fills {
+			var fill struct {
+				Tid  int64 `json:"tid"`
+				Time int64 `json:"time"`
+			}
+			json.Unmarshal(raw, &fill)
+			if !b.markSeen(fmt.Sprintf("fill:%d", fill.Tid)) {
+				continue
+			}
+			b.observe(key, fill.Time)
+			fills = append(fills, raw)
+		}
+		if len(fills) == 0 {
+			return nil, false
+		}
+		data.Fills = fills
+		return b.remarshal(msg, data)
+	case "orderUpdates":
+		var updates []json.RawMessage
+		if err := json.Unmarshal(msg.Data, &updates); err != nil {
+			return msg, true
+		}
+		key := backfillKey(msg.Channel, b.orderUser)
+		filtered := updates[:0]
+		for _, raw := range updates {
+			var update struct {
+				Order struct {
+					Oid int64 `json:"oid"`
+				} `json:"order"`
+				Status          string `json:"status"`
+				StatusTimestamp int64  `json:"statusTimestamp"`
+			}
+			json.Unmarshal(raw, &update)
+			if !b.markSeen(fmt.Sprintf("order:%d:%s", update.Order.Oid, update.Status)) {
+				continue
+			}
+			b.observe(key, update.StatusTimestamp)
+			filtered = append(filtered, raw)
+		}
+		if len(filtered) == 0 {
+			return nil, false
+		}
+		return b.remarshal(msg, filtered)
+	case "userNonFundingLedgerUpdates":
+		var data wsRawLedgerUpdates
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			return msg, true
+		}
+		key := backfillKey(msg.Channel, data.User)
+		updates := data.NonFundingLedgerUpdates[:0]
+		for _, raw := range data.NonFundingLedgerUpdates {
+			var update struct {
+				Hash string `json:"hash"`
+				Time int64  `json:"time"`
+			}
+			json.Unmarshal(raw, &update)
+			if !b.markSeen(fmt.Sprintf("ledger:%s:%d", update.Hash, update.Time)) {
+				continue
+			}
+			b.observe(key, update.Time)
+			updates = append(updates, raw)
+		}
+		if len(updates) == 0 {
+			return nil, false
+		}
+		data.NonFundingLedgerUpdates = updates
+		return b.remarshal(msg, data)
+	}
+	return msg, true
+}
+
+func (b *wsBackfill) remarshal(msg *WsMessage, data any) (*WsMessage, bool) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return msg, true
+	}
+	return &WsMessage{Channel: msg.Channel, Data: raw}, true
+}
+
+// since returns the time of the last delivered event for the key, 0 if none.
+func (b *wsBackfill) since(key string) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.lastTime[key]
+}
+
+// run fetches the events missed by every user subscription and delivers them.
+func (b *wsBackfill) run() {
+	b.ws.subMu.RLock()
+	var subscriptions []Subscription
+	for _, sub := range b.ws.subscriptions {
+		subscriptions = append(subscriptions, sub.subscription)
+	}
+	b.ws.subMu.RUnlock()
+
+	now := time.Now().UnixMilli()
+	for _, subscription := range subscriptions {
+		var err error
+		switch subscription.Type {
+		case "userFills":
+			err = b.backfillFills(subscription.User, now)
+		case "orderUpdates":
+			err = b.backfillOrders(subscription.User)
+		case "userNonFundingLedgerUpdates":
+			err = b.backfillLedger(subscription.User, now)
+		}
+		if err != nil {
+			b.ws.debug("Error backfilling %+v: %s", subscription, err)
+		}
+	}
+}
+
+func (b *wsBackfill) backfillFills(user string, now int64) error {
+	start := b.since(backfillKey("userFills", user))
+	if start == 0 {
+		return nil
+	}
+	fills, err := b.infoAPI.GetAllUserFillsByTime(user, start, now)
+	if err != nil {
+		return err
+	}
+	if len(*fills) == 0 {
+		return nil
+	}
+	return b.inject("userFills", WsUserFills{User: user, Fills: *fills})
+}
+
+func (b *wsBackfill) backfillOrders(user string) error {
+	start := b.since(backfillKey("orderUpdates", user))
+	if start == 0 {
+		return nil
+	}
+	orders, err := b.infoAPI.GetHistoricalOrders(user)
+	if err != nil {
+		return err
+	}
+	var missed []HistoricalOrder
+	for _, order := range *orders {
+		if order.StatusTimestamp >= start {
+			missed = append(missed, order)
+		}
+	}
+	if len(missed) == 0 {
+		return nil
+	}
+	return b.inject("orderUpdates", missed)
+}
+
+func (b *wsBackfill) backfillLedger(user string, now int64) error {
+	start := b.since(backfillKey("userNonFundingLedgerUpdates", user))
+	if start == 0 {
+		return nil
+	}
+	updates, err := b.infoAPI.GetNonFundingUpdates(user, start, now)
+	if err != nil {
+		return err
+	}
+	if len(*updates) == 0 {
+		return nil
+	}
+	return b.inject("userNonFundingLedgerUpdates", WsUserNonFundingLedgerUpdates{User: user, NonFundingLedgerUpdates: *updates})
+}
+
+// inject delivers backfilled events as if they were received on the channel.
+func (b *wsBackfill) inject(channel string, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	b.ws.deliver(&WsMessage{Channel: channel, Data: raw})
+	return nil
+}
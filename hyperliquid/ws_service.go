@@ -0,0 +1,615 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// WebSocketAPI is a client for the Hyperliquid websocket service.
+//
+// It keeps a single connection open, answers post requests by id,
+// fans subscription messages out to subscribers and
+// keeps the connection alive with periodic pings.
+type WebSocketAPI struct {
+	Client
+	url         string
+	conn        *websocket.Conn
+	writeMu     sync.Mutex                     // serializes writes to conn
+	mu          sync.Mutex                     // guards conn, pending and done
+	pending     map[int64]chan *WsPostResponse // post requests waiting for a response
+	nextID      int64
+	done        chan struct{}
+	closed      bool          // set by Close, stops reconnecting
+	backfill    *wsBackfill   // restores missed user events after a reconnect
+	PostTimeout time.Duration // Timeout for post requests
+
+	AutoReconnect bool // Reconnect and resubscribe when the connection drops
+
+	subMu         sync.RWMutex               // guards subscriptions
+	subscriptions map[string]*wsSubscription // active subscriptions by key
+
+	SubscriberOptions SubscriberOptions // Default options used by Subscribe
+}
+
+// wsSubscription is a single subscription on the connection shared by all its subscribers.
+type wsSubscription struct {
+	subscription Subscription
+	subscribers  map[int64]*WsSubscriber
+}
+
+// WsSubscriber receives the messages of one or more subscriptions.
+// Several subscribers can share the same subscription; the subscribe message
+// is sent once and every message is delivered to each subscriber.
+type WsSubscriber struct {
+	ws            *WebSocketAPI
+	id            int64
+	subscriptions []Subscription
+	ch            chan WsMessage
+	policy        WsBufferPolicy
+	dropped       uint64
+	done          chan struct{}
+	once          sync.Once
+	mu            sync.Mutex // serializes push with the close of ch
+	closed        bool
+}
+
+// C returns the channel the subscription messages are delivered on.
+// The channel is closed after Unsubscribe.
+func (s *WsSubscriber) C() <-chan WsMessage {
+	return s.ch
+}
+
+// Subscription returns the (first) subscription the subscriber is attached to.
+func (s *WsSubscriber) Subscription() Subscription {
+	return s.subscriptions[0]
+}
+
+// Subscriptions returns all the subscriptions the subscriber is attached to.
+func (s *WsSubscriber) Subscriptions() []Subscription {
+	return s.subscriptions
+}
+
+// Dropped returns the number of messages dropped because the subscriber's buffer was full.
+func (s *WsSubscriber) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// push delivers a message to the subscriber according to its buffer policy.
+// Messages pushed after Unsubscribe are discarded.
+func (s *WsSubscriber) push(msg WsMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	switch s.policy {
+	case WsPolicyDrop:
+		select {
+		case s.ch <- msg:
+		default:
+			atomic.AddUint64(&s.dropped, 1)
+		}
+	case WsPolicyLatest:
+		for {
+			select {
+			case s.ch <- msg:
+				return
+			default:
+			}
+			// Buffer is full: discard the oldest message and try again.
+			select {
+			case <-s.ch:
+				atomic.AddUint64(&s.dropped, 1)
+			default:
+			}
+		}
+	default:
+		select {
+		case s.ch <- msg:
+		case <-s.done:
+		}
+	}
+}
+
+// close closes the channel once the pending push, unblocked by done, returned.
+func (s *WsSubscriber) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	close(s.ch)
+}
+
+// Unsubscribe detaches the subscriber and closes its channel.
+// The unsubscribe message is sent when the last subscriber of a subscription leaves.
+func (s *WsSubscriber) Unsubscribe() error {
+	var err error
+	s.once.Do(func() {
+		close(s.done)
+		err = s.ws.unsubscribe(s)
+	})
+	return err
+}
+
+// getWsURL returns the websocket URL based on the network type.
+func getWsURL(isMainnet bool) string {
+	if isMainnet {
+		return MAINNET_WS_URL
+	}
+	return TESTNET_WS_URL
+}
+
+// NewWebSocketAPI returns a new instance of the WebSocketAPI struct.
+// Run Connect() before sending any requests.
+func NewWebSocketAPI(isMainnet bool) *WebSocketAPI {
+	return &WebSocketAPI{
+		Client:        *NewClient(isMainnet),
+		url:           getWsURL(isMainnet),
+		pending:       make(map[int64]chan *WsPostResponse),
+		PostTimeout:   WS_POST_TIMEOUT,
+		AutoReconnect: true,
+		subscriptions: make(map[string]*wsSubscription),
+	}
+}
+
+// Endpoint returns the websocket URL.
+func (ws *WebSocketAPI) Endpoint() string {
+	return ws.url
+}
+
+// IsConnected returns true if the websocket connection is open.
+func (ws *WebSocketAPI) IsConnected() bool {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return ws.conn != nil
+}
+
+// Connect opens the websocket connection and starts the read and ping loops.
+func (ws *WebSocketAPI) Connect() error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.closed = false
+	return ws.open()
+}
+
+// open dials the websocket and starts the read and ping loops.
+// Must be called with mu held.
+func (ws *WebSocketAPI) open() error {
+	if ws.conn != nil {
+		return nil
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(ws.url, nil)
+	if err != nil {
+		ws.debug("Error websocket.Dial: %s", err)
+		return err
+	}
+	ws.conn = conn
+	ws.done = make(chan struct{})
+	go ws.readLoop(conn, ws.done)
+	go ws.pingLoop(ws.done)
+	return nil
+}
+
+// reconnect dials again with an exponential backoff until it succeeds or Close is called.
+// Active subscriptions are sent again and missed user events are backfilled.
+func (ws *WebSocketAPI) reconnect() {
+	delay := WS_RECONNECT_MIN_DELAY
+	for {
+		ws.mu.Lock()
+		if ws.closed {
+			ws.mu.Unlock()
+			return
+		}
+		err := ws.open()
+		ws.mu.Unlock()
+		if err == nil {
+			break
+		}
+		time.Sleep(delay)
+		delay = min(delay*2, WS_RECONNECT_MAX_DELAY)
+	}
+	ws.debug("Websocket reconnected to %s", ws.url)
+	ws.resubscribe()
+	if ws.backfill != nil {
+		go ws.backfill.run()
+	}
+}
+
+// resubscribe sends the subscribe message of every active subscription.
+func (ws *WebSocketAPI) resubscribe() {
+	ws.subMu.RLock()
+	defer ws.subMu.RUnlock()
+	for _, sub := range ws.subscriptions {
+		if err := ws.send(WsRequest{Method: "subscribe", Subscription: &sub.subscription}); err != nil {
+			ws.debug("Error resubscribing to %+v: %s", sub.subscription, err)
+		}
+	}
+}
+
+// Close closes the websocket connection.
+// Pending post requests are released with an error.
+func (ws *WebSocketAPI) Close() error {
+	ws.mu.Lock()
+	ws.closed = true
+	conn := ws.conn
+	ws.mu.Unlock()
+	if conn == nil {
+		return nil
+	}
+	ws.writeMu.Lock()
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	ws.writeMu.Unlock()
+	err := conn.Close()
+	ws.disconnect(conn)
+	return err
+}
+
+// disconnect drops the given connection and fails all pending post requests.
+// Returns true if the connection was lost rather than closed by Close.
+func (ws *WebSocketAPI) disconnect(conn *websocket.Conn) bool {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if ws.conn != conn {
+		return false
+	}
+	ws.conn = nil
+	close(ws.done)
+	for id, ch := range ws.pending {
+		close(ch)
+		delete(ws.pending, id)
+	}
+	return !ws.closed
+}
+
+// send writes a message to the websocket connection.
+func (ws *WebSocketAPI) send(message any) error {
+	ws.mu.Lock()
+	conn := ws.conn
+	ws.mu.Unlock()
+	if conn == nil {
+		return APIError{Message: "Websocket not connected"}
+	}
+	ws.writeMu.Lock()
+	defer ws.writeMu.Unlock()
+	return conn.WriteJSON(message)
+}
+
+// readLoop reads messages until the connection is closed.
+func (ws *WebSocketAPI) readLoop(conn *websocket.Conn, done chan struct{}) {
+	defer func() {
+		conn.Close()
+		if ws.disconnect(conn) && ws.AutoReconnect {
+			go ws.reconnect()
+		}
+	}()
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			select {
+			case <-done:
+			default:
+				ws.debug("Error websocket read: %s", err)
+			}
+			return
+		}
+		var msg WsMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			ws.debug("Error json.Unmarshal: %s", err)
+			continue
+		}
+		ws.dispatch(&msg)
+	}
+}
+
+// pingLoop sends a ping periodically so the server does not drop the connection.
+func (ws *WebSocketAPI) pingLoop(done chan struct{}) {
+	ticker := time.NewTicker(WS_PING_INTERVAL)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := ws.send(WsRequest{Method: "ping"}); err != nil {
+				ws.debug("Error sending ping: %s", err)
+			}
+		}
+	}
+}
+
+// dispatch routes a received message to its consumer.
+func (ws *WebSocketAPI) dispatch(msg *WsMessage) {
+	switch msg.Channel {
+	case "post":
+		var response WsPostResponse
+		if err := json.Unmarshal(msg.Data, &response); err != nil {
+			ws.debug("Error json.Unmarshal post response: %s", err)
+			return
+		}
+		ws.mu.Lock()
+		ch, ok := ws.pending[response.ID]
+		delete(ws.pending, response.ID)
+		ws.mu.Unlock()
+		if ok {
+			ch <- &response
+		}
+	case "pong", "subscriptionResponse":
+	case "error":
+		ws.debug("Websocket error: %s", string(msg.Data))
+	default:
+		ws.deliver(msg)
+	}
+}
+
+// deliver fans a subscription message out to every matching subscriber.
+func (ws *WebSocketAPI) deliver(msg *WsMessage) {
+	if ws.backfill != nil {
+		var ok bool
+		if msg, ok = ws.backfill.filter(msg); !ok {
+			return
+		}
+	}
+	routing := parseWsRouting(msg.Data)
+	// Collect the subscribers under the lock and push without it, a blocking push
+	// must not prevent the subscriber from unsubscribing.
+	ws.subMu.RLock()
+	delivered := make(map[int64]bool)
+	var subscribers []*WsSubscriber
+	for _, sub := range ws.subscriptions {
+		if !sub.subscription.matches(msg.Channel, routing) {
+			continue
+		}
+		for _, subscriber := range sub.subscribers {
+			// A subscriber attached to several matching subscriptions gets the message once.
+			if delivered[subscriber.id] {
+				continue
+			}
+			delivered[subscriber.id] = true
+			subscribers = append(subscribers, subscriber)
+		}
+	}
+	ws.subMu.RUnlock()
+	if len(subscribers) == 0 {
+		ws.debug("Unhandled websocket message: %s", msg.Channel)
+	}
+	for _, subscriber := range subscribers {
+		subscriber.push(*msg)
+	}
+}
+
+// Subscribe attaches a new subscriber to the given subscription.
+// The subscribe message is only sent for the first subscriber of a subscription,
+// later subscribers share the same stream.
+//
+// The subscriber is configured with the default SubscriberOptions of the client.
+//
+//	sub, err := ws.Subscribe(Subscription{Type: "l2Book", Coin: "BTC"})
+//	for msg := range sub.C() { ... }
+func (ws *WebSocketAPI) Subscribe(subscription Subscription) (*WsSubscriber, error) {
+	return ws.SubscribeWithOptions(subscription, ws.SubscriberOptions)
+}
+
+// SubscribeWithOptions is the same as Subscribe but with custom buffering for this subscriber.
+//
+//	sub, err := ws.SubscribeWithOptions(Subscription{Type: "allMids"}, SubscriberOptions{BufferSize: 1, Policy: WsPolicyLatest})
+func (ws *WebSocketAPI) SubscribeWithOptions(subscription Subscription, options SubscriberOptions) (*WsSubscriber, error) {
+	return ws.SubscribeMany([]Subscription{subscription}, options)
+}
+
+// SubscribeMany attaches a single subscriber to several subscriptions.
+// Messages of all the subscriptions are delivered on one channel in the order
+// they were received, which is not guaranteed across separate subscribers.
+//
+//	sub, err := ws.SubscribeMany([]Subscription{
+//		{Type: "orderUpdates", User: address},
+//		{Type: "userFills", User: address},
+//	}, SubscriberOptions{})
+//
+// The orderUpdates, userEvents and notification messages don't carry the user, so they can
+// only be subscribed for one user per connection. Use a WebSocketAPI per user to follow several users.
+func (ws *WebSocketAPI) SubscribeMany(subscriptions []Subscription, options SubscriberOptions) (*WsSubscriber, error) {
+	if len(subscriptions) == 0 {
+		return nil, APIError{Message: "No subscriptions provided"}
+	}
+	bufferSize := options.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = WS_SUBSCRIBER_BUFFER
+	}
+	subscriber := &WsSubscriber{
+		ws:            ws,
+		id:            atomic.AddInt64(&ws.nextID, 1),
+		subscriptions: subscriptions,
+		ch:            make(chan WsMessage, bufferSize),
+		policy:        options.Policy,
+		done:          make(chan struct{}),
+	}
+
+	ws.subMu.Lock()
+	defer ws.subMu.Unlock()
+	if err := ws.checkUserless(subscriptions); err != nil {
+		return nil, err
+	}
+	for i := range subscriptions {
+		subscription := subscriptions[i]
+		key := subscription.key()
+		sub, ok := ws.subscriptions[key]
+		if !ok {
+			err := ws.send(WsRequest{Method: "subscribe", Subscription: &subscription})
+			if err != nil {
+				ws.debug("Error subscribing to %+v: %s", subscription, err)
+				ws.detach(subscriber, subscriptions[:i])
+				return nil, err
+			}
+			sub = &wsSubscription{
+				subscription: subscription,
+				subscribers:  make(map[int64]*WsSubscriber),
+			}
+			ws.subscriptions[key] = sub
+			if ws.backfill != nil {
+				ws.backfill.watch(subscription)
+			}
+		}
+		sub.subscribers[subscriber.id] = subscriber
+	}
+	return subscriber, nil
+}
+
+// checkUserless returns an error if subscriptions would follow a second user on a channel
+// whose messages don't carry the user. Must be called with subMu held.
+func (ws *WebSocketAPI) checkUserless(subscriptions []Subscription) error {
+	users := make(map[string]string)
+	for _, sub := range ws.subscriptions {
+		if userlessTypes[sub.subscription.Type] {
+			users[sub.subscription.Type] = sub.subscription.User
+		}
+	}
+	for _, subscription := range subscriptions {
+		if !userlessTypes[subscription.Type] {
+			continue
+		}
+		user, ok := users[subscription.Type]
+		if ok && !strings.EqualFold(user, subscription.User) {
+			return APIError{Message: fmt.Sprintf("Already subscribed to %s of user %s on this connection, its messages can't be routed to user %s", subscription.Type, user, subscription.User)}
+		}
+		users[subscription.Type] = subscription.User
+	}
+	return nil
+}
+
+// unsubscribe detaches the subscriber and closes its channel.
+func (ws *WebSocketAPI) unsubscribe(subscriber *WsSubscriber) error {
+	ws.subMu.Lock()
+	defer ws.subMu.Unlock()
+	defer subscriber.close()
+	return ws.detach(subscriber, subscriber.subscriptions)
+}
+
+// detach removes the subscriber from the subscriptions and sends the unsubscribe
+// message for every subscription left without subscribers.
+// Must be called with subMu held.
+func (ws *WebSocketAPI) detach(subscriber *WsSubscriber, subscriptions []Subscription) error {
+	var err error
+	for _, subscription := range subscriptions {
+		key := subscription.key()
+		sub, ok := ws.subscriptions[key]
+		if !ok {
+			continue
+		}
+		delete(sub.subscribers, subscriber.id)
+		if len(sub.subscribers) > 0 {
+			continue
+		}
+		delete(ws.subscriptions, key)
+		if !ws.IsConnected() {
+			continue
+		}
+		if serr := ws.send(WsRequest{Method: "unsubscribe", Subscription: &sub.subscription}); serr != nil && err == nil {
+			err = serr
+		}
+	}
+	return err
+}
+
+// SubscriberCount returns the number of subscribers attached to the subscription.
+func (ws *WebSocketAPI) SubscriberCount(subscription Subscription) int {
+	ws.subMu.RLock()
+	defer ws.subMu.RUnlock()
+	sub, ok := ws.subscriptions[subscription.key()]
+	if !ok {
+		return 0
+	}
+	return len(sub.subscribers)
+}
+
+// Post sends a post request over the websocket and waits for its response.
+// The requestType is either "info" or "action" and the payload is the same
+// object that would be sent to the /info or /exchange endpoint.
+// Returns the raw response payload.
+func (ws *WebSocketAPI) Post(requestType string, payload any) ([]byte, error) {
+	if ws.rateLimiter != nil {
+		// Posts weigh the same as the REST requests
+		endpoint := "info"
+		if requestType == "action" {
+			endpoint = "exchange"
+		}
+		payloadBytes, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		if err := ws.waitRateLimit(endpoint, payloadBytes); err != nil {
+			return nil, err
+		}
+	}
+	id := atomic.AddInt64(&ws.nextID, 1)
+	ch := make(chan *WsPostResponse, 1)
+	ws.mu.Lock()
+	ws.pending[id] = ch
+	ws.mu.Unlock()
+
+	request := WsRequest{
+		Method: "post",
+		ID:     id,
+		Request: &WsPostRequest{
+			Type:    requestType,
+			Payload: payload,
+		},
+	}
+	ws.debug("Websocket post %d: %+v", id, payload)
+	if err := ws.send(request); err != nil {
+		ws.removePending(id)
+		return nil, err
+	}
+
+	select {
+	case response, ok := <-ch:
+		if !ok {
+			return nil, APIError{Message: "Websocket closed before post response"}
+		}
+		ws.debug("Websocket post response %d: %s", id, string(response.Response.Payload))
+		if response.Response.Type == "error" {
+			var message string
+			if err := json.Unmarshal(response.Response.Payload, &message); err != nil {
+				message = string(response.Response.Payload)
+			}
+			return nil, responseError(message)
+		}
+		return response.Response.Payload, nil
+	case <-time.After(ws.PostTimeout):
+		ws.removePending(id)
+		return nil, APIError{Message: fmt.Sprintf("Websocket post %d timed out after %s", id, ws.PostTimeout)}
+	}
+}
+
+// PostAction sends a signed exchange request over the websocket.
+// The response payload is the same as the /exchange endpoint response.
+func (ws *WebSocketAPI) PostAction(request any) ([]byte, error) {
+	return ws.Post("action", request)
+}
+
+// PostInfo sends an info request over the websocket.
+// The response payload is the same as the /info endpoint response.
+func (ws *WebSocketAPI) PostInfo(request any) ([]byte, error) {
+	payload, err := ws.Post("info", request)
+	if err != nil {
+		return nil, err
+	}
+	// Info responses are wrapped as {"type": "...", "data": ...}
+	var wrapped struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &wrapped); err != nil {
+		return nil, err
+	}
+	return wrapped.Data, nil
+}
+
+func (ws *WebSocketAPI) removePending(id int64) {
+	ws.mu.Lock()
+	delete(ws.pending, id)
+	ws.mu.Unlock()
+}
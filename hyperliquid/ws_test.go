@@ -0,0 +1,525 @@
+package hyperliquid
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/websocket"
+)
+
+// newTestWsServer starts a local websocket server that calls handle for every
+// received request and writes back whatever it returns (if not nil).
+func newTestWsServer(t *testing.T, handle func(conn *websocket.Conn, req WsRequest) any) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Upgrade() error = %v", err)
+			return
+		}
+		defer conn.Close()
+		for {
+			var req WsRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			if res := handle(conn, req); res != nil {
+				if err := conn.WriteJSON(res); err != nil {
+					return
+				}
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func GetTestWebSocketAPI(t *testing.T, server *httptest.Server) *WebSocketAPI {
+	ws := NewWebSocketAPI(false)
+	if GLOBAL_DEBUG {
+		ws.SetDebugActive()
+	}
+	ws.url = "ws" + strings.TrimPrefix(server.URL, "http")
+	if err := ws.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	t.Cleanup(func() { ws.Close() })
+	return ws
+}
+
+func postResponse(id int64, responseType string, payload any) map[string]any {
+	return map[string]any{
+		"channel": "post",
+		"data": map[string]any{
+			"id": id,
+			"response": map[string]any{
+				"type":    responseType,
+				"payload": payload,
+			},
+		},
+	}
+}
+
+func TestWebSocketAPI_PostAction(t *testing.T) {
+	server := newTestWsServer(t, func(conn *websocket.Conn, req WsRequest) any {
+		if req.Method != "post" || req.Request == nil || req.Request.Type != "action" {
+			t.Errorf("unexpected request %+v", req)
+			return nil
+		}
+		return postResponse(req.ID, "action", map[string]any{
+			"status":   "ok",
+			"response": map[string]any{"type": "cancel", "data": map[string]any{"statuses": []string{"success"}}},
+		})
+	})
+	ws := GetTestWebSocketAPI(t, server)
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	exchangeAPI := &ExchangeAPI{
+		Client:       *NewClient(false),
+		baseEndpoint: "/exchange",
+		meta:         map[string]AssetInfo{"ETH": {AssetID: 1, SzDecimals: 4}},
+	}
+	if err := exchangeAPI.SetPrivateKey(hex.EncodeToString(crypto.FromECDSA(key))); err != nil {
+		t.Fatal(err)
+	}
+	exchangeAPI.SetWebSocketAPI(ws)
+
+	res, err := exchangeAPI.CancelOrderByOID("ETH", 123)
+	if err != nil {
+		t.Fatalf("CancelOrderByOID() error = %v", err)
+	}
+	if res.Status != "ok" || len(res.Response.Data.Statuses) != 1 || res.Response.Data.Statuses[0].Status != "success" {
+		t.Errorf("CancelOrderByOID() = %+v, want success", res)
+	}
+}
+
+func TestWebSocketAPI_PostError(t *testing.T) {
+	server := newTestWsServer(t, func(conn *websocket.Conn, req WsRequest) any {
+		return postResponse(req.ID, "error", "Invalid request")
+	})
+	ws := GetTestWebSocketAPI(t, server)
+	_, err := ws.PostAction(map[string]any{})
+	if err == nil || err.Error() != "Invalid request" {
+		t.Errorf("PostAction() error = %v, want Invalid request", err)
+	}
+}
+
+func TestWebSocketAPI_PostInfo(t *testing.T) {
+	server := newTestWsServer(t, func(conn *websocket.Conn, req WsRequest) any {
+		return postResponse(req.ID, "info", map[string]any{
+			"type": "allMids",
+			"data": map[string]string{"BTC": "100000.0"},
+		})
+	})
+	ws := GetTestWebSocketAPI(t, server)
+	data, err := ws.PostInfo(InfoRequest{Type: "allMids"})
+	if err != nil {
+		t.Fatalf("PostInfo() error = %v", err)
+	}
+	var mids map[string]string
+	if err := json.Unmarshal(data, &mids); err != nil {
+		t.Fatal(err)
+	}
+	if mids["BTC"] != "100000.0" {
+		t.Errorf("PostInfo() = %v, want BTC mid", mids)
+	}
+}
+
+func TestWebSocketAPI_SubscriptionFanOut(t *testing.T) {
+	requests := make(chan WsRequest, 10)
+	var serverConn *websocket.Conn
+	connected := make(chan struct{})
+	server := newTestWsServer(t, func(conn *websocket.Conn, req WsRequest) any {
+		if serverConn == nil {
+			serverConn = conn
+			close(connected)
+		}
+		requests <- req
+		return nil
+	})
+	ws := GetTestWebSocketAPI(t, server)
+	subscription := Subscription{Type: "l2Book", Coin: "BTC"}
+
+	first, err := ws.Subscribe(subscription)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	second, err := ws.Subscribe(subscription)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	other, err := ws.Subscribe(Subscription{Type: "l2Book", Coin: "ETH"})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if count := ws.SubscriberCount(subscription); count != 2 {
+		t.Errorf("SubscriberCount() = %v, want 2", count)
+	}
+	for _, coin := range []string{"BTC", "ETH"} {
+		req := <-requests
+		if req.Method != "subscribe" || req.Subscription.Coin != coin {
+			t.Errorf("request = %+v, want subscribe to %s", req, coin)
+		}
+	}
+
+	<-connected
+	serverConn.WriteJSON(map[string]any{
+		"channel": "l2Book",
+		"data":    map[string]any{"coin": "BTC", "time": 1, "levels": [][]any{{}, {}}},
+	})
+	for _, sub := range []*WsSubscriber{first, second} {
+		msg := <-sub.C()
+		if msg.Channel != "l2Book" || parseWsRouting(msg.Data).Coin != "BTC" {
+			t.Errorf("message = %+v, want BTC l2Book", msg)
+		}
+	}
+	select {
+	case msg := <-other.C():
+		t.Errorf("ETH subscriber received %+v", msg)
+	default:
+	}
+
+	if err := first.Unsubscribe(); err != nil {
+		t.Errorf("Unsubscribe() error = %v", err)
+	}
+	if _, ok := <-first.C(); ok {
+		t.Errorf("channel not closed after Unsubscribe()")
+	}
+	if err := second.Unsubscribe(); err != nil {
+		t.Errorf("Unsubscribe() error = %v", err)
+	}
+	req := <-requests
+	if req.Method != "unsubscribe" || req.Subscription.Coin != "BTC" {
+		t.Errorf("request = %+v, want single unsubscribe from BTC", req)
+	}
+	if count := ws.SubscriberCount(subscription); count != 0 {
+		t.Errorf("SubscriberCount() = %v, want 0", count)
+	}
+}
+
+func TestWebSocketAPI_UnsubscribeBlockedSubscriber(t *testing.T) {
+	subscribed := make(chan *websocket.Conn, 10)
+	server := newTestWsServer(t, func(conn *websocket.Conn, req WsRequest) any {
+		if req.Method == "subscribe" {
+			subscribed <- conn
+		}
+		return nil
+	})
+	ws := GetTestWebSocketAPI(t, server)
+	blocked, err := ws.SubscribeWithOptions(Subscription{Type: "allMids"}, SubscriberOptions{BufferSize: 1, Policy: WsPolicyBlock})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	other, err := ws.Subscribe(Subscription{Type: "l2Book", Coin: "BTC"})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	conn := <-subscribed
+	// The second message blocks the read loop on the full buffer of the subscriber
+	for i := 0; i < 2; i++ {
+		conn.WriteJSON(map[string]any{"channel": "allMids", "data": map[string]any{"mids": map[string]any{}}})
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	// Other subscribers can still subscribe and unsubscribe while the read loop is blocked
+	done := make(chan error)
+	go func() {
+		if _, err := ws.Subscribe(Subscription{Type: "trades", Coin: "ETH"}); err != nil {
+			done <- err
+			return
+		}
+		done <- other.Unsubscribe()
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Unsubscribe() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Unsubscribe() deadlocked with the blocked read loop")
+	}
+	if err := blocked.Unsubscribe(); err != nil {
+		t.Errorf("Unsubscribe() error = %v", err)
+	}
+}
+
+func TestWebSocketAPI_TwoUsers(t *testing.T) {
+	alice := "0x0000000000000000000000000000000000000001"
+	bob := "0x0000000000000000000000000000000000000002"
+	subscribed := make(chan *websocket.Conn, 10)
+	server := newTestWsServer(t, func(conn *websocket.Conn, req WsRequest) any {
+		if req.Method == "subscribe" {
+			subscribed <- conn
+		}
+		return nil
+	})
+	ws := GetTestWebSocketAPI(t, server)
+
+	aliceOrders, err := ws.Subscribe(Subscription{Type: "orderUpdates", User: alice})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	// Order updates don't carry the user, a second user would receive the orders of the first one
+	if _, err := ws.Subscribe(Subscription{Type: "orderUpdates", User: bob}); err == nil {
+		t.Errorf("Subscribe() expected error for the order updates of a second user")
+	}
+	if _, err := ws.SubscribeMany([]Subscription{{Type: "userEvents", User: alice}, {Type: "userEvents", User: bob}}, SubscriberOptions{}); err == nil {
+		t.Errorf("SubscribeMany() expected error for the events of two users")
+	}
+	aliceOrders2, err := ws.Subscribe(Subscription{Type: "orderUpdates", User: strings.ToUpper(alice)})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v for a second subscriber of the same user", err)
+	}
+	aliceFills, err := ws.Subscribe(Subscription{Type: "userFills", User: alice})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	bobFills, err := ws.Subscribe(Subscription{Type: "userFills", User: bob})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	conn := <-subscribed
+	conn.WriteJSON(map[string]any{"channel": "userFills", "data": map[string]any{"user": bob, "fills": []any{}}})
+	conn.WriteJSON(map[string]any{"channel": "orderUpdates", "data": []any{map[string]any{"status": "open"}}})
+	if msg := <-bobFills.C(); msg.Channel != "userFills" {
+		t.Errorf("message = %+v, want the fills of bob", msg)
+	}
+	if msg := <-aliceOrders.C(); msg.Channel != "orderUpdates" {
+		t.Errorf("message = %+v, want the orders of alice", msg)
+	}
+	select {
+	case msg := <-aliceFills.C():
+		t.Errorf("alice received %+v", msg)
+	default:
+	}
+
+	// Once alice is unsubscribed, the orders of bob can be followed
+	aliceOrders.Unsubscribe()
+	aliceOrders2.Unsubscribe()
+	if _, err := ws.Subscribe(Subscription{Type: "orderUpdates", User: bob}); err != nil {
+		t.Errorf("Subscribe() error = %v after alice unsubscribed", err)
+	}
+}
+
+func TestWebSocketAPI_BufferPolicy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		policy   WsBufferPolicy
+		expected []string
+		dropped  uint64
+	}{
+		{
+			name:     "Drop",
+			policy:   WsPolicyDrop,
+			expected: []string{"1", "2"},
+			dropped:  2,
+		},
+		{
+			name:     "Latest",
+			policy:   WsPolicyLatest,
+			expected: []string{"3", "4"},
+			dropped:  2,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			subscriber := &WsSubscriber{
+				ch:     make(chan WsMessage, 2),
+				policy: tc.policy,
+				done:   make(chan struct{}),
+			}
+			for _, data := range []string{"1", "2", "3", "4"} {
+				subscriber.push(WsMessage{Channel: "allMids", Data: json.RawMessage(data)})
+			}
+			for _, expected := range tc.expected {
+				msg := <-subscriber.ch
+				if string(msg.Data) != expected {
+					t.Errorf("message = %s, want %s", msg.Data, expected)
+				}
+			}
+			if subscriber.Dropped() != tc.dropped {
+				t.Errorf("Dropped() = %v, want %v", subscriber.Dropped(), tc.dropped)
+			}
+		})
+	}
+}
+
+func TestWebSocketAPI_BackfillFilter(t *testing.T) {
+	ws := NewWebSocketAPI(false)
+	ws.EnableBackfill(nil)
+	fills := func(tids ...int) *WsMessage {
+		data := WsUserFills{User: "0xABC"}
+		for _, tid := range tids {
+			data.Fills = append(data.Fills, OrderFill{Tid: int64(tid), Time: int64(tid * 1000)})
+		}
+		raw, _ := json.Marshal(data)
+		return &WsMessage{Channel: "userFills", Data: raw}
+	}
+
+	if _, ok := ws.backfill.filter(fills(1, 2)); !ok {
+		t.Errorf("filter() dropped new fills")
+	}
+	if _, ok := ws.backfill.filter(fills(1, 2)); ok {
+		t.Errorf("filter() delivered duplicate fills")
+	}
+	msg, ok := ws.backfill.filter(fills(2, 3))
+	if !ok {
+		t.Fatalf("filter() dropped new fill")
+	}
+	var data WsUserFills
+	json.Unmarshal(msg.Data, &data)
+	if len(data.Fills) != 1 || data.Fills[0].Tid != 3 {
+		t.Errorf("filter() = %+v, want only tid 3", data.Fills)
+	}
+	if since := ws.backfill.since(backfillKey("userFills", "0xabc")); since != 3000 {
+		t.Errorf("since() = %v, want 3000", since)
+	}
+}
+
+func TestWebSocketAPI_ReconnectAndBackfill(t *testing.T) {
+	user := "0x0000000000000000000000000000000000000001"
+	start := time.Now().UnixMilli() + 60000
+	fill := func(tid int) map[string]any {
+		return map[string]any{"coin": "ETH", "px": "2000", "sz": "1", "tid": tid, "time": start + int64(tid)*1000}
+	}
+	var mu sync.Mutex
+	connections := map[*websocket.Conn]int{}
+	server := newTestWsServer(t, func(conn *websocket.Conn, req WsRequest) any {
+		mu.Lock()
+		if _, ok := connections[conn]; !ok {
+			connections[conn] = len(connections) + 1
+		}
+		index := connections[conn]
+		mu.Unlock()
+		if req.Method != "subscribe" {
+			return nil
+		}
+		if index == 1 {
+			// Deliver a fill then drop the connection.
+			conn.WriteJSON(map[string]any{
+				"channel": "userFills",
+				"data":    map[string]any{"user": user, "fills": []any{fill(1)}},
+			})
+			conn.Close()
+			return nil
+		}
+		// The snapshot after the reconnect repeats the first fill.
+		return map[string]any{
+			"channel": "userFills",
+			"data":    map[string]any{"user": user, "isSnapshot": true, "fills": []any{fill(1)}},
+		}
+	})
+	infoAPI := GetTestInfoAPI(t, func(req InfoRequest) any {
+		if req.Type != "userFillsByTime" || req.StartTime != start+1000 {
+			t.Errorf("unexpected info request %+v", req)
+		}
+		return []any{fill(1), fill(2)}
+	})
+
+	ws := GetTestWebSocketAPI(t, server)
+	ws.EnableBackfill(infoAPI)
+	sub, err := ws.Subscribe(Subscription{Type: "userFills", User: user})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	var tids []int64
+	timeout := time.After(5 * time.Second)
+	for len(tids) < 2 {
+		select {
+		case msg := <-sub.C():
+			var data WsUserFills
+			if err := json.Unmarshal(msg.Data, &data); err != nil {
+				t.Fatal(err)
+			}
+			for _, fill := range data.Fills {
+				tids = append(tids, fill.Tid)
+			}
+		case <-timeout:
+			t.Fatalf("received fills %v, want [1 2]", tids)
+		}
+	}
+	if len(tids) != 2 || tids[0] != 1 || tids[1] != 2 {
+		t.Errorf("received fills %v, want [1 2]", tids)
+	}
+	select {
+	case msg := <-sub.C():
+		t.Errorf("unexpected message %s", msg.Data)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestWebSocketAPI_BackfillBeforeFirstEvent(t *testing.T) {
+	user := "0x0000000000000000000000000000000000000001"
+	var mu sync.Mutex
+	connections := map[*websocket.Conn]int{}
+	server := newTestWsServer(t, func(conn *websocket.Conn, req WsRequest) any {
+		mu.Lock()
+		if _, ok := connections[conn]; !ok {
+			connections[conn] = len(connections) + 1
+		}
+		index := connections[conn]
+		mu.Unlock()
+		if req.Method == "subscribe" && index == 1 && req.Subscription.Type == "userFills" {
+			// Drop the connection before any event was delivered
+			conn.Close()
+		}
+		return nil
+	})
+	subscribed := time.Now().UnixMilli()
+	infoAPI := GetTestInfoAPI(t, func(req InfoRequest) any {
+		if req.User != user || req.StartTime != 0 && (req.StartTime < subscribed || req.StartTime > time.Now().UnixMilli()) {
+			t.Errorf("unexpected info request %+v", req)
+		}
+		switch req.Type {
+		case "userFillsByTime":
+			return []any{map[string]any{"coin": "ETH", "px": "2000", "sz": "1", "tid": 1, "time": time.Now().UnixMilli()}}
+		case "historicalOrders":
+			return []any{
+				map[string]any{"order": map[string]any{"coin": "ETH", "oid": 1}, "status": "filled", "statusTimestamp": time.Now().UnixMilli()},
+				// Older than the subscription
+				map[string]any{"order": map[string]any{"coin": "ETH", "oid": 2}, "status": "canceled", "statusTimestamp": subscribed - 1000},
+			}
+		}
+		return []any{}
+	})
+
+	ws := GetTestWebSocketAPI(t, server)
+	ws.EnableBackfill(infoAPI)
+	sub, err := ws.SubscribeMany([]Subscription{
+		{Type: "orderUpdates", User: user},
+		{Type: "userFills", User: user},
+	}, SubscriberOptions{})
+	if err != nil {
+		t.Fatalf("SubscribeMany() error = %v", err)
+	}
+	if since := ws.backfill.since(backfillKey("orderUpdates", user)); since < subscribed {
+		t.Errorf("since() = %v, want the subscription time", since)
+	}
+
+	channels := map[string]int{}
+	timeout := time.After(5 * time.Second)
+	for len(channels) < 2 {
+		select {
+		case msg := <-sub.C():
+			var items []json.RawMessage
+			if msg.Channel == "orderUpdates" && json.Unmarshal(msg.Data, &items) == nil && len(items) != 1 {
+				t.Errorf("orderUpdates = %s, want only the order updated after the subscription", msg.Data)
+			}
+			channels[msg.Channel]++
+		case <-timeout:
+			t.Fatalf("received %v, want the backfilled fills and orders", channels)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package hyperliquid
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// WsRequest is the envelope for every message sent over the websocket.
+//
+//	{"method": "post", "id": 1, "request": {"type": "action", "payload": {...}}}
+//	{"method": "subscribe", "subscription": {"type": "l2Book", "coin": "BTC"}}
+//	{"method": "ping"}
+type WsRequest struct {
+	Method       string         `json:"method"`
+	ID           int64          `json:"id,omitempty"`
+	Request      *WsPostRequest `json:"request,omitempty"`
+	Subscription *Subscription  `json:"subscription,omitempty"`
+}
+
+// WsPostRequest is the body of a "post" request.
+// Type is either "info" or "action" and Payload is the same object
+// that would be sent to the /info or /exchange endpoint.
+type WsPostRequest struct {
+	Type    string `json:"type"`
+	Payload any    `json:"payload"`
+}
+
+// WsMessage is the envelope for every message received over the websocket.
+type WsMessage struct {
+	Channel string          `json:"channel"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// WsPostResponse is the data of a message received on the "post" channel.
+type WsPostResponse struct {
+	ID       int64 `json:"id"`
+	Response struct {
+		Type    string          `json:"type"`
+		Payload json.RawMessage `json:"payload"`
+	} `json:"response"`
+}
+
+// Subscription describes a websocket data feed.
+// Only the fields required by the subscription type must be set.
+// https://hyperliquid.gitbook.io/hyperliquid-docs/for-developers/api/websocket/subscriptions
+//
+//	Subscription{Type: "l2Book", Coin: "BTC"}
+//	Subscription{Type: "userFills", User: "0x..."}
+//	Subscription{Type: "candle", Coin: "ETH", Interval: "1m"}
+type Subscription struct {
+	Type     string `json:"type"`
+	Coin     string `json:"coin,omitempty"`
+	User     string `json:"user,omitempty"`
+	Interval string `json:"interval,omitempty"`
+}
+
+// key identifies the subscription on the connection.
+func (s Subscription) key() string {
+	return strings.Join([]string{s.Type, s.Coin, strings.ToLower(s.User), s.Interval}, "|")
+}
+
+// channel returns the name of the channel the subscription data is delivered on.
+func (s Subscription) channel() string {
+	if s.Type == "userEvents" {
+		return "user"
+	}
+	return s.Type
+}
+
+// userlessTypes are the user subscriptions whose messages don't carry the user.
+// Their messages can't be told apart between users, so a connection accepts a single user for each of them.
+var userlessTypes = map[string]bool{
+	"orderUpdates": true,
+	"userEvents":   true,
+	"notification": true,
+}
+
+// matches reports whether a message received on channel with the given
+// routing fields belongs to this subscription.
+func (s Subscription) matches(channel string, routing wsRouting) bool {
+	if channel != s.channel() && !(s.Type == "activeAssetCtx" && channel == "activeSpotAssetCtx") {
+		return false
+	}
+	if s.Coin != "" && routing.coin() != "" && s.Coin != routing.coin() {
+		return false
+	}
+	if s.User != "" && routing.User != "" && !strings.EqualFold(s.User, routing.User) {
+		return false
+	}
+	if s.Interval != "" && routing.Interval != "" && s.Interval != routing.Interval {
+		return false
+	}
+	return true
+}
+
+// wsRouting holds the fields of a message used to find its subscriptions.
+// Candles use the short "s" and "i" names for coin and interval.
+type wsRouting struct {
+	Coin     string `json:"coin"`
+	Symbol   string `json:"s"`
+	User     string `json:"user"`
+	Interval string `json:"i"`
+}
+
+func (r wsRouting) coin() string {
+	if r.Coin != "" {
+		return r.Coin
+	}
+	return r.Symbol
+}
+
+// parseWsRouting extracts the routing fields from the message data.
+// For array payloads (e.g. trades) the first element is used.
+func parseWsRouting(data json.RawMessage) wsRouting {
+	var routing wsRouting
+	if len(data) > 0 && data[0] == '[' {
+		var items []wsRouting
+		if err := json.Unmarshal(data, &items); err == nil && len(items) > 0 {
+			routing = items[0]
+		}
+		return routing
+	}
+	json.Unmarshal(data, &routing)
+	return routing
+}
+
+// WsBufferPolicy decides what happens when a subscriber's buffer is full.
+type WsBufferPolicy int
+
+const (
+	// WsPolicyBlock blocks the read loop until the subscriber has room.
+	// No message is lost but a slow subscriber stalls every other stream.
+	WsPolicyBlock WsBufferPolicy = iota
+	// WsPolicyDrop drops the new message when the buffer is full.
+	WsPolicyDrop
+	// WsPolicyLatest drops the oldest buffered message to make room for the new one.
+	// Useful for snapshot streams (l2Book, allMids) where only the last value matters.
+	WsPolicyLatest
+)
+
+// SubscriberOptions configures the buffering of a subscriber.
+type SubscriberOptions struct {
+	BufferSize int            // Size of the subscriber channel, WS_SUBSCRIBER_BUFFER if 0
+	Policy     WsBufferPolicy // What to do when the channel is full
+}
+
+// WsActiveAssetCtx is the data of a message received on the "activeAssetCtx" channel for a perpetual.
+type WsActiveAssetCtx struct {
+	Coin string       `json:"coin"`
+	Ctx  PerpAssetCtx `json:"ctx"`
+}
+
+// WsUserFills is the data of a message received on the "userFills" channel.
+type WsUserFills struct {
+	IsSnapshot bool        `json:"isSnapshot,omitempty"`
+	User       string      `json:"user"`
+	Fills      []OrderFill `json:"fills"`
+}
+
+// WsUserNonFundingLedgerUpdates is the data of a message received on the "userNonFundingLedgerUpdates" channel.
+type WsUserNonFundingLedgerUpdates struct {
+	IsSnapshot              bool               `json:"isSnapshot,omitempty"`
+	User                    string             `json:"user"`
+	NonFundingLedgerUpdates []NonFundingUpdate `json:"nonFundingLedgerUpdates"`
+}
+
+// WsFunding is a funding payment received on the "userFundings" channel.
+type WsFunding struct {
+	Time int64 `json:"time"`
+	FundingDelta
+}
+
+// WsUserFundings is the data of a message received on the "userFundings" channel.
+type WsUserFundings struct {
+	IsSnapshot bool        `json:"isSnapshot,omitempty"`
+	User       string      `json:"user"`
+	Fundings   []WsFunding `json:"fundings"`
+}